@@ -498,6 +498,29 @@ func testAcceptance(t *testing.T, when spec.G, it spec.S, packFixturesDir, packP
 						})
 					})
 
+					when("--timestamp", func() {
+						it.Before(func() {
+							h.SkipIf(t, !packSupports(packPath, "build --timestamp"), "--timestamp flag not supported for build")
+						})
+
+						it("reproduces identical digests for unchanged layers", func() {
+							h.Run(t, subjectPack(
+								"build", repoName,
+								"-p", filepath.Join("testdata", "mock_app"),
+								"--timestamp", "2020-01-01T00:00:00Z",
+							))
+							firstID := h.ImageID(t, repoName)
+
+							h.Run(t, subjectPack(
+								"build", repoName,
+								"-p", filepath.Join("testdata", "mock_app"),
+								"--timestamp", "2020-01-01T00:00:00Z",
+							))
+
+							h.AssertEq(t, h.ImageID(t, repoName), firstID)
+						})
+					})
+
 					when("--buildpack", func() {
 						when("the argument is an ID", func() {
 							it("adds the buildpacks to the builder if necessary and runs them", func() {
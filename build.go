@@ -3,17 +3,28 @@ package pack
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/buildpacks/imgutil"
 	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1remote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	gcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/cmd"
@@ -22,8 +33,12 @@ import (
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/buildpack"
+	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/project"
+	"github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/stringset"
 	"github.com/buildpacks/pack/internal/style"
@@ -35,17 +50,45 @@ type Lifecycle interface {
 
 type BuildOptions struct {
 	Image             string              // required
+	AdditionalTags    []string            // extra tags applied to the built image, Image remains the canonical name
+	PreviousImage     string              // restore layer metadata from this image instead of Image, for layer reuse after a rename
 	Builder           string              // required
-	AppPath           string              // defaults to current working directory
+	AppPath           string              // defaults to current working directory, may be a git URL with an optional '#<ref>' fragment or '-' for a tar stream on stdin
 	RunImage          string              // defaults to the best mirror from the builder metadata or AdditionalMirrors
 	AdditionalMirrors map[string][]string // only considered if RunImage is not provided
 	Env               map[string]string
+	ProjectDescriptor project.Descriptor // the build section's include/exclude lists filter the files uploaded from AppPath
+	Volumes           []string           // host paths mounted into the build containers, read-only unless ':rw' is given, not present in the final image
 	Publish           bool
-	NoPull            bool
-	ClearCache        bool
+	NoPull            bool // deprecated: use PullPolicy instead
+	ClearCache        cache.ClearPolicy        // which of the image's caches to clear before building; nothing cleared by default
 	Buildpacks        []string
+	Registry          string // a buildpack registry index, a git URL or local checkout, consulted for '<namespace>/<name>@<version>' buildpacks
 	ProxyConfig       *ProxyConfig // defaults to  environment proxy vars
 	ContainerConfig   ContainerConfig
+	Timeout           time.Duration            // global limit on each lifecycle phase, unlimited if zero
+	PhaseTimeouts     map[string]time.Duration // per-phase overrides of Timeout, keyed by phase name
+	LifecycleBinDir   string                   // defaults to the standard lifecycle binary location
+	Preflight         bool                     // verify the builder is usable before starting the build
+	LogArtifactPath   string                   // write a structured (JSONL) log of the build to this path
+	CacheImage        string                   // cache build layers in a registry image instead of a local volume
+	CacheDir          string                   // cache build layers in a host directory bind mount instead of a named volume
+	CacheVolume       string                   // cache build layers in this named volume instead of the image-derived default
+	PullPolicy        image.PullPolicy         // how builder, run and buildpack package images are refreshed
+	NormalizeModTime  bool                     // zero app archive modification times for reproducible builds, preserved if unset
+	CreationTime      *time.Time               // fixed creation time for exported layers, for reproducible builds; exporter default if nil
+	CreatedAt         *time.Time               // OCI 'created' timestamp recorded in the exported image's config, distinct from layer times; exporter default if nil
+	Workspace         string                   // container path where app sources are mounted and the lifecycle runs, defaults to '/workspace'
+	ReportPath        string                   // write the lifecycle's build report (image reference, digest and buildpacks) to this path
+	Labels            map[string]string        // extra labels stamped onto the exported image; the 'io.buildpacks' namespace is reserved
+	DefaultProcess    string                   // buildpack-defined process type the exporter makes the image's default entrypoint
+	LifecycleImage    string                   // run the lifecycle from this image instead of the one embedded in the builder
+	UID               *int                     // run the lifecycle phases as this user ID instead of the builder's unprivileged user; grants the build that user's access to mounted volumes
+	GID               *int                     // run the lifecycle phases as this group ID instead of the builder's group, with the same caveats as UID
+	Platforms         []string                 // '<os>/<arch>[/<variant>]' manifests picked from multi-arch images; several platforms require Publish and produce a manifest list
+	DetectOnly        bool                     // stop after the detect phase and log the buildpack group that passed detection instead of building an image
+	TrustBuilder      bool                     // run the combined creator phase in a single container instead of separate phase containers; only for builders the user trusts
+	Interactive       bool                     // keep a failed phase's container and its volumes around so /layers and /workspace can be inspected
 }
 
 type ProxyConfig struct {
@@ -59,24 +102,137 @@ type ContainerConfig struct {
 }
 
 func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
+	if len(opts.Platforms) > 1 {
+		return c.buildManifestList(ctx, opts)
+	}
+
 	imageRef, err := c.parseTagReference(opts.Image)
 	if err != nil {
 		return errors.Wrapf(err, "invalid image name '%s'", opts.Image)
 	}
 
-	appPath, err := c.processAppPath(opts.AppPath)
+	additionalTags := make([]string, 0, len(opts.AdditionalTags))
+	for _, tag := range opts.AdditionalTags {
+		tagRef, err := c.parseTagReference(tag)
+		if err != nil {
+			return errors.Wrapf(err, "invalid additional tag '%s'", tag)
+		}
+		additionalTags = append(additionalTags, tagRef.Name())
+	}
+
+	previousImageName := ""
+	if opts.PreviousImage != "" {
+		previousImageRef, err := c.parseTagReference(opts.PreviousImage)
+		if err != nil {
+			return errors.Wrapf(err, "invalid previous image '%s'", opts.PreviousImage)
+		}
+		previousImageName = previousImageRef.Name()
+	}
+
+	sourcePath := opts.AppPath
+	if isGitSource(sourcePath) {
+		clonePath, cleanup, err := c.cloneGitSource(ctx, sourcePath)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		sourcePath = clonePath
+	} else if sourcePath == "-" {
+		appDir, cleanup, err := c.extractStdinSource(os.Stdin)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		sourcePath = appDir
+	}
+
+	appPath, err := c.processAppPath(sourcePath)
 	if err != nil {
 		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
 	}
 
+	appIgnore, err := appSourceMatcher(opts.ProjectDescriptor.Build)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := build.ParseVolumes(opts.Volumes)
+	if err != nil {
+		return err
+	}
+
+	if err := build.ValidateWorkspace(opts.Workspace); err != nil {
+		return err
+	}
+
+	cacheDir := opts.CacheDir
+	if cacheDir != "" {
+		if cacheDir, err = filepath.Abs(cacheDir); err != nil {
+			return errors.Wrapf(err, "invalid cache dir '%s'", opts.CacheDir)
+		}
+	}
+
+	if err := validateCacheVolumeName(opts.CacheVolume); err != nil {
+		return err
+	}
+
+	if err := validateLabels(opts.Labels); err != nil {
+		return err
+	}
+
+	if err := validateDefaultProcess(opts.DefaultProcess); err != nil {
+		return err
+	}
+
+	var registryResolver registry.Resolver
+	if opts.Registry != "" {
+		indexDir := opts.Registry
+		if isGitSource(indexDir) {
+			clonePath, cleanup, err := c.cloneGitSource(ctx, indexDir)
+			if err != nil {
+				return errors.Wrap(err, "fetching buildpack registry index")
+			}
+			defer cleanup()
+			indexDir = clonePath
+		}
+		registryResolver = registry.NewIndexResolver(indexDir)
+	}
+
 	proxyConfig := c.processProxyConfig(opts.ProxyConfig)
 
+	pullPolicy := opts.PullPolicy
+	if opts.NoPull {
+		pullPolicy = image.PullNever
+	}
+
+	var platform *image.Platform
+	if len(opts.Platforms) == 1 {
+		p, err := image.ParsePlatform(opts.Platforms[0])
+		if err != nil {
+			return err
+		}
+		platform = &p
+	}
+
+	if opts.CacheImage != "" {
+		if err := c.validateCacheImageAccess(opts.CacheImage); err != nil {
+			return err
+		}
+	}
+
 	builderRef, err := c.processBuilderName(opts.Builder)
 	if err != nil {
 		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), true, !opts.NoPull)
+	builderName := builderRef.Name()
+	if platform != nil {
+		if builderName, err = c.resolvePlatformImage(builderName, *platform); err != nil {
+			return err
+		}
+	}
+
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderName, true, pullPolicy)
 	if err != nil {
 		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
 	}
@@ -86,10 +242,9 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	runImageName := c.resolveRunImage(opts.RunImage, imageRef.Context().RegistryStr(), bldr.Stack(), opts.AdditionalMirrors)
-	runImage, err := c.validateRunImage(ctx, runImageName, opts.NoPull, opts.Publish, bldr.StackID)
+	runImageName, runImage, err := c.selectRunImage(ctx, opts.RunImage, imageRef.Context().RegistryStr(), bldr.Stack(), opts.AdditionalMirrors, pullPolicy, opts.Publish, bldr.StackID, platform)
 	if err != nil {
-		return errors.Wrapf(err, "invalid run-image '%s'", runImageName)
+		return err
 	}
 
 	var runMixins []string
@@ -97,7 +252,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 
-	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Buildpacks(), bldr.Order(), opts.Buildpacks, opts.NoPull, opts.Publish)
+	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Buildpacks(), bldr.Order(), opts.Buildpacks, registryResolver, pullPolicy, opts.Publish, platform)
 	if err != nil {
 		return err
 	}
@@ -126,23 +281,451 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Errorf("Builder %s is incompatible with this version of pack", style.Symbol(opts.Builder))
 	}
 
-	return c.lifecycle.Execute(ctx, build.LifecycleOptions{
-		AppPath:    appPath,
-		Image:      imageRef,
-		Builder:    ephemeralBuilder,
-		RunImage:   runImageName,
-		ClearCache: opts.ClearCache,
-		Publish:    opts.Publish,
-		HTTPProxy:  proxyConfig.HTTPProxy,
-		HTTPSProxy: proxyConfig.HTTPSProxy,
-		NoProxy:    proxyConfig.NoProxy,
-		Network:    opts.ContainerConfig.Network,
-	})
+	lifecycleImageName := ""
+	if opts.LifecycleImage != "" {
+		lifecycleImage, err := c.imageFetcher.Fetch(ctx, opts.LifecycleImage, !opts.Publish, pullPolicy)
+		if err != nil {
+			return errors.Wrap(err, "fetching lifecycle image")
+		}
+
+		lifecycleDescriptor, err := builder.DescriptorFromImage(lifecycleImage)
+		if err != nil {
+			return errors.Wrapf(err, "invalid lifecycle image %s", style.Symbol(opts.LifecycleImage))
+		}
+
+		if !lifecycleDescriptor.API.PlatformVersion.SupportsVersion(lcPlatformAPIVersion) {
+			return errors.Errorf(
+				"lifecycle image %s (Platform API version %s) is incompatible with builder %s (Platform API version %s)",
+				style.Symbol(opts.LifecycleImage),
+				lifecycleDescriptor.API.PlatformVersion.String(),
+				style.Symbol(opts.Builder),
+				lcPlatformAPIVersion.String(),
+			)
+		}
+
+		lifecycleImageName = lifecycleImage.Name()
+	}
+
+	if opts.Preflight {
+		if err := c.runPreflightCheck(ctx, ephemeralBuilder.Name(), opts.LifecycleBinDir); err != nil {
+			return errors.Wrapf(err, "preflight check for builder %s", style.Symbol(opts.Builder))
+		}
+		c.logger.Debugf("Preflight check passed for builder %s", style.Symbol(opts.Builder))
+	}
+
+	groupPath := ""
+	if opts.DetectOnly {
+		tmpDir, err := ioutil.TempDir("", "pack-detect")
+		if err != nil {
+			return errors.Wrap(err, "create temp dir")
+		}
+		defer os.RemoveAll(tmpDir)
+		groupPath = filepath.Join(tmpDir, "group.toml")
+	}
+
+	if err := c.lifecycle.Execute(ctx, build.LifecycleOptions{
+		AppPath:            appPath,
+		Image:              imageRef,
+		AdditionalTags:     additionalTags,
+		Builder:            ephemeralBuilder,
+		Keychain:           c.keychain,
+		RunImage:           runImageName,
+		PreviousImage:      previousImageName,
+		ClearCache:         opts.ClearCache,
+		Publish:            opts.Publish,
+		HTTPProxy:          proxyConfig.HTTPProxy,
+		HTTPSProxy:         proxyConfig.HTTPSProxy,
+		NoProxy:            proxyConfig.NoProxy,
+		Network:            opts.ContainerConfig.Network,
+		Timeout:            opts.Timeout,
+		PhaseTimeouts:      opts.PhaseTimeouts,
+		LifecycleBinDir:    opts.LifecycleBinDir,
+		LogArtifactPath:    opts.LogArtifactPath,
+		CacheImage:         opts.CacheImage,
+		CacheDir:           cacheDir,
+		CacheVolume:        opts.CacheVolume,
+		NormalizeModTime:   opts.NormalizeModTime,
+		AppIgnore:          appIgnore,
+		Volumes:            volumes,
+		CreationTime:       opts.CreationTime,
+		Workspace:          opts.Workspace,
+		ReportPath:         opts.ReportPath,
+		DetectOnly:         opts.DetectOnly,
+		TrustBuilder:       opts.TrustBuilder,
+		Interactive:        opts.Interactive,
+		GroupPath:          groupPath,
+		DefaultProcessType: opts.DefaultProcess,
+		LifecycleImage:     lifecycleImageName,
+		UID:                opts.UID,
+		GID:                opts.GID,
+	}); err != nil {
+		return err
+	}
+
+	if opts.DetectOnly {
+		group, err := readDetectedGroup(groupPath)
+		if err != nil {
+			return err
+		}
+		c.logger.Info("Detected group:")
+		for _, bp := range group {
+			line := fmt.Sprintf("  %s@%s", bp.ID, bp.Version)
+			if bp.Optional {
+				line += " (optional)"
+			}
+			c.logger.Info(line)
+		}
+		return nil
+	}
+
+	if err := c.applyLabels(ctx, imageRef.Name(), opts.AdditionalTags, opts.Publish, opts.Labels); err != nil {
+		return err
+	}
+
+	if opts.CreatedAt != nil {
+		if err := c.applyCreatedTime(ctx, imageRef.Name(), opts.AdditionalTags, opts.Publish, *opts.CreatedAt); err != nil {
+			return errors.Wrap(err, "setting image creation time")
+		}
+	}
+
+	return nil
+}
+
+// readDetectedGroup parses the group.toml the detector wrote, copied out of the
+// detect container for detect-only builds.
+func readDetectedGroup(path string) ([]dist.BuildpackRef, error) {
+	var group struct {
+		Group []dist.BuildpackRef `toml:"group"`
+	}
+	if _, err := toml.DecodeFile(path, &group); err != nil {
+		return nil, errors.Wrap(err, "reading detected group")
+	}
+	return group.Group, nil
+}
+
+// BuildResult describes the image a successful build produced.
+type BuildResult struct {
+	ImageRef string // canonical, tag-qualified name the image was built as
+	Digest   string // the image's identifier: a digest reference when published, an image ID otherwise
+}
+
+// BuildWithResult runs Build and reports the built image's canonical reference
+// and digest, for callers embedding pack as a library rather than reading the
+// CLI's log output.
+func (c *Client) BuildWithResult(ctx context.Context, opts BuildOptions) (*BuildResult, error) {
+	if err := c.Build(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	imageRef, err := c.parseTagReference(opts.Image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.Image)
+	}
+
+	digest, err := c.ImageDigest(imageRef.Name(), !opts.Publish)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving built image digest")
+	}
+
+	return &BuildResult{ImageRef: imageRef.Name(), Digest: digest}, nil
+}
+
+// buildManifestList runs the build once per requested platform, pushing each
+// platform-specific image under a derived tag, and then pushes a manifest list
+// referencing all of them under the requested image name.
+func (c *Client) buildManifestList(ctx context.Context, opts BuildOptions) error {
+	if !opts.Publish {
+		return errors.Errorf("building for multiple platforms requires %s", style.Symbol("--publish"))
+	}
+
+	imageRef, err := c.parseTagReference(opts.Image)
+	if err != nil {
+		return errors.Wrapf(err, "invalid image name '%s'", opts.Image)
+	}
+
+	platforms := make([]image.Platform, 0, len(opts.Platforms))
+	for _, value := range opts.Platforms {
+		platform, err := image.ParsePlatform(value)
+		if err != nil {
+			return err
+		}
+		platforms = append(platforms, platform)
+	}
+
+	entries := make([]image.IndexEntry, 0, len(platforms))
+	for _, platform := range platforms {
+		platformImageName := fmt.Sprintf("%s:%s", imageRef.Context().Name(), platformTag(imageRef, platform))
+
+		platformOpts := opts
+		platformOpts.Image = platformImageName
+		platformOpts.Platforms = []string{platform.String()}
+		platformOpts.AdditionalTags = nil
+
+		c.logger.Infof("Building %s for platform %s", style.Symbol(opts.Image), style.Symbol(platform.String()))
+		if err := c.Build(ctx, platformOpts); err != nil {
+			return errors.Wrapf(err, "building for platform %s", style.Symbol(platform.String()))
+		}
+
+		platformRef, err := name.ParseReference(platformImageName, name.WeakValidation)
+		if err != nil {
+			return err
+		}
+		img, err := v1remote.Image(platformRef, c.registryOptions()...)
+		if err != nil {
+			return errors.Wrapf(err, "reading built image %s", style.Symbol(platformImageName))
+		}
+		entries = append(entries, image.IndexEntry{Image: img, Platform: platform})
+	}
+
+	index := image.NewManifestList(entries)
+	if err := v1remote.WriteIndex(imageRef, index, c.registryOptions()...); err != nil {
+		return errors.Wrapf(err, "pushing manifest list %s", style.Symbol(opts.Image))
+	}
+	for _, tag := range opts.AdditionalTags {
+		tagRef, err := c.parseTagReference(tag)
+		if err != nil {
+			return errors.Wrapf(err, "invalid additional tag '%s'", tag)
+		}
+		if err := v1remote.WriteIndex(tagRef, index, c.registryOptions()...); err != nil {
+			return errors.Wrapf(err, "pushing manifest list %s", style.Symbol(tag))
+		}
+	}
+
+	c.logger.Infof("Pushed manifest list %s with %d platform image(s)", style.Symbol(opts.Image), len(entries))
+	return nil
+}
+
+// platformTag derives the tag a single platform's image is pushed under while
+// building a manifest list, e.g. 'v1' and linux/arm/v7 become 'v1-linux-arm-v7'.
+func platformTag(imageRef name.Reference, platform image.Platform) string {
+	return imageRef.Identifier() + "-" + strings.Replace(platform.String(), "/", "-", -1)
+}
+
+// applyLabels stamps extra labels onto the exported image after the lifecycle
+// finishes, since the exporter has no label support of its own. The mutated
+// image is saved under the additional tags as well, so every name of the build
+// keeps referencing the same manifest.
+func (c *Client) applyLabels(ctx context.Context, repoName string, additionalNames []string, publish bool, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	img, err := c.imageFetcher.Fetch(ctx, repoName, !publish, image.PullNever)
+	if err != nil {
+		return errors.Wrap(err, "fetching built image to apply labels")
+	}
+	for key, value := range labels {
+		if err := img.SetLabel(key, value); err != nil {
+			return errors.Wrapf(err, "setting label %s", style.Symbol(key))
+		}
+	}
+	return img.Save(additionalNames...)
+}
+
+// applyCreatedTime rewrites the exported image's config so 'created' records
+// the requested time; the exporter stamps a fixed time of its own for
+// reproducibility and offers no override for it. The rewritten image replaces
+// the additional tags as well, so every name of the build keeps referencing
+// the same manifest.
+func (c *Client) applyCreatedTime(ctx context.Context, repoName string, additionalNames []string, publish bool, created time.Time) error {
+	if publish {
+		refs := make([]name.Reference, 0, 1+len(additionalNames))
+		for _, imageName := range append([]string{repoName}, additionalNames...) {
+			ref, err := name.ParseReference(imageName, name.WeakValidation)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, ref)
+		}
+		img, err := v1remote.Image(refs[0], c.registryOptions()...)
+		if err != nil {
+			return errors.Wrap(err, "fetching built image")
+		}
+		if img, err = image.SetCreated(img, created); err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if err := v1remote.Write(ref, img, c.registryOptions()...); err != nil {
+				return errors.Wrapf(err, "pushing image %s", style.Symbol(ref.Name()))
+			}
+		}
+		return nil
+	}
+
+	tag, err := name.NewTag(repoName, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	tarPath, err := c.saveImages(ctx, []string{repoName})
+	if err != nil {
+		return errors.Wrap(err, "saving built image")
+	}
+	defer os.Remove(tarPath)
+
+	img, err := tarball.ImageFromPath(tarPath, &tag)
+	if err != nil {
+		return errors.Wrap(err, "reading built image")
+	}
+	if img, err = image.SetCreated(img, created); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarball.Write(tag, img, pw))
+	}()
+	resp, err := c.docker.ImageLoad(ctx, pr, true)
+	if err != nil {
+		return errors.Wrap(err, "loading built image")
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return err
+	}
+
+	for _, additionalName := range additionalNames {
+		if err := c.docker.ImageTag(ctx, repoName, additionalName); err != nil {
+			return errors.Wrapf(err, "retagging built image as %s", style.Symbol(additionalName))
+		}
+	}
+	return nil
+}
+
+// saveImages exports the named images from the daemon into a temporary tar and returns its path.
+func (c *Client) saveImages(ctx context.Context, imageNames []string) (string, error) {
+	rc, err := c.docker.ImageSave(ctx, imageNames)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	tmp, err := ioutil.TempFile("", "pack-image-save")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, rc); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// processTypePattern matches buildpack-defined process type names such as
+// 'web' or 'worker'.
+var processTypePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateDefaultProcess checks a default process type override before the
+// build starts; whether the type actually exists is only known to the exporter
+// once the buildpacks have run, and it fails listing the defined types.
+func validateDefaultProcess(processType string) error {
+	if processType == "" {
+		return nil
+	}
+	if !processTypePattern.MatchString(processType) {
+		return errors.Errorf("invalid default process type %s: must be lowercase alphanumerics and dashes", style.Symbol(processType))
+	}
+	return nil
+}
+
+// labelKeyPattern enforces the recommended label key syntax: lowercase
+// alphanumerics separated by '.' and '-'.
+var labelKeyPattern = regexp.MustCompile(`^[a-z0-9]+([.-][a-z0-9]+)*$`)
+
+func validateLabels(labels map[string]string) error {
+	for key := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			return errors.Errorf("invalid label key %s: must be lowercase alphanumerics separated by '.' and '-'", style.Symbol(key))
+		}
+		if key == "io.buildpacks" || strings.HasPrefix(key, "io.buildpacks.") {
+			return errors.Errorf("label key %s is reserved for buildpack metadata", style.Symbol(key))
+		}
+	}
+	return nil
+}
+
+// appSourceMatcher translates the project descriptor's include or exclude list into
+// an ignore matcher for the app upload; both lists use gitignore-style patterns. The
+// descriptor is validated on read, so at most one of the lists is set.
+func appSourceMatcher(buildConfig project.Build) (*archive.IgnoreMatcher, error) {
+	if len(buildConfig.Exclude) > 0 {
+		matcher, err := archive.NewIgnoreMatcher(strings.NewReader(strings.Join(buildConfig.Exclude, "\n")))
+		return matcher, errors.Wrap(err, "invalid project descriptor exclude")
+	}
+	if len(buildConfig.Include) > 0 {
+		matcher, err := archive.NewIncludeMatcher(strings.NewReader(strings.Join(buildConfig.Include, "\n")))
+		return matcher, errors.Wrap(err, "invalid project descriptor include")
+	}
+	return nil, nil
 }
 
+// cacheVolumeNamePattern matches Docker's volume naming rules.
+var cacheVolumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// validateCacheVolumeName rejects explicit cache volume names the daemon would refuse
+// before any build phase tries to mount them.
+func validateCacheVolumeName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !cacheVolumeNamePattern.MatchString(name) {
+		return errors.Errorf("invalid cache volume %s: must match %s", style.Symbol(name), style.Symbol(cacheVolumeNamePattern.String()))
+	}
+	return nil
+}
+
+// validateCacheImageAccess ensures the client's keychain holds credentials for the cache
+// image's registry before the build starts, since the lifecycle must push cache layers there.
+func (c *Client) validateCacheImageAccess(cacheImage string) error {
+	ref, err := name.ParseReference(cacheImage, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "invalid cache image name '%s'", cacheImage)
+	}
+
+	auth, err := c.registryKeychain().Resolve(ref.Context().Registry)
+	if err != nil {
+		return errors.Wrapf(err, "resolving credentials for cache image %s", style.Symbol(cacheImage))
+	}
+	if auth == authn.Anonymous {
+		return errors.Errorf("no registry credentials found for cache image %s", style.Symbol(cacheImage))
+	}
+	return nil
+}
+
+// runPreflightCheck verifies that the builder can actually run a detect container before the
+// app context is uploaded: the builder image must be usable by the daemon and the lifecycle's
+// detector binary must be present at the expected location.
+func (c *Client) runPreflightCheck(ctx context.Context, builderName, lifecycleBinDir string) error {
+	if lifecycleBinDir == "" {
+		lifecycleBinDir = build.DefaultLifecycleBinDir
+	}
+	detectorPath := path.Join(lifecycleBinDir, "detector")
+
+	ctr, err := c.docker.ContainerCreate(ctx, &dcontainer.Config{
+		Image:  builderName,
+		Cmd:    []string{detectorPath},
+		Labels: map[string]string{"author": "pack"},
+	}, nil, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "creating detect container")
+	}
+	defer c.docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	if _, err := c.docker.ContainerStatPath(ctx, ctr.ID, detectorPath); err != nil {
+		return errors.Wrapf(err, "lifecycle binary %s is missing from builder", style.Symbol(detectorPath))
+	}
+	return nil
+}
+
+// ErrNoDefaultBuilder is returned when no builder is given and the client has
+// no default builder configured; callers can match it with errors.Cause.
+var ErrNoDefaultBuilder = errors.New("builder is a required parameter if the client has no default builder")
+
 func (c *Client) processBuilderName(builderName string) (name.Reference, error) {
 	if builderName == "" {
-		return nil, errors.New("builder is a required parameter if the client has no default builder")
+		return nil, ErrNoDefaultBuilder
 	}
 	return name.ParseReference(builderName, name.WeakValidation)
 }
@@ -167,11 +750,45 @@ func (c *Client) getBuilder(img imgutil.Image) (*builder.Builder, error) {
 	return bldr, nil
 }
 
-func (c *Client) validateRunImage(context context.Context, name string, noPull bool, publish bool, expectedStack string) (imgutil.Image, error) {
+// selectRunImage resolves and validates the run image. A comma-separated
+// runImage value lists fallbacks: the candidates are tried in order and the
+// first one that is fetchable and matches the builder's stack is selected.
+func (c *Client) selectRunImage(ctx context.Context, runImage, targetRegistry string, stackInfo builder.StackMetadata, additionalMirrors map[string][]string, pullPolicy image.PullPolicy, publish bool, expectedStack string, platform *image.Platform) (string, imgutil.Image, error) {
+	candidates := splitRunImageList(runImage)
+	if len(candidates) <= 1 {
+		runImageName := c.resolveRunImage(runImage, targetRegistry, stackInfo, additionalMirrors)
+		img, err := c.validateRunImage(ctx, runImageName, pullPolicy, publish, expectedStack, platform)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "invalid run-image '%s'", runImageName)
+		}
+		return runImageName, img, nil
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		img, err := c.validateRunImage(ctx, candidate, pullPolicy, publish, expectedStack, platform)
+		if err != nil {
+			c.logger.Warnf("Run image %s is not usable: %s", style.Symbol(candidate), err)
+			lastErr = errors.Wrapf(err, "invalid run-image '%s'", candidate)
+			continue
+		}
+		c.logger.Infof("Selected run image %s", style.Symbol(candidate))
+		return candidate, img, nil
+	}
+	return "", nil, lastErr
+}
+
+func (c *Client) validateRunImage(context context.Context, name string, pullPolicy image.PullPolicy, publish bool, expectedStack string, platform *image.Platform) (imgutil.Image, error) {
 	if name == "" {
 		return nil, errors.New("run image must be specified")
 	}
-	img, err := c.imageFetcher.Fetch(context, name, !publish, !noPull)
+	if platform != nil {
+		var err error
+		if name, err = c.resolvePlatformImage(name, *platform); err != nil {
+			return nil, err
+		}
+	}
+	img, err := c.imageFetcher.Fetch(context, name, !publish, pullPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -179,12 +796,64 @@ func (c *Client) validateRunImage(context context.Context, name string, noPull b
 	if err != nil {
 		return nil, err
 	}
-	if stackID != expectedStack {
-		return nil, fmt.Errorf("run-image stack id '%s' does not match builder stack '%s'", stackID, expectedStack)
+	if !stack.IDsMatch(stackID, expectedStack) {
+		return nil, RunImageStackMismatchError{RunImageStack: stackID, BuilderStack: expectedStack}
 	}
 	return img, nil
 }
 
+// RunImageStackMismatchError reports a run image from a different stack than
+// the builder; callers can match it with errors.Cause.
+type RunImageStackMismatchError struct {
+	RunImageStack string
+	BuilderStack  string
+}
+
+func (e RunImageStackMismatchError) Error() string {
+	return fmt.Sprintf("run-image stack id '%s' does not match builder stack '%s'", e.RunImageStack, e.BuilderStack)
+}
+
+// resolvePlatformImage pins imageName to the digest of the manifest matching platform
+// when the registry serves a manifest list for it. References to single-platform
+// images are returned unchanged, leaving the daemon's own platform handling in place.
+func (c *Client) resolvePlatformImage(imageName string, platform image.Platform) (string, error) {
+	remoteName, err := image.RewriteReference(imageName, c.registryMirrors)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := name.ParseReference(remoteName, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := v1remote.Get(ref, c.registryOptions()...)
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching manifest for image %s", style.Symbol(imageName))
+	}
+
+	if desc.MediaType != gcrtypes.OCIImageIndex && desc.MediaType != gcrtypes.DockerManifestList {
+		return imageName, nil
+	}
+
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return "", errors.Wrapf(err, "reading manifest list for image %s", style.Symbol(imageName))
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return "", errors.Wrapf(err, "reading manifest list for image %s", style.Symbol(imageName))
+	}
+
+	digest, err := image.SelectManifest(manifest, platform)
+	if err != nil {
+		return "", errors.Wrapf(err, "selecting platform for image %s", style.Symbol(imageName))
+	}
+
+	return fmt.Sprintf("%s@%s", ref.Context().Name(), digest), nil
+}
+
 func (c *Client) validateMixins(additionalBuildpacks []dist.Buildpack, bldr *builder.Builder, runImageName string, runMixins []string) error {
 	if err := stack.ValidateMixins(bldr.Image().Name(), bldr.Mixins(), runImageName, runMixins); err != nil {
 		return err
@@ -237,8 +906,8 @@ func assembleAvailableMixins(buildMixins, runMixins []string) []string {
 // by ID then Version.
 func allBuildpacks(builderImage imgutil.Image, additionalBuildpacks []dist.Buildpack) ([]dist.BuildpackDescriptor, error) {
 	var all []dist.BuildpackDescriptor
-	var bpLayers dist.BuildpackLayers
-	if _, err := dist.GetLabel(builderImage, dist.BuildpackLayersLabel, &bpLayers); err != nil {
+	bpLayers, err := dist.BuildpackLayersForImage(builderImage)
+	if err != nil {
 		return nil, err
 	}
 	for id, bps := range bpLayers {
@@ -268,6 +937,25 @@ func allBuildpacks(builderImage imgutil.Image, additionalBuildpacks []dist.Build
 	return all, nil
 }
 
+// extractStdinSource reads a tar stream from the reader into a temp dir and returns its
+// path along with a cleanup function, so piped app sources behave like directories.
+func (c *Client) extractStdinSource(reader io.Reader) (string, func(), error) {
+	tmpDir, err := ioutil.TempDir("", "pack-app-stdin")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "create temp dir")
+	}
+	cleanup := func() {
+		os.RemoveAll(tmpDir)
+	}
+
+	if err := archive.ExtractTar(reader, tmpDir); err != nil {
+		cleanup()
+		return "", nil, errors.Wrap(err, "reading app source from stdin")
+	}
+
+	return tmpDir, cleanup, nil
+}
+
 func (c *Client) processAppPath(appPath string) (string, error) {
 	var (
 		resolvedAppPath string
@@ -381,72 +1069,147 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 // 	----------
 // 	- group:
 //		- A
-func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, declaredBPs []string, noPull bool, publish bool) (fetchedBPs []dist.Buildpack, order dist.Order, err error) {
+//
+//	WITH DECLARED: "X,Y", Z
+// 	----------
+// 	- group:
+//		- X
+//		- Z
+// 	- group:
+//		- Y
+//		- Z
+func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, declaredBPs []string, registryResolver registry.Resolver, pullPolicy image.PullPolicy, publish bool, platform *image.Platform) (fetchedBPs []dist.Buildpack, order dist.Order, err error) {
 	order = dist.Order{{Group: []dist.BuildpackRef{}}}
-	for _, bp := range declaredBPs {
-		locatorType, err := buildpack.GetLocatorType(bp, builderBPs)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		switch locatorType {
-		case buildpack.FromBuilderLocator:
-			switch {
-			case len(order) == 0 || len(order[0].Group) == 0:
-				order = builderOrder
-			case len(order) > 1:
-				// This should only ever be possible if they are using from=builder twice which we don't allow
-				return nil, nil, errors.New("buildpacks from builder can only be defined once")
-			default:
-				newOrder := dist.Order{}
-				groupToAdd := order[0].Group
-				for _, bOrderEntry := range builderOrder {
-					newEntry := dist.OrderEntry{Group: append(groupToAdd, bOrderEntry.Group...)}
-					newOrder = append(newOrder, newEntry)
-				}
+	for _, declaredBP := range declaredBPs {
+		alternatives := strings.Split(declaredBP, ",")
 
-				order = newOrder
-			}
-		case buildpack.IDLocator:
-			id, version := buildpack.ParseIDLocator(bp)
-			order = appendBuildpackToOrder(order, dist.BuildpackInfo{
-				ID:      id,
-				Version: version,
-			})
-		case buildpack.URILocator:
-			err := ensureBPSupport(bp)
+		var infos []dist.BuildpackInfo
+		for _, bp := range alternatives {
+			locatorType, err := buildpack.GetLocatorType(bp, builderBPs)
 			if err != nil {
-				return fetchedBPs, order, errors.Wrapf(err, "checking support")
+				return nil, nil, err
 			}
 
-			blob, err := c.downloader.Download(ctx, bp)
-			if err != nil {
-				return fetchedBPs, order, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(bp))
-			}
+			if locatorType == buildpack.FromBuilderLocator {
+				if len(alternatives) > 1 {
+					return nil, nil, errors.Errorf("%s cannot be combined with other buildpacks in a group", style.Symbol("from=builder"))
+				}
 
-			fetchedBP, err := dist.BuildpackFromRootBlob(blob)
-			if err != nil {
-				return fetchedBPs, order, errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bp))
+				switch {
+				case len(order) == 0 || len(order[0].Group) == 0:
+					order = builderOrder
+				case len(order) > 1:
+					// This should only ever be possible if they are using from=builder twice which we don't allow
+					return nil, nil, errors.New("buildpacks from builder can only be defined once")
+				default:
+					newOrder := dist.Order{}
+					groupToAdd := order[0].Group
+					for _, bOrderEntry := range builderOrder {
+						newEntry := dist.OrderEntry{Group: append(groupToAdd, bOrderEntry.Group...)}
+						newOrder = append(newOrder, newEntry)
+					}
+
+					order = newOrder
+				}
+				continue
 			}
 
-			fetchedBPs = append(fetchedBPs, fetchedBP)
-			order = appendBuildpackToOrder(order, fetchedBP.Descriptor().Info)
-		case buildpack.PackageLocator:
-			mainBP, depBPs, err := extractPackagedBuildpacks(ctx, bp, c.imageFetcher, publish, noPull)
+			info, newlyFetched, err := c.resolveBuildpack(ctx, bp, locatorType, registryResolver, pullPolicy, publish, platform)
 			if err != nil {
-				return fetchedBPs, order, errors.Wrapf(err, "creating from buildpackage %s", style.Symbol(bp))
+				return fetchedBPs, order, err
 			}
 
-			fetchedBPs = append(append(fetchedBPs, mainBP), depBPs...)
-			order = appendBuildpackToOrder(order, mainBP.Descriptor().Info)
+			fetchedBPs = append(fetchedBPs, newlyFetched...)
+			infos = append(infos, info)
+		}
+
+		switch len(infos) {
+		case 0:
+		case 1:
+			order = appendBuildpackToOrder(order, infos[0])
 		default:
-			return nil, nil, fmt.Errorf("invalid buildpack string %s", style.Symbol(bp))
+			order = appendAlternativesToOrder(order, infos)
 		}
 	}
 
 	return fetchedBPs, order, nil
 }
 
+// resolveBuildpack locates a single declared buildpack, downloading or extracting it as needed, and
+// returns its info along with any buildpacks that must be added to the builder.
+func (c *Client) resolveBuildpack(ctx context.Context, bp string, locatorType buildpack.LocatorType, registryResolver registry.Resolver, pullPolicy image.PullPolicy, publish bool, platform *image.Platform) (dist.BuildpackInfo, []dist.Buildpack, error) {
+	switch locatorType {
+	case buildpack.IDLocator:
+		id, version := buildpack.ParseIDLocator(bp)
+		return dist.BuildpackInfo{
+			ID:      id,
+			Version: version,
+		}, nil, nil
+	case buildpack.URILocator:
+		err := ensureBPSupport(bp)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "checking support")
+		}
+
+		blob, err := c.downloader.Download(ctx, bp)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(bp))
+		}
+
+		fetchedBP, err := dist.BuildpackFromRootBlob(blob)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bp))
+		}
+
+		return fetchedBP.Descriptor().Info, []dist.Buildpack{fetchedBP}, nil
+	case buildpack.PackageLocator:
+		packageName := bp
+		if platform != nil {
+			var err error
+			if packageName, err = c.resolvePlatformImage(packageName, *platform); err != nil {
+				return dist.BuildpackInfo{}, nil, err
+			}
+		}
+
+		mainBP, depBPs, err := extractPackagedBuildpacks(ctx, packageName, c.imageFetcher, publish, pullPolicy)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "creating from buildpackage %s", style.Symbol(bp))
+		}
+
+		return mainBP.Descriptor().Info, append([]dist.Buildpack{mainBP}, depBPs...), nil
+	case buildpack.RegistryLocator:
+		if registryResolver == nil {
+			return dist.BuildpackInfo{}, nil, errors.Errorf("resolving buildpack %s requires a registry: use the --buildpack-registry flag", style.Symbol(bp))
+		}
+
+		namespace, bpName, version, err := registry.ParseRef(bp)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, err
+		}
+
+		entry, err := registryResolver.Resolve(namespace, bpName, version)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "resolving buildpack %s from registry", style.Symbol(bp))
+		}
+
+		address := entry.Address
+		if platform != nil {
+			if address, err = c.resolvePlatformImage(address, *platform); err != nil {
+				return dist.BuildpackInfo{}, nil, err
+			}
+		}
+
+		mainBP, depBPs, err := extractPackagedBuildpacks(ctx, address, c.imageFetcher, publish, pullPolicy)
+		if err != nil {
+			return dist.BuildpackInfo{}, nil, errors.Wrapf(err, "creating from buildpackage %s", style.Symbol(entry.Address))
+		}
+
+		return mainBP.Descriptor().Info, append([]dist.Buildpack{mainBP}, depBPs...), nil
+	default:
+		return dist.BuildpackInfo{}, nil, fmt.Errorf("invalid buildpack string %s", style.Symbol(bp))
+	}
+}
+
 func appendBuildpackToOrder(order dist.Order, bpInfo dist.BuildpackInfo) (newOrder dist.Order) {
 	for _, orderEntry := range order {
 		newEntry := orderEntry
@@ -460,6 +1223,24 @@ func appendBuildpackToOrder(order dist.Order, bpInfo dist.BuildpackInfo) (newOrd
 	return newOrder
 }
 
+// appendAlternativesToOrder forks every existing group once per alternative, so that
+// comma-separated buildpacks declared in a single flag detect as an OR-group.
+func appendAlternativesToOrder(order dist.Order, bpInfos []dist.BuildpackInfo) (newOrder dist.Order) {
+	for _, orderEntry := range order {
+		for _, bpInfo := range bpInfos {
+			group := make([]dist.BuildpackRef, 0, len(orderEntry.Group)+1)
+			group = append(group, orderEntry.Group...)
+			group = append(group, dist.BuildpackRef{
+				BuildpackInfo: bpInfo,
+				Optional:      false,
+			})
+			newOrder = append(newOrder, dist.OrderEntry{Group: group})
+		}
+	}
+
+	return newOrder
+}
+
 func ensureBPSupport(bpPath string) (err error) {
 	p := bpPath
 	if paths.IsURI(bpPath) {
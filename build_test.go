@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,16 +24,21 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/heroku/color"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/blob"
+	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/buildpackage"
+	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/dist"
 	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/image"
 	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/internal/project"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/logging"
 	h "github.com/buildpacks/pack/testhelpers"
@@ -225,6 +232,481 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("AdditionalTags option", func() {
+			it("must be valid tag references", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        builderName,
+					AdditionalTags: []string{"not@valid"},
+				}),
+					"invalid additional tag 'not@valid'",
+				)
+			})
+
+			it("lifecycle receives resolved references", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "example.com/some/repo:tag",
+					AdditionalTags: []string{"example.com/some/repo:latest", "example.com/some/repo:v1.2.3"},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.AdditionalTags, []string{
+					"example.com/some/repo:latest",
+					"example.com/some/repo:v1.2.3",
+				})
+			})
+		})
+
+		when("PreviousImage option", func() {
+			it("must be a valid image reference", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       builderName,
+					PreviousImage: "not@valid",
+				}),
+					"invalid previous image 'not@valid'",
+				)
+			})
+
+			it("lifecycle receives the resolved reference", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:       builderName,
+					Image:         "example.com/some/repo:tag",
+					PreviousImage: "example.com/some/previous-repo:tag",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.PreviousImage, "example.com/some/previous-repo:tag")
+			})
+
+			it("defaults to empty, analyzing the image being built", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.PreviousImage, "")
+			})
+		})
+
+		when("ProjectDescriptor option", func() {
+			it("lifecycle receives a matcher for the exclude list", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					ProjectDescriptor: project.Descriptor{
+						Build: project.Build{Exclude: []string{"*.log"}},
+					},
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.AppIgnore)
+				h.AssertEq(t, fakeLifecycle.Opts.AppIgnore.Ignore("app.log", false), true)
+				h.AssertEq(t, fakeLifecycle.Opts.AppIgnore.Ignore("main.go", false), false)
+			})
+
+			it("lifecycle receives a matcher for the include list", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					ProjectDescriptor: project.Descriptor{
+						Build: project.Build{Include: []string{"*.jar"}},
+					},
+				}))
+				h.AssertNotNil(t, fakeLifecycle.Opts.AppIgnore)
+				h.AssertEq(t, fakeLifecycle.Opts.AppIgnore.Ignore("app.jar", false), false)
+				h.AssertEq(t, fakeLifecycle.Opts.AppIgnore.Ignore("main.go", false), true)
+			})
+
+			it("lifecycle receives no matcher when neither list is set", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+				}))
+				h.AssertNil(t, fakeLifecycle.Opts.AppIgnore)
+			})
+		})
+
+		when("Volumes option", func() {
+			it("lifecycle receives binds defaulting to read-only", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Volumes: []string{tmpDir + ":/mounts/certs", tmpDir + ":/mounts/scratch:rw"},
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Volumes, []string{
+					tmpDir + ":/mounts/certs:ro",
+					tmpDir + ":/mounts/scratch:rw",
+				})
+			})
+
+			it("errors when the host path does not exist", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Volumes: []string{filepath.Join(tmpDir, "missing") + ":/mounts/certs"},
+				}),
+					"invalid volume",
+				)
+			})
+
+			it("errors when the target path is reserved for the lifecycle", func() {
+				for _, target := range []string{"/layers", "/cnb/extra", "/workspace/sub"} {
+					h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+						Builder: builderName,
+						Image:   "some/app",
+						Volumes: []string{tmpDir + ":" + target},
+					}),
+						"reserved for the lifecycle",
+					)
+				}
+			})
+
+			it("errors on an unknown mode", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Volumes: []string{tmpDir + ":/mounts/certs:rx"},
+				}),
+					"mode must be 'ro' or 'rw'",
+				)
+			})
+		})
+
+		when("Workspace option", func() {
+			it("lifecycle receives the workspace", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:   builderName,
+					Image:     "some/app",
+					Workspace: "/app",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.Workspace, "/app")
+			})
+
+			it("errors when the path is not absolute", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:   builderName,
+					Image:     "some/app",
+					Workspace: "app",
+				}),
+					"invalid workspace 'app': must be an absolute path",
+				)
+			})
+
+			it("errors when the path is reserved for the lifecycle", func() {
+				for _, workspace := range []string{"/layers", "/layers/sub", "/cnb/app"} {
+					h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+						Builder:   builderName,
+						Image:     "some/app",
+						Workspace: workspace,
+					}),
+						"reserved for the lifecycle",
+					)
+				}
+			})
+		})
+
+		when("CacheVolume option", func() {
+			it("lifecycle receives the volume name", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:     builderName,
+					Image:       "some/app",
+					CacheVolume: "some-volume",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.CacheVolume, "some-volume")
+			})
+
+			it("errors when the name is not a valid volume name", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:     builderName,
+					Image:       "some/app",
+					CacheVolume: "-bad/name",
+				}),
+					"invalid cache volume '-bad/name'",
+				)
+			})
+		})
+
+		when("Labels option", func() {
+			var fakeAppImage *fakes.Image
+
+			it.Before(func() {
+				fakeAppImage = fakes.NewImage("index.docker.io/some/app:latest", "", nil)
+				fakeImageFetcher.LocalImages[fakeAppImage.Name()] = fakeAppImage
+			})
+
+			it("applies the labels to the built image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Labels:  map[string]string{"org.opencontainers.image.revision": "abc123"},
+				}))
+
+				label, err := fakeAppImage.Label("org.opencontainers.image.revision")
+				h.AssertNil(t, err)
+				h.AssertEq(t, label, "abc123")
+				h.AssertEq(t, fakeAppImage.IsSaved(), true)
+			})
+
+			it("saves the labeled image under the additional tags", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					AdditionalTags: []string{"example.com/some/app:extra"},
+					Labels:         map[string]string{"org.opencontainers.image.revision": "abc123"},
+				}))
+
+				h.AssertContains(t, strings.Join(fakeAppImage.SavedNames(), ","), "example.com/some/app:extra")
+			})
+
+			it("errors when a label key is invalid", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Labels:  map[string]string{"Some Key": "some-value"},
+				}),
+					"invalid label key 'Some Key': must be lowercase alphanumerics separated by '.' and '-'",
+				)
+			})
+
+			it("errors when a label key uses the reserved buildpacks namespace", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder: builderName,
+					Image:   "some/app",
+					Labels:  map[string]string{"io.buildpacks.stack.id": "some-value"},
+				}),
+					"label key 'io.buildpacks.stack.id' is reserved for buildpack metadata",
+				)
+			})
+		})
+
+		when("UID and GID options", func() {
+			it("forwards the user override to the lifecycle", func() {
+				uid, gid := 1001, 2002
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					UID:     &uid,
+					GID:     &gid,
+				}))
+				h.AssertEq(t, *fakeLifecycle.Opts.UID, 1001)
+				h.AssertEq(t, *fakeLifecycle.Opts.GID, 2002)
+			})
+
+			it("defaults to no override", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				}))
+				h.AssertNil(t, fakeLifecycle.Opts.UID)
+				h.AssertNil(t, fakeLifecycle.Opts.GID)
+			})
+		})
+
+		when("Platforms option", func() {
+			it("errors when the platform is malformed", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Image:     "some/app",
+					Builder:   builderName,
+					Platforms: []string{"linux"},
+				}), "invalid platform 'linux': must be in the form '<os>/<arch>[/<variant>]'")
+			})
+
+			it("requires publish when several platforms are requested", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Image:     "some/app",
+					Builder:   builderName,
+					Platforms: []string{"linux/amd64", "linux/arm64"},
+				}), "building for multiple platforms requires '--publish'")
+			})
+		})
+
+		when("DetectOnly option", func() {
+			it.Before(func() {
+				fakeLifecycle.ExecuteFn = func(opts build.LifecycleOptions) error {
+					return ioutil.WriteFile(opts.GroupPath, []byte(`
+[[group]]
+  id = "bp.one"
+  version = "1.2.3"
+
+[[group]]
+  id = "bp.two"
+  version = "4.5.6"
+  optional = true
+`), 0644)
+				}
+			})
+
+			it("halts the lifecycle after detection and reports the detected group", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					DetectOnly: true,
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.DetectOnly, true)
+				h.AssertContains(t, outBuf.String(), "Detected group:")
+				h.AssertContains(t, outBuf.String(), "bp.one@1.2.3")
+				h.AssertContains(t, outBuf.String(), "bp.two@4.5.6 (optional)")
+			})
+
+			it("never touches a built image", func() {
+				// The app image is not registered with the fetcher, so applying the
+				// labels would fail if the build got as far as exporting.
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					DetectOnly: true,
+					Labels:     map[string]string{"org.opencontainers.image.revision": "abc123"},
+				}))
+			})
+		})
+
+		when("#BuildWithResult", func() {
+			it("returns the built image's reference and digest", func() {
+				fakeAppImage := fakes.NewImage("index.docker.io/some/app:latest", "", &fakeIdentifier{name: "sha256:abcd1234"})
+				fakeImageFetcher.LocalImages[fakeAppImage.Name()] = fakeAppImage
+
+				result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.ImageRef, "index.docker.io/some/app:latest")
+				h.AssertEq(t, result.Digest, "sha256:abcd1234")
+			})
+
+			it("validates the options like Build", func() {
+				_, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   "not@valid",
+					Builder: builderName,
+				})
+				h.AssertError(t, err, "invalid image name 'not@valid'")
+			})
+
+			it("errors when the built image cannot be found", func() {
+				_, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   "some/other-app",
+					Builder: builderName,
+				})
+				h.AssertError(t, err, "resolving built image digest")
+			})
+		})
+
+		when("DefaultProcess option", func() {
+			it("lifecycle receives the process type", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					DefaultProcess: "worker",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.DefaultProcessType, "worker")
+			})
+
+			it("errors when the process type is not a valid name", func() {
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					DefaultProcess: "Not Valid",
+				}),
+					"invalid default process type 'Not Valid': must be lowercase alphanumerics and dashes",
+				)
+			})
+		})
+
+		when("LifecycleImage option", func() {
+			var fakeLifecycleImage *fakes.Image
+
+			it.Before(func() {
+				fakeLifecycleImage = fakes.NewImage("index.docker.io/some/lifecycle-image:latest", "", nil)
+				h.AssertNil(t, fakeLifecycleImage.SetLabel(
+					"io.buildpacks.lifecycle.metadata",
+					`{"version": "0.6.1", "api": {"buildpack": "0.2", "platform": "0.2"}}`,
+				))
+				fakeImageFetcher.LocalImages["some/lifecycle-image"] = fakeLifecycleImage
+			})
+
+			it("lifecycle receives the lifecycle image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					LifecycleImage: "some/lifecycle-image",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.LifecycleImage, "index.docker.io/some/lifecycle-image:latest")
+			})
+
+			it("errors when the lifecycle's platform API is incompatible with the builder", func() {
+				h.AssertNil(t, fakeLifecycleImage.SetLabel(
+					"io.buildpacks.lifecycle.metadata",
+					`{"version": "0.9.0", "api": {"buildpack": "0.2", "platform": "0.9"}}`,
+				))
+
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					LifecycleImage: "some/lifecycle-image",
+				}),
+					"lifecycle image 'some/lifecycle-image' (Platform API version 0.9) is incompatible with builder",
+				)
+			})
+
+			it("errors when the image is missing the lifecycle metadata label", func() {
+				h.AssertNil(t, fakeLifecycleImage.SetLabel("io.buildpacks.lifecycle.metadata", ""))
+
+				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					Builder:        builderName,
+					Image:          "some/app",
+					LifecycleImage: "some/lifecycle-image",
+				}),
+					"missing label 'io.buildpacks.lifecycle.metadata'",
+				)
+			})
+		})
+
+		when("CacheImage option", func() {
+			var prevDockerConfig string
+
+			it.Before(func() {
+				prevDockerConfig = os.Getenv("DOCKER_CONFIG")
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Setenv("DOCKER_CONFIG", prevDockerConfig))
+			})
+
+			when("no registry credentials are available", func() {
+				it.Before(func() {
+					h.AssertNil(t, os.Setenv("DOCKER_CONFIG", tmpDir))
+				})
+
+				it("errors before starting the build", func() {
+					h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    builderName,
+						CacheImage: "some/cache-image",
+					}),
+						"no registry credentials found for cache image 'some/cache-image'",
+					)
+				})
+			})
+
+			when("registry credentials are available", func() {
+				it.Before(func() {
+					configDir, err := ioutil.TempDir(tmpDir, "docker-config")
+					h.AssertNil(t, err)
+					h.AssertNil(t, ioutil.WriteFile(
+						filepath.Join(configDir, "config.json"),
+						[]byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"`+base64.StdEncoding.EncodeToString([]byte("user:password"))+`"}}}`),
+						0666,
+					))
+					h.AssertNil(t, os.Setenv("DOCKER_CONFIG", configDir))
+				})
+
+				it("forwards the cache image to the lifecycle", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    builderName,
+						CacheImage: "some/cache-image",
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.CacheImage, "some/cache-image")
+				})
+			})
+		})
+
 		when("AppDir option", func() {
 			it("defaults to the current working directory", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -286,6 +768,65 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, fakeLifecycle.Opts.AppPath, absPath)
 			})
 
+			when("appPath is '-'", func() {
+				it("reads a tar stream from stdin", func() {
+					tarPath := filepath.Join(tmpDir, "stdin-app.tar")
+					tarFile, err := os.Create(tarPath)
+					h.AssertNil(t, err)
+					tw := tar.NewWriter(tarFile)
+					h.AssertNil(t, tw.WriteHeader(&tar.Header{
+						Name:     "app.js",
+						Mode:     0644,
+						Size:     int64(len("console.log()")),
+						Typeflag: tar.TypeReg,
+					}))
+					_, err = tw.Write([]byte("console.log()"))
+					h.AssertNil(t, err)
+					h.AssertNil(t, tw.Close())
+					h.AssertNil(t, tarFile.Close())
+
+					stdin, err := os.Open(tarPath)
+					h.AssertNil(t, err)
+					defer stdin.Close()
+					oldStdin := os.Stdin
+					os.Stdin = stdin
+					defer func() { os.Stdin = oldStdin }()
+
+					var appContents []byte
+					fakeLifecycle.ExecuteFn = func(opts build.LifecycleOptions) error {
+						appContents, err = ioutil.ReadFile(filepath.Join(opts.AppPath, "app.js"))
+						return err
+					}
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						AppPath: "-",
+					}))
+					h.AssertEq(t, string(appContents), "console.log()")
+				})
+
+				it("fails when stdin is not a tar stream", func() {
+					stdinPath := filepath.Join(tmpDir, "not-a-tar")
+					h.AssertNil(t, ioutil.WriteFile(stdinPath, []byte("just some text"), 0644))
+
+					stdin, err := os.Open(stdinPath)
+					h.AssertNil(t, err)
+					defer stdin.Close()
+					oldStdin := os.Stdin
+					os.Stdin = stdin
+					defer func() { os.Stdin = oldStdin }()
+
+					err = subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						AppPath: "-",
+					})
+
+					h.AssertError(t, err, "reading app source from stdin: invalid tar archive")
+				})
+			})
+
 			when("appDir is a symlink", func() {
 				var (
 					appDirName     = "some-app"
@@ -361,11 +902,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		when("Builder option", func() {
 			it("builder is required", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				err := subject.Build(context.TODO(), BuildOptions{
 					Image: "some/app",
-				}),
-					"invalid builder ''",
-				)
+				})
+				h.AssertError(t, err, "invalid builder ''")
+				h.AssertSameInstance(t, errors.Cause(err), ErrNoDefaultBuilder)
 			})
 
 			when("the builder name is provided", func() {
@@ -458,13 +999,67 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("errors", func() {
-					h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					err := subject.Build(context.TODO(), BuildOptions{
 						Image:    "some/app",
 						Builder:  builderName,
 						RunImage: "custom/run",
-					}),
+					})
+					h.AssertError(t, err,
 						"invalid run-image 'custom/run': run-image stack id 'other.stack' does not match builder stack 'some.stack.id'",
 					)
+
+					stackErr, ok := errors.Cause(err).(RunImageStackMismatchError)
+					h.AssertEq(t, ok, true)
+					h.AssertEq(t, stackErr.RunImageStack, "other.stack")
+					h.AssertEq(t, stackErr.BuilderStack, "some.stack.id")
+				})
+			})
+
+			when("run image is a comma-separated fallback list", func() {
+				it("uses the first candidate that is pullable and matches the stack", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:    "some/app",
+						Builder:  builderName,
+						RunImage: "missing/run,custom/run",
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.RunImage, "custom/run")
+					h.AssertContains(t, outBuf.String(), "Run image 'missing/run' is not usable")
+					h.AssertContains(t, outBuf.String(), "Selected run image 'custom/run'")
+				})
+
+				when("an earlier candidate is on a different stack", func() {
+					var otherStackImage *fakes.Image
+
+					it.Before(func() {
+						otherStackImage = fakes.NewImage("other-stack/run", "", nil)
+						h.AssertNil(t, otherStackImage.SetLabel("io.buildpacks.stack.id", "other.stack"))
+						fakeImageFetcher.LocalImages[otherStackImage.Name()] = otherStackImage
+					})
+
+					it.After(func() {
+						otherStackImage.Cleanup()
+					})
+
+					it("skips it and uses the next candidate", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:    "some/app",
+							Builder:  builderName,
+							RunImage: "other-stack/run,custom/run",
+						}))
+						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "custom/run")
+						h.AssertContains(t, outBuf.String(), "Run image 'other-stack/run' is not usable")
+					})
+				})
+
+				when("no candidate is usable", func() {
+					it("errors with the last failure", func() {
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:    "some/app",
+							Builder:  builderName,
+							RunImage: "missing/run,also-missing/run",
+						})
+						h.AssertError(t, err, "invalid run-image 'also-missing/run'")
+					})
 				})
 			})
 
@@ -563,17 +1158,17 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
-					ClearCache: true,
+					ClearCache: cache.ClearAll,
 				}))
-				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, true)
+				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, cache.ClearAll)
 			})
 
-			it("defaults to false", func() {
+			it("defaults to clearing nothing", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 					Image:   "some/app",
 					Builder: builderName,
 				}))
-				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, false)
+				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, cache.ClearNone)
 			})
 		})
 
@@ -600,7 +1195,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
-					ClearCache: true,
+					ClearCache: cache.ClearAll,
 					Buildpacks: []string{additionalBP},
 				}))
 				h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
@@ -618,7 +1213,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{"buildpack.1.id"},
 					}))
 					h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
@@ -637,7 +1232,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							"from=builder:buildpack.1.id@buildpack.1.version",
 						},
@@ -678,7 +1273,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							"from=builder",
 							additionalBP1,
@@ -742,7 +1337,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							additionalBP1,
 							"from=builder",
@@ -807,7 +1402,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							additionalBP1,
 							additionalBP2,
@@ -847,6 +1442,64 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("a comma-separated group of buildpacks is provided", func() {
+				it("forks the order once per alternative", func() {
+					additionalBP := createBuildpackTar(t, tmpDir, dist.BuildpackDescriptor{
+						API: api.MustParse("0.3"),
+						Info: dist.BuildpackInfo{
+							ID:      "buildpack.add.1.id",
+							Version: "buildpack.add.1.version",
+						},
+						Stacks: []dist.Stack{{ID: defaultBuilderStackID}},
+						Order:  nil,
+					})
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    builderName,
+						ClearCache: cache.ClearAll,
+						Buildpacks: []string{
+							"buildpack.1.id@buildpack.1.version,buildpack.2.id@buildpack.2.version",
+							additionalBP,
+						},
+					}))
+
+					assertOrderEquals(`[[order]]
+
+  [[order.group]]
+    id = "buildpack.1.id"
+    version = "buildpack.1.version"
+
+  [[order.group]]
+    id = "buildpack.add.1.id"
+    version = "buildpack.add.1.version"
+
+[[order]]
+
+  [[order.group]]
+    id = "buildpack.2.id"
+    version = "buildpack.2.version"
+
+  [[order.group]]
+    id = "buildpack.add.1.id"
+    version = "buildpack.add.1.version"
+`)
+				})
+
+				it("fails when from=builder is part of a group", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    builderName,
+						ClearCache: cache.ClearAll,
+						Buildpacks: []string{
+							"from=builder,buildpack.1.id@buildpack.1.version",
+						},
+					})
+
+					h.AssertError(t, err, "'from=builder' cannot be combined with other buildpacks in a group")
+				})
+			})
+
 			when("meta-buildpack is used", func() {
 				it("resolves buildpack from builder", func() {
 					buildpackTar := createBuildpackTar(t, tmpDir, dist.BuildpackDescriptor{
@@ -876,7 +1529,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{buildpackTar},
 					})
 
@@ -968,7 +1621,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							"example.com/some/package",
 						},
@@ -1010,7 +1663,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							"example.com/some/package",
 						},
@@ -1025,7 +1678,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
-						ClearCache: true,
+						ClearCache: cache.ClearAll,
 						Buildpacks: []string{
 							"example.com/some/package",
 						},
@@ -1039,7 +1692,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
-					ClearCache: true,
+					ClearCache: cache.ClearAll,
 					Buildpacks: []string{"missing.bp@version"},
 				}),
 					"invalid buildpack string 'missing.bp@version'",
@@ -1066,7 +1719,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
-							ClearCache: true,
+							ClearCache: cache.ClearAll,
 							Buildpacks: []string{
 								"buildpack.1.id@buildpack.1.version",
 								filepath.Join("testdata", "buildpack"),
@@ -1080,7 +1733,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
-							ClearCache: true,
+							ClearCache: cache.ClearAll,
 							Buildpacks: []string{
 								"buildpack.1.id@buildpack.1.version",
 								buildpackTgz,
@@ -1123,7 +1776,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
-							ClearCache: true,
+							ClearCache: cache.ClearAll,
 							Buildpacks: []string{
 								"buildpack.1.id@buildpack.1.version",
 								"buildpack.2.id@buildpack.2.version",
@@ -1175,7 +1828,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
-							ClearCache: true,
+							ClearCache: cache.ClearAll,
 							Buildpacks: []string{
 								"buildpack.1.id@buildpack.1.version",
 								"buildpack.2.id@buildpack.2.version",
@@ -1281,11 +1934,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 					})
 				})
 			})
@@ -1301,11 +1954,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, false)
+						h.AssertEq(t, args.PullPolicy, image.PullNever)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, false)
+						h.AssertEq(t, args.PullPolicy, image.PullNever)
 					})
 				})
 
@@ -1319,11 +1972,31 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
+
+						args = fakeImageFetcher.FetchCalls[builderName]
+						h.AssertEq(t, args.Daemon, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
+					})
+				})
+			})
+
+			when("PullPolicy option", func() {
+				when("if-not-present", func() {
+					it("uses the local builder and run images if they are present", func() {
+						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    builderName,
+							PullPolicy: image.PullIfNotPresent,
+						}))
+
+						args := fakeImageFetcher.FetchCalls["default/run"]
+						h.AssertEq(t, args.Daemon, true)
+						h.AssertEq(t, args.PullPolicy, image.PullIfNotPresent)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullIfNotPresent)
 					})
 				})
 			})
@@ -57,8 +57,18 @@ type StackConfig struct {
 }
 
 type LifecycleConfig struct {
-	URI     string `toml:"uri"`
-	Version string `toml:"version"`
+	URI      string             `toml:"uri"`
+	Version  string             `toml:"version"`
+	Checksum string             `toml:"checksum"`
+	API      LifecycleAPIConfig `toml:"api"`
+}
+
+// LifecycleAPIConfig declares the inclusive range of Buildpack API versions
+// the builder's buildpacks may use. Empty bounds leave that side of the
+// range open.
+type LifecycleAPIConfig struct {
+	BuildpackMin string `toml:"buildpack-min"`
+	BuildpackMax string `toml:"buildpack-max"`
 }
 
 // ReadConfig reads a builder configuration from the file path provided and returns the
@@ -101,6 +101,14 @@ func (r *ConfigReader) Read(path string) (Config, error) {
 				style.Symbol("image"),
 			)
 		}
+
+		if dep.URI == "" && dep.ImageName == "" {
+			return config, errors.Errorf(
+				"dependency configured with neither %s nor %s",
+				style.Symbol("uri"),
+				style.Symbol("image"),
+			)
+		}
 	}
 
 	return config, nil
@@ -174,6 +174,19 @@ func testBuildpackageConfigReader(t *testing.T, when spec.G, it spec.S) {
 			h.AssertError(t, err, "dependency configured with both 'uri' and 'image'")
 		})
 
+		it("returns an error when a dependency has no options configured", func() {
+			configFile := filepath.Join(tmpDir, "package.toml")
+
+			err := ioutil.WriteFile(configFile, []byte(emptyDependencyPackageToml), os.ModePerm)
+			h.AssertNil(t, err)
+
+			packageConfigReader := buildpackage.NewConfigReader()
+
+			_, err = packageConfigReader.Read(configFile)
+			h.AssertNotNil(t, err)
+			h.AssertError(t, err, "dependency configured with neither 'uri' nor 'image'")
+		})
+
 		it("returns an error no buildpack is configured", func() {
 			configFile := filepath.Join(tmpDir, "package.toml")
 
@@ -256,6 +269,13 @@ uri = "bp/b"
 image = "some/package-dep"
 `
 
+const emptyDependencyPackageToml = `
+[buildpack]
+uri = "noop-buildpack.tgz"
+
+[[dependencies]]
+`
+
 const missingBuildpackPackageToml = `
 [[dependencies]]
 uri = "bp/b"
@@ -0,0 +1,291 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	v1remote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/buildpackage"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// BundleManifestFile is the name of the manifest entry within a bundle archive.
+	BundleManifestFile = "bundle.toml"
+)
+
+type BundleOptions struct {
+	BuilderImage string // required
+	Output       string // required
+}
+
+type LoadBundleOptions struct {
+	Path string // required
+}
+
+// BundleManifest describes the contents of a bundle archive.
+type BundleManifest struct {
+	Builder       string          `toml:"builder"`
+	RunImages     []string        `toml:"run-images"`
+	Lifecycle     BundleLifecycle `toml:"lifecycle"`
+	SchemaVersion string          `toml:"schema-version"`
+}
+
+type BundleLifecycle struct {
+	Version string `toml:"version"`
+}
+
+const bundleSchemaVersion = "0.1"
+
+// Bundle collects a builder image, its run images and mirrors, into a single
+// OCI-layout archive that can be transferred to an air-gapped host and loaded
+// with LoadBundle. The images are copied straight from their registries, so no
+// daemon is involved.
+func (c *Client) Bundle(ctx context.Context, opts BundleOptions) error {
+	builderRef, err := c.processBuilderName(opts.BuilderImage)
+	if err != nil {
+		return errors.Wrapf(err, "invalid builder '%s'", opts.BuilderImage)
+	}
+
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), false, image.PullAlways)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
+	}
+
+	bldr, err := c.getBuilder(rawBuilderImage)
+	if err != nil {
+		return errors.Wrapf(err, "invalid builder '%s'", opts.BuilderImage)
+	}
+
+	manifest := BundleManifest{
+		Builder:       builderRef.Name(),
+		Lifecycle:     BundleLifecycle{Version: bldr.LifecycleDescriptor().Info.Version.String()},
+		SchemaVersion: bundleSchemaVersion,
+	}
+
+	layoutDir, err := ioutil.TempDir("", "pack-bundle-layout")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(layoutDir)
+
+	layoutWriter, err := buildpackage.NewImageLayoutWriter(layoutDir)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle layout")
+	}
+
+	if err := c.appendBundleImage(layoutWriter, builderRef.Name()); err != nil {
+		return errors.Wrapf(err, "bundling builder image %s", style.Symbol(builderRef.Name()))
+	}
+
+	runImages := append([]string{bldr.Stack().RunImage.Image}, bldr.Stack().RunImage.Mirrors...)
+	for _, runImageName := range runImages {
+		if err := c.appendBundleImage(layoutWriter, runImageName); err != nil {
+			c.logger.Warnf("Skipping run image %s: %s", style.Symbol(runImageName), err)
+			continue
+		}
+		manifest.RunImages = append(manifest.RunImages, runImageName)
+	}
+
+	if err := layoutWriter.Finish(); err != nil {
+		return errors.Wrap(err, "writing bundle layout index")
+	}
+
+	if err := writeBundleArchive(opts.Output, manifest, layoutDir); err != nil {
+		return errors.Wrapf(err, "writing bundle to '%s'", opts.Output)
+	}
+
+	c.logger.Infof("Successfully bundled builder %s", style.Symbol(builderRef.Name()))
+	return nil
+}
+
+// appendBundleImage copies the named image from its registry into the bundle's
+// OCI layout.
+func (c *Client) appendBundleImage(layoutWriter *buildpackage.ImageLayoutWriter, imageName string) error {
+	remoteName, err := image.RewriteReference(imageName, c.registryMirrors)
+	if err != nil {
+		return err
+	}
+	ref, err := name.ParseReference(remoteName, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+	img, err := v1remote.Image(ref, c.registryOptions()...)
+	if err != nil {
+		return err
+	}
+	return layoutWriter.AppendImage(img, imageName)
+}
+
+// LoadBundle loads the images from a bundle archive created by Bundle into the daemon.
+func (c *Client) LoadBundle(ctx context.Context, opts LoadBundleOptions) error {
+	layoutDir, err := ioutil.TempDir("", "pack-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(layoutDir)
+
+	manifest, err := extractBundle(opts.Path, layoutDir)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return errors.Errorf("bundle '%s' is missing a %s manifest", opts.Path, BundleManifestFile)
+	}
+
+	layoutPath, err := layout.FromPath(layoutDir)
+	if err != nil {
+		return errors.Errorf("bundle '%s' contains no OCI layout", opts.Path)
+	}
+	index, err := layoutPath.ImageIndex()
+	if err != nil {
+		return errors.Wrapf(err, "reading bundle '%s'", opts.Path)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return errors.Wrapf(err, "reading bundle '%s'", opts.Path)
+	}
+	if len(indexManifest.Manifests) == 0 {
+		return errors.Errorf("bundle '%s' contains no images", opts.Path)
+	}
+
+	for _, desc := range indexManifest.Manifests {
+		refName := desc.Annotations[buildpackage.RefNameAnnotation]
+		if refName == "" {
+			return errors.Errorf("bundle '%s' contains an image without a reference name", opts.Path)
+		}
+		img, err := index.Image(desc.Digest)
+		if err != nil {
+			return errors.Wrapf(err, "reading image %s from bundle", style.Symbol(refName))
+		}
+		if err := c.loadImage(ctx, img, refName); err != nil {
+			return errors.Wrapf(err, "loading image %s", style.Symbol(refName))
+		}
+	}
+
+	c.logger.Infof("Successfully loaded builder %s", style.Symbol(manifest.Builder))
+	for _, runImageName := range manifest.RunImages {
+		c.logger.Infof("Successfully loaded run image %s", style.Symbol(runImageName))
+	}
+	return nil
+}
+
+// loadImage imports img into the daemon under refName.
+func (c *Client) loadImage(ctx context.Context, img v1.Image, refName string) error {
+	tag, err := name.NewTag(refName, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarball.Write(tag, img, pw))
+	}()
+	resp, err := c.docker.ImageLoad(ctx, pr, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
+
+// extractBundle unpacks the bundle archive's OCI layout into layoutDir and
+// returns the parsed manifest, or nil if the archive holds none.
+func extractBundle(bundlePath, layoutDir string) (*BundleManifest, error) {
+	fh, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening bundle '%s'", bundlePath)
+	}
+	defer fh.Close()
+
+	var manifest *BundleManifest
+
+	tr := tar.NewReader(fh)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading bundle '%s'", bundlePath)
+		}
+
+		entryName := filepath.ToSlash(filepath.Clean(header.Name))
+		if entryName == BundleManifestFile {
+			manifest = &BundleManifest{}
+			if _, err := toml.DecodeReader(tr, manifest); err != nil {
+				return nil, errors.Wrap(err, "parsing bundle manifest")
+			}
+			continue
+		}
+		if strings.HasPrefix(entryName, "..") || filepath.IsAbs(entryName) {
+			return nil, errors.Errorf("bundle '%s' contains invalid entry '%s'", bundlePath, header.Name)
+		}
+
+		dest := filepath.Join(layoutDir, entryName)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return nil, err
+			}
+			contents, err := os.Create(dest)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(contents, tr)
+			if closeErr := contents.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+func writeBundleArchive(outputPath string, manifest BundleManifest, layoutDir string) error {
+	fh, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	tw := tar.NewWriter(fh)
+	defer tw.Close()
+
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(manifest); err != nil {
+		return errors.Wrap(err, "encoding bundle manifest")
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: BundleManifestFile, Size: int64(buf.Len()), Mode: 0644}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return archive.WriteDirToTar(tw, layoutDir, "", 0, 0, -1, true)
+}
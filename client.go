@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/buildpacks/imgutil"
 	dockerClient "github.com/docker/docker/client"
@@ -20,9 +21,9 @@ import (
 //go:generate mockgen -package testmocks -destination testmocks/mock_image_fetcher.go github.com/buildpacks/pack ImageFetcher
 type ImageFetcher interface {
 	// Fetch fetches an image by resolving it both remotely and locally depending on provided parameters.
-	// If daemon is true, it will look return a `local.Image`. Pull, applicable only when daemon is true, will
-	// attempt to pull a remote image first.
-	Fetch(ctx context.Context, name string, daemon, pull bool) (imgutil.Image, error)
+	// If daemon is true, it will look return a `local.Image`. The pull policy, applicable only when daemon
+	// is true, controls whether a remote image is pulled first.
+	Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error)
 }
 
 //go:generate mockgen -package testmocks -destination testmocks/mock_downloader.go github.com/buildpacks/pack Downloader
@@ -36,12 +37,19 @@ type ImageFactory interface {
 }
 
 type Client struct {
-	logger       logging.Logger
-	imageFetcher ImageFetcher
-	downloader   Downloader
-	lifecycle    Lifecycle
-	docker       dockerClient.CommonAPIClient
-	imageFactory ImageFactory
+	logger              logging.Logger
+	imageFetcher        ImageFetcher
+	downloader          Downloader
+	lifecycle           Lifecycle
+	docker              dockerClient.CommonAPIClient
+	imageFactory        ImageFactory
+	blobCacheDisabled   bool
+	downloadTimeout     time.Duration
+	registryMirrors     map[string]string
+	insecureRegistries  []string
+	registryAuth        string
+	credHelpersDisabled bool
+	keychain            authn.Keychain
 }
 
 type ClientOption func(c *Client)
@@ -83,6 +91,61 @@ func WithCacheDir(path string) ClientOption {
 	}
 }
 
+// WithBlobCacheDisabled download blobs on every invocation instead of reusing cached artifacts.
+// It has no effect when a custom downloader is supplied.
+func WithBlobCacheDisabled() ClientOption {
+	return func(c *Client) {
+		c.blobCacheDisabled = true
+	}
+}
+
+// WithDownloadTimeout override how long a blob download may stall without
+// receiving data before it fails. It has no effect when a custom downloader
+// is supplied.
+func WithDownloadTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.downloadTimeout = timeout
+	}
+}
+
+// WithRegistryMirrors route remote image traffic through per-registry
+// pull-through mirrors, e.g. 'docker.io' -> 'registry.internal'. It has no
+// effect when a custom fetcher or image factory is supplied.
+func WithRegistryMirrors(mirrors map[string]string) ClientOption {
+	return func(c *Client) {
+		c.registryMirrors = mirrors
+	}
+}
+
+// WithInsecureRegistries treat the given registry hosts as insecure, talking
+// to them over plain HTTP. It has no effect when a custom image factory is
+// supplied, and never affects images in the docker daemon.
+func WithInsecureRegistries(registries []string) ClientOption {
+	return func(c *Client) {
+		c.insecureRegistries = registries
+	}
+}
+
+// WithRegistryAuth resolve registry credentials from a base64-encoded JSON
+// map of 'registry' to 'authorization header' instead of the docker config
+// file, which remains the fallback for registries absent from the map. It has
+// no effect when a custom fetcher or image factory is supplied.
+func WithRegistryAuth(encodedAuth string) ClientOption {
+	return func(c *Client) {
+		c.registryAuth = encodedAuth
+	}
+}
+
+// WithCredHelpersDisabled resolve registry credentials only from entries
+// stored directly in the docker config file, never invoking configured
+// credential helpers. It has no effect when a custom fetcher or image factory
+// is supplied.
+func WithCredHelpersDisabled() ClientOption {
+	return func(c *Client) {
+		c.credHelpersDisabled = true
+	}
+}
+
 // WithDockerClient supply your own docker client.
 func WithDockerClient(docker dockerClient.CommonAPIClient) ClientOption {
 	return func(c *Client) {
@@ -114,15 +177,50 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "getting pack home")
 		}
-		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"))
+		var downloadOpts []blob.DownloaderOption
+		if client.blobCacheDisabled {
+			downloadOpts = append(downloadOpts, blob.WithCacheDisabled())
+		}
+		if client.downloadTimeout > 0 {
+			downloadOpts = append(downloadOpts, blob.WithDownloadTimeout(client.downloadTimeout))
+		}
+		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"), downloadOpts...)
 	}
 
-	if client.imageFetcher == nil {
-		client.imageFetcher = image.NewFetcher(client.logger, client.docker)
+	keychain := authn.Keychain(authn.DefaultKeychain)
+	if client.credHelpersDisabled {
+		keychain = image.NewConfigFileKeychain()
 	}
+	if client.registryAuth != "" {
+		var err error
+		keychain, err = image.NewStaticKeychain(client.registryAuth, keychain)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing registry auth")
+		}
+	}
+	keychain = image.NewDescribedKeychain(keychain)
+	client.keychain = keychain
 
 	if client.imageFactory == nil {
-		client.imageFactory = image.NewFactory(client.docker, authn.DefaultKeychain)
+		client.imageFactory = image.NewFactory(
+			client.docker,
+			keychain,
+			image.WithRegistryMirrors(client.registryMirrors),
+			image.WithInsecureRegistries(client.insecureRegistries),
+			image.WithProgressReporting(client.logger),
+		)
+	}
+
+	if client.imageFetcher == nil {
+		fetcherOpts := []image.FetcherOption{
+			image.WithMirrors(client.registryMirrors),
+			image.WithKeychain(keychain),
+		}
+		if factory, ok := client.imageFactory.(*image.DefaultImageFactory); ok {
+			// the factory's insecure-registry handling applies to fetches too
+			fetcherOpts = append(fetcherOpts, image.WithFetchTransport(factory.Transport()))
+		}
+		client.imageFetcher = image.NewFetcher(client.logger, client.docker, fetcherOpts...)
 	}
 
 	client.lifecycle = build.NewLifecycle(client.docker, client.logger)
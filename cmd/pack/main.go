@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use: "pack",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			noBlobCache := false
 			if fs := cmd.Flags(); fs != nil {
 				if flag, err := fs.GetBool("no-color"); err == nil {
 					color.Disable(flag)
@@ -42,19 +44,72 @@ func main() {
 				if flag, err := fs.GetBool("verbose"); err == nil {
 					logger.WantVerbose(flag)
 				}
+				if fs.Changed("log-level") {
+					if flag, err := fs.GetString("log-level"); err == nil {
+						if err := logger.WantLevel(flag); err != nil {
+							exitError(logger, err)
+						}
+					}
+				}
 				if flag, err := fs.GetBool("timestamps"); err == nil {
 					logger.WantTime(flag)
 				}
+				if flag, err := fs.GetBool("log-timestamps"); err == nil {
+					logger.WantLineTimestamps(flag)
+				}
+				if flag, err := fs.GetBool("no-blob-cache"); err == nil {
+					noBlobCache = flag
+				}
+				if flag, err := fs.GetString("log-format"); err == nil {
+					if err := logger.WantFormat(flag); err != nil {
+						exitError(logger, err)
+					}
+				}
+			}
+
+			insecureRegistries := cfg.InsecureRegistries
+			if fs := cmd.Flags(); fs != nil {
+				if flag, err := fs.GetStringArray("insecure-registry"); err == nil {
+					insecureRegistries = append(insecureRegistries, flag...)
+				}
+			}
+
+			registryAuth := os.Getenv("PACK_REGISTRY_AUTH")
+			if fs := cmd.Flags(); fs != nil && fs.Changed("registry-auth") {
+				if flag, err := fs.GetString("registry-auth"); err == nil {
+					registryAuth = flag
+				}
 			}
 
-			packClient = initClient(logger)
+			var downloadTimeout time.Duration
+			if cfg.DownloadTimeout != "" {
+				var err error
+				downloadTimeout, err = time.ParseDuration(cfg.DownloadTimeout)
+				if err != nil {
+					exitError(logger, errors.Wrap(err, "parsing download-timeout from config"))
+				}
+			}
+			if fs := cmd.Flags(); fs != nil && fs.Changed("download-timeout") {
+				if flag, err := fs.GetDuration("download-timeout"); err == nil {
+					downloadTimeout = flag
+				}
+			}
+
+			packClient = initClient(logger, noBlobCache, downloadTimeout, registryAuth, cfg.RegistryMirrors, insecureRegistries)
 		},
 	}
 
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color output")
+	rootCmd.PersistentFlags().Bool("no-blob-cache", false, "Skip caching downloaded buildpack and lifecycle archives")
 	rootCmd.PersistentFlags().Bool("timestamps", false, "Enable timestamps in output")
+	rootCmd.PersistentFlags().Bool("log-timestamps", false, "Prefix every log line, including lifecycle phase output, with an RFC3339 timestamp")
+	rootCmd.PersistentFlags().String("log-format", "text", "Output format for pack's logs ('text' or 'json')")
+	rootCmd.PersistentFlags().String("log-level", "info", "Minimum level that is logged ('debug', 'info', 'warn' or 'error'),\n  overriding --quiet and --verbose")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Show less output")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Show more output")
+	rootCmd.PersistentFlags().StringArray("insecure-registry", nil, "Registry host to access over plain HTTP, repeat for each registry")
+	rootCmd.PersistentFlags().Duration("download-timeout", 0, "Fail a stalled buildpack or lifecycle download after receiving no data for this long, e.g. '30s' (default from config)")
+	rootCmd.PersistentFlags().String("registry-auth", "", "Base64-encoded JSON map of registry to auth header, e.g. '{\"gcr.io\": \"Bearer asdf=\"}', overriding the docker config file\n  (default from $PACK_REGISTRY_AUTH)")
 	rootCmd.Flags().Bool("version", false, "Show current 'pack' version")
 
 	commands.AddHelpFlag(rootCmd, "pack")
@@ -63,12 +118,17 @@ func main() {
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.InspectImage(logger, &cfg, &packClient))
 
+	rootCmd.AddCommand(commands.Bundle(logger, &packClient))
+	rootCmd.AddCommand(commands.Cache(logger))
+	rootCmd.AddCommand(commands.Prune(logger, &packClient))
 	rootCmd.AddCommand(commands.CreateBuilder(logger, &packClient))
 	rootCmd.AddCommand(commands.PackageBuildpack(logger, &packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.SetRunImagesMirrors(logger, cfg))
 	rootCmd.AddCommand(commands.InspectBuilder(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.InspectBuildpack(logger, &packClient))
 	rootCmd.AddCommand(commands.SetDefaultBuilder(logger, cfg, &packClient))
-	rootCmd.AddCommand(commands.SuggestBuilders(logger, &packClient))
+	rootCmd.AddCommand(commands.Config(logger, cfg))
+	rootCmd.AddCommand(commands.SuggestBuilders(logger, cfg, &packClient))
 
 	rootCmd.AddCommand(commands.SuggestStacks(logger))
 	rootCmd.AddCommand(commands.Version(logger, cmd.Version))
@@ -100,8 +160,24 @@ func initConfig() (config.Config, error) {
 	return cfg, nil
 }
 
-func initClient(logger logging.Logger) pack.Client {
-	client, err := pack.NewClient(pack.WithLogger(logger))
+func initClient(logger logging.Logger, noBlobCache bool, downloadTimeout time.Duration, registryAuth string, registryMirrors map[string]string, insecureRegistries []string) pack.Client {
+	opts := []pack.ClientOption{pack.WithLogger(logger)}
+	if noBlobCache {
+		opts = append(opts, pack.WithBlobCacheDisabled())
+	}
+	if downloadTimeout > 0 {
+		opts = append(opts, pack.WithDownloadTimeout(downloadTimeout))
+	}
+	if registryAuth != "" {
+		opts = append(opts, pack.WithRegistryAuth(registryAuth))
+	}
+	if len(registryMirrors) > 0 {
+		opts = append(opts, pack.WithRegistryMirrors(registryMirrors))
+	}
+	if len(insecureRegistries) > 0 {
+		opts = append(opts, pack.WithInsecureRegistries(insecureRegistries))
+	}
+	client, err := pack.NewClient(opts...)
 	if err != nil {
 		exitError(logger, err)
 	}
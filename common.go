@@ -3,13 +3,42 @@ package pack
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1remote "github.com/google/go-containerregistry/pkg/v1/remote"
 
 	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/style"
 )
 
+// registryKeychain returns the keychain the client resolves registry
+// credentials with, falling back to the default docker config file keychain
+// for clients constructed without NewClient.
+func (c *Client) registryKeychain() authn.Keychain {
+	if c.keychain != nil {
+		return c.keychain
+	}
+	return authn.DefaultKeychain
+}
+
+// registryOptions returns the options the client's direct registry calls are
+// made with, so they resolve credentials through the client's keychain and
+// travel over the image factory's transport, keeping insecure-registry
+// handling consistent with fetches and pushes.
+func (c *Client) registryOptions() []v1remote.Option {
+	opts := []v1remote.Option{v1remote.WithAuthFromKeychain(c.registryKeychain())}
+	if factory, ok := c.imageFactory.(*image.DefaultImageFactory); ok {
+		if transport := factory.Transport(); transport != nil {
+			opts = append(opts, v1remote.WithTransport(transport))
+		}
+	}
+	return opts
+}
+
 func (c *Client) parseTagReference(imageName string) (name.Reference, error) {
 	if imageName == "" {
 		return nil, errors.New("image is a required parameter")
@@ -30,7 +59,7 @@ func (c *Client) resolveRunImage(runImage, targetRegistry string, stackInfo buil
 		c.logger.Debugf("Using provided run-image %s", style.Symbol(runImage))
 		return runImage
 	}
-	runImageName := getBestRunMirror(
+	runImageName := stack.BestRunImageMirror(
 		targetRegistry,
 		stackInfo.RunImage.Image,
 		stackInfo.RunImage.Mirrors,
@@ -48,6 +77,18 @@ func (c *Client) resolveRunImage(runImage, targetRegistry string, stackInfo buil
 	return runImageName
 }
 
+// splitRunImageList splits a comma-separated run image value into its
+// fallback candidates, dropping empty entries.
+func splitRunImageList(runImage string) []string {
+	var candidates []string
+	for _, candidate := range strings.Split(runImage, ",") {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
 func contains(slc []string, v string) bool {
 	for _, s := range slc {
 		if s == v {
@@ -56,22 +97,3 @@ func contains(slc []string, v string) bool {
 	}
 	return false
 }
-
-func getBestRunMirror(registry string, runImage string, mirrors []string, preferredMirrors []string) string {
-	runImageList := append(preferredMirrors, append([]string{runImage}, mirrors...)...)
-	for _, img := range runImageList {
-		ref, err := name.ParseReference(img, name.WeakValidation)
-		if err != nil {
-			continue
-		}
-		if ref.Context().RegistryStr() == registry {
-			return img
-		}
-	}
-
-	if len(preferredMirrors) > 0 {
-		return preferredMirrors[0]
-	}
-
-	return runImage
-}
@@ -2,13 +2,19 @@ package pack
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil"
 	"github.com/pkg/errors"
 
 	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/blob"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/dist"
 	"github.com/buildpacks/pack/internal/image"
@@ -20,6 +26,10 @@ type CreateBuilderOptions struct {
 	Config      pubbldr.Config
 	Publish     bool
 	NoPull      bool
+
+	// Maximum number of image layers the buildpacks are written as; zero
+	// keeps one layer per buildpack.
+	Flatten int
 }
 
 func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) error {
@@ -27,11 +37,20 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		return errors.Wrap(err, "invalid builder config")
 	}
 
+	apiRange, err := api.NewRange(opts.Config.Lifecycle.API.BuildpackMin, opts.Config.Lifecycle.API.BuildpackMax)
+	if err != nil {
+		return errors.Wrap(err, "invalid builder config")
+	}
+
+	if opts.Flatten < 0 {
+		return errors.Errorf("invalid flatten value %s: must be a positive layer count", style.Symbol(fmt.Sprintf("%d", opts.Flatten)))
+	}
+
 	if err := c.validateRunImageConfig(ctx, opts); err != nil {
 		return err
 	}
 
-	baseImage, err := c.imageFetcher.Fetch(ctx, opts.Config.Stack.BuildImage, !opts.Publish, !opts.NoPull)
+	baseImage, err := c.imageFetcher.Fetch(ctx, opts.Config.Stack.BuildImage, !opts.Publish, pullPolicyForNoPull(opts.NoPull))
 	if err != nil {
 		return errors.Wrap(err, "fetch build image")
 	}
@@ -52,7 +71,7 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		)
 	}
 
-	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle)
+	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle, opts.Publish, opts.NoPull)
 	if err != nil {
 		return errors.Wrap(err, "fetch lifecycle")
 	}
@@ -82,26 +101,63 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 			return errors.Wrap(err, "invalid buildpack")
 		}
 
+		if err := validateBuildpackAPI(fetchedBp, apiRange); err != nil {
+			return err
+		}
+
 		bldr.AddBuildpack(fetchedBp)
 	}
 
 	for _, pkg := range opts.Config.Buildpacks.Packages() {
-		mainBP, depBPs, err := extractPackagedBuildpacks(ctx, pkg.ImageName, c.imageFetcher, opts.Publish, opts.NoPull)
+		mainBP, depBPs, err := extractPackagedBuildpacks(ctx, pkg.ImageName, c.imageFetcher, opts.Publish, pullPolicyForNoPull(opts.NoPull))
 		if err != nil {
 			return err
 		}
 
 		for _, bp := range append([]dist.Buildpack{mainBP}, depBPs...) {
+			if err := validateBuildpackAPI(bp, apiRange); err != nil {
+				return err
+			}
+
 			bldr.AddBuildpack(bp)
 		}
 	}
 
 	bldr.SetOrder(opts.Config.Order)
 	bldr.SetStack(opts.Config.Stack)
+	bldr.SetFlatten(opts.Flatten)
 
 	return bldr.Save(c.logger)
 }
 
+// CreateBuilderResult describes the builder image a successful CreateBuilder
+// produced.
+type CreateBuilderResult struct {
+	ImageRef string // canonical, tag-qualified name the builder was created as
+	Digest   string // the image's identifier: a digest reference when published, an image ID otherwise
+}
+
+// CreateBuilderWithResult runs CreateBuilder and reports the builder image's
+// canonical reference and digest, for callers embedding pack as a library
+// rather than reading the CLI's log output.
+func (c *Client) CreateBuilderWithResult(ctx context.Context, opts CreateBuilderOptions) (*CreateBuilderResult, error) {
+	if err := c.CreateBuilder(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	imageRef, err := c.parseTagReference(opts.BuilderName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.BuilderName)
+	}
+
+	digest, err := c.ImageDigest(imageRef.Name(), !opts.Publish)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving builder image digest")
+	}
+
+	return &CreateBuilderResult{ImageRef: imageRef.Name(), Digest: digest}, nil
+}
+
 func validateBuildpack(bp dist.Buildpack, source, expectedID, expectedBPVersion string) error {
 	if expectedID != "" && bp.Descriptor().Info.ID != expectedID {
 		return fmt.Errorf(
@@ -124,7 +180,20 @@ func validateBuildpack(bp dist.Buildpack, source, expectedID, expectedBPVersion
 	return nil
 }
 
-func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig) (builder.Lifecycle, error) {
+func validateBuildpackAPI(bp dist.Buildpack, apiRange *api.Range) error {
+	if !apiRange.Contains(bp.Descriptor().API) {
+		return fmt.Errorf(
+			"buildpack %s (Buildpack API version %s) is outside the builder's declared Buildpack API range %s",
+			style.Symbol(bp.Descriptor().Info.FullName()),
+			bp.Descriptor().API.String(),
+			style.Symbol(apiRange.String()),
+		)
+	}
+
+	return nil
+}
+
+func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig, publish, noPull bool) (builder.Lifecycle, error) {
 	if config.Version != "" && config.URI != "" {
 		return nil, errors.Errorf(
 			"%s can only declare %s or %s, not both",
@@ -147,11 +216,62 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 		uri = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion))
 	}
 
+	if strings.HasPrefix(uri, "docker://") {
+		return c.fetchLifecycleImage(ctx, strings.TrimPrefix(uri, "docker://"), publish, noPull)
+	}
+
 	b, err := c.downloader.Download(ctx, uri)
 	if err != nil {
 		return nil, errors.Wrap(err, "downloading lifecycle")
 	}
 
+	if config.Checksum != "" {
+		if err := verifyBlobChecksum(b, config.Checksum); err != nil {
+			return nil, errors.Wrap(err, "verifying lifecycle")
+		}
+	}
+
+	lifecycle, err := builder.NewLifecycle(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid lifecycle")
+	}
+
+	return lifecycle, nil
+}
+
+// fetchLifecycleImage reads the lifecycle from a standalone lifecycle image,
+// extracting the layer the image's metadata label points at.
+func (c *Client) fetchLifecycleImage(ctx context.Context, ref string, publish, noPull bool) (builder.Lifecycle, error) {
+	img, err := c.imageFetcher.Fetch(ctx, ref, !publish, pullPolicyForNoPull(noPull))
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch lifecycle image")
+	}
+
+	var metadata builder.LifecycleMetadata
+	if ok, err := dist.GetLabel(img, builder.LifecycleMetadataLabel, &metadata); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.Errorf("lifecycle image %s missing label %s", style.Symbol(img.Name()), style.Symbol(builder.LifecycleMetadataLabel))
+	}
+
+	if metadata.LayerDiffID == "" {
+		return nil, errors.Errorf("lifecycle image %s does not declare a lifecycle layer", style.Symbol(img.Name()))
+	}
+
+	b := &openerBlob{
+		opener: func() (io.ReadCloser, error) {
+			rc, err := img.GetLayer(metadata.LayerDiffID)
+			if err != nil {
+				return nil, errors.Wrapf(err,
+					"extracting lifecycle layer (diffID %s) from image %s",
+					style.Symbol(metadata.LayerDiffID),
+					style.Symbol(img.Name()),
+				)
+			}
+			return rc, nil
+		},
+	}
+
 	lifecycle, err := builder.NewLifecycle(b)
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid lifecycle")
@@ -160,6 +280,28 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 	return lifecycle, nil
 }
 
+// verifyBlobChecksum compares the SHA256 digest of the blob's contents to the
+// expected checksum, which may carry an optional "sha256:" prefix.
+func verifyBlobChecksum(b blob.Blob, expected string) error {
+	rc, err := b.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != strings.TrimPrefix(expected, "sha256:") {
+		return errors.Errorf("checksum mismatch: expected %s, got %s", style.Symbol(expected), style.Symbol(actual))
+	}
+
+	return nil
+}
+
 func uriFromLifecycleVersion(version semver.Version) string {
 	return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+linux.x86-64.tgz", version.String(), version.String())
 }
@@ -184,7 +326,7 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 	var runImages []imgutil.Image
 	for _, i := range append([]string{opts.Config.Stack.RunImage}, opts.Config.Stack.RunImageMirrors...) {
 		if !opts.Publish {
-			img, err := c.imageFetcher.Fetch(ctx, i, true, false)
+			img, err := c.imageFetcher.Fetch(ctx, i, true, image.PullNever)
 			if err != nil {
 				if errors.Cause(err) != image.ErrNotFound {
 					return err
@@ -195,7 +337,7 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 			}
 		}
 
-		img, err := c.imageFetcher.Fetch(ctx, i, false, false)
+		img, err := c.imageFetcher.Fetch(ctx, i, false, image.PullNever)
 		if err != nil {
 			if errors.Cause(err) != image.ErrNotFound {
 				return err
@@ -4,6 +4,8 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,6 +15,7 @@ import (
 	"testing"
 
 	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/imgutil/local"
 	"github.com/golang/mock/gomock"
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
@@ -166,7 +169,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 
 			it("should fail when the stack ID from the builder config does not match the stack ID from the build image", func() {
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image", true, true).Return(fakeBuildImage, nil)
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image", true, image.PullAlways).Return(fakeBuildImage, nil)
 				h.AssertNil(t, fakeBuildImage.SetLabel("io.buildpacks.stack.id", "other.stack.id"))
 				prepareFetcherWithRunImages()
 
@@ -254,13 +257,13 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 
 			it("should warn when the run image cannot be found", func() {
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image", true, true).Return(fakeBuildImage, nil)
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/build-image", true, image.PullAlways).Return(fakeBuildImage, nil)
 
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run-image", false, false).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run-image", true, false).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run-image", false, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run-image", true, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
 
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "localhost:5000/some/run-image", false, false).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
-				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "localhost:5000/some/run-image", true, false).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "localhost:5000/some/run-image", false, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "localhost:5000/some/run-image", true, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "yikes!"))
 
 				err := subject.CreateBuilder(context.TODO(), opts)
 				h.AssertNil(t, err)
@@ -328,6 +331,112 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("lifecycle declares a buildpack api range", func() {
+			it("should succeed when the buildpack api is within the range", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle.API = pubbldr.LifecycleAPIConfig{BuildpackMin: "0.2", BuildpackMax: "0.3"}
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertNil(t, err)
+			})
+
+			it("should fail when the buildpack api is outside the range", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle.API = pubbldr.LifecycleAPIConfig{BuildpackMax: "0.2"}
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "buildpack 'bp.one@1.2.3' (Buildpack API version 0.3) is outside the builder's declared Buildpack API range '<= 0.2'")
+			})
+
+			it("should fail when the range is malformed", func() {
+				opts.Config.Lifecycle.API = pubbldr.LifecycleAPIConfig{BuildpackMin: "not-a-version"}
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "invalid builder config: could not parse 'not-a-version' as version")
+			})
+		})
+
+		when("lifecycle URI is an https URL", func() {
+			it("should download the lifecycle", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle = pubbldr.LifecycleConfig{URI: "https://example.fake/lifecycle.tgz"}
+
+				mockDownloader.EXPECT().Download(
+					gomock.Any(),
+					"https://example.fake/lifecycle.tgz",
+				).Return(
+					blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil,
+				)
+
+				bldr := successfullyCreateBuilder()
+
+				h.AssertEq(t, bldr.LifecycleDescriptor().Info.Version.String(), "3.4.5")
+			})
+		})
+
+		when("lifecycle checksum is provided", func() {
+			it("should succeed when the checksum matches", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle.Checksum = "sha256:" + blobChecksum(t, filepath.Join("testdata", "lifecycle"))
+
+				successfullyCreateBuilder()
+			})
+
+			it("should fail when the checksum does not match", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle.Checksum = "sha256:deadbeef"
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "fetch lifecycle: verifying lifecycle: checksum mismatch: expected 'sha256:deadbeef'")
+			})
+		})
+
+		when("lifecycle URI is a docker image reference", func() {
+			it("should extract the lifecycle from the image's layer", func() {
+				lifecycleTar := filepath.Join(tmpDir, "lifecycle.tar")
+				h.AssertNil(t, copyBlobToFile(blob.NewBlob(filepath.Join("testdata", "lifecycle")), lifecycleTar))
+				diffID := "sha256:" + fileChecksum(t, lifecycleTar)
+
+				fakeLifecycleImage := fakes.NewImage("index.docker.io/some/lifecycle-image:latest", "", nil)
+				h.AssertNil(t, fakeLifecycleImage.AddLayer(lifecycleTar))
+				h.AssertNil(t, fakeLifecycleImage.SetLabel(
+					"io.buildpacks.lifecycle.metadata",
+					fmt.Sprintf(`{"version": "3.4.5", "api": {"buildpack": "0.2", "platform": "0.2"}, "layerDiffID": %q}`, diffID),
+				))
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/lifecycle-image", true, gomock.Any()).Return(fakeLifecycleImage, nil)
+
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle = pubbldr.LifecycleConfig{URI: "docker://some/lifecycle-image"}
+
+				bldr := successfullyCreateBuilder()
+
+				h.AssertEq(t, bldr.LifecycleDescriptor().Info.Version.String(), "3.4.5")
+			})
+
+			it("should fail when the image does not describe a lifecycle", func() {
+				fakeLifecycleImage := fakes.NewImage("index.docker.io/some/lifecycle-image:latest", "", nil)
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/lifecycle-image", true, gomock.Any()).Return(fakeLifecycleImage, nil)
+
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				opts.Config.Lifecycle = pubbldr.LifecycleConfig{URI: "docker://some/lifecycle-image"}
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "fetch lifecycle: lifecycle image 'index.docker.io/some/lifecycle-image:latest' missing label 'io.buildpacks.lifecycle.metadata'")
+			})
+		})
+
 		when("buildpack mixins are not satisfied", func() {
 			it("should return an error", func() {
 				prepareFetcherWithBuildImage()
@@ -393,6 +502,31 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("#CreateBuilderWithResult", func() {
+			it("returns the builder image reference and digest", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "index.docker.io/some/builder:latest", true, image.PullNever).
+					Return(fakes.NewImage("index.docker.io/some/builder:latest", "", local.IDIdentifier{ImageID: "builder-image-id"}), nil)
+
+				result, err := subject.CreateBuilderWithResult(context.TODO(), opts)
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, fakeBuildImage.IsSaved(), true)
+				h.AssertEq(t, result.ImageRef, "index.docker.io/some/builder:latest")
+				h.AssertEq(t, result.Digest, "builder-image-id")
+			})
+
+			it("validates the config like CreateBuilder", func() {
+				opts.Config.Stack.ID = ""
+
+				_, err := subject.CreateBuilderWithResult(context.TODO(), opts)
+
+				h.AssertError(t, err, "stack.id is required")
+			})
+		})
+
 		when("creation succeeds for platform API < 0.2", func() {
 			it("should set basic metadata", func() {
 				configureBuilderWithLifecycleAPIv0_1()
@@ -484,7 +618,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 
 		when("packages", func() {
 			createBuildpack := func(descriptor dist.BuildpackDescriptor) string {
-				bp, err := ifakes.NewFakeBuildpackBlob(descriptor, 0644)
+				bp, err := ifakes.NewFakeBuildpackBlob(descriptor, 0755)
 				h.AssertNil(t, err)
 				url := fmt.Sprintf("https://example.com/bp.%s.tgz", h.RandString(12))
 				mockDownloader.EXPECT().Download(gomock.Any(), url).Return(bp, nil).AnyTimes()
@@ -513,7 +647,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 					}))
 				})
 
-				shouldFetchPackageImageWith := func(demon, pull bool) {
+				shouldFetchPackageImageWith := func(demon bool, pull image.PullPolicy) {
 					mockImageFetcher.EXPECT().Fetch(gomock.Any(), packageImage.Name(), demon, pull).Return(packageImage, nil)
 				}
 
@@ -538,7 +672,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 							},
 						)
 
-						shouldFetchPackageImageWith(true, true)
+						shouldFetchPackageImageWith(true, image.PullAlways)
 						h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
 					})
 				})
@@ -560,7 +694,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 							},
 						)
 
-						shouldFetchPackageImageWith(false, true)
+						shouldFetchPackageImageWith(false, image.PullAlways)
 						h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
 					})
 				})
@@ -582,7 +716,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 							},
 						)
 
-						shouldFetchPackageImageWith(false, false)
+						shouldFetchPackageImageWith(false, image.PullNever)
 						h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
 					})
 				})
@@ -637,6 +771,51 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 	})
 }
 
+func blobChecksum(t *testing.T, path string) string {
+	t.Helper()
+
+	rc, err := blob.NewBlob(path).Open()
+	h.AssertNil(t, err)
+	defer rc.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, rc)
+	h.AssertNil(t, err)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func fileChecksum(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	h.AssertNil(t, err)
+	defer f.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	h.AssertNil(t, err)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func copyBlobToFile(b blob.Blob, dest string) error {
+	rc, err := b.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, rc)
+	return err
+}
+
 func assertTarHasFile(t *testing.T, tarFile, path string) {
 	t.Helper()
 
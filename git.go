@@ -0,0 +1,116 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// isGitSource reports whether the app path is a remote git URL rather than a
+// local directory or archive.
+func isGitSource(appPath string) bool {
+	for _, prefix := range []string{"git://", "git@", "ssh://"} {
+		if strings.HasPrefix(appPath, prefix) {
+			return true
+		}
+	}
+
+	if strings.HasPrefix(appPath, "http://") || strings.HasPrefix(appPath, "https://") {
+		repo := appPath
+		if i := strings.LastIndex(repo, "#"); i >= 0 {
+			repo = repo[:i]
+		}
+		return strings.HasSuffix(repo, ".git")
+	}
+
+	return false
+}
+
+// cloneGitSource shallow-clones a git URL into a temp dir and returns its path
+// along with a cleanup function. An optional '#<ref>' fragment selects the
+// branch, tag or commit to check out; without one the remote HEAD is used.
+// The git binary inherits the environment, so GIT_* variables and the SSH
+// agent work for private repositories.
+func (c *Client) cloneGitSource(ctx context.Context, gitURL string) (string, func(), error) {
+	repo, ref := gitURL, "HEAD"
+	if i := strings.LastIndex(gitURL, "#"); i >= 0 {
+		repo = gitURL[:i]
+		if fragment := gitURL[i+1:]; fragment != "" {
+			ref = fragment
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "pack-app-git")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "create temp dir")
+	}
+	cleanup := func() {
+		os.RemoveAll(tmpDir)
+	}
+
+	// init plus fetch of a single ref instead of clone --branch, so branches,
+	// tags and commits all resolve the same way while staying shallow
+	setup := [][]string{
+		{"init", "--quiet", tmpDir},
+		{"-C", tmpDir, "remote", "add", "origin", repo},
+	}
+	for _, args := range setup {
+		if output, err := runGit(ctx, args...); err != nil {
+			cleanup()
+			return "", nil, errors.Errorf("cloning git source %s: %s", style.Symbol(repo), output)
+		}
+	}
+
+	checkoutRef := "FETCH_HEAD"
+	if output, err := runGit(ctx, "-C", tmpDir, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		if !isCommitHash(ref) {
+			cleanup()
+			return "", nil, errors.Errorf("resolving git ref %s for %s: %s", style.Symbol(ref), style.Symbol(repo), output)
+		}
+
+		// some servers refuse shallow fetches of unadvertised commits, so fall
+		// back to a full fetch and check the commit out from history
+		if _, err := runGit(ctx, "-C", tmpDir, "fetch", "--quiet", "origin"); err != nil {
+			cleanup()
+			return "", nil, errors.Errorf("resolving git ref %s for %s: %s", style.Symbol(ref), style.Symbol(repo), output)
+		}
+		checkoutRef = ref
+	}
+
+	if output, err := runGit(ctx, "-C", tmpDir, "checkout", "--quiet", checkoutRef); err != nil {
+		cleanup()
+		return "", nil, errors.Errorf("checking out git ref %s for %s: %s", style.Symbol(ref), style.Symbol(repo), output)
+	}
+
+	c.logger.Debugf("Using git source %s at %s", style.Symbol(repo), style.Symbol(ref))
+	return tmpDir, cleanup, nil
+}
+
+// isCommitHash reports whether the ref looks like a (possibly abbreviated)
+// commit hash rather than a branch or tag name.
+func isCommitHash(ref string) bool {
+	if len(ref) < 4 || len(ref) > 40 {
+		return false
+	}
+	for _, r := range ref {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func runGit(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return strings.TrimSpace(stderr.String()), err
+}
@@ -0,0 +1,145 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestGitSource(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "GitSource", testGitSource, spec.Report(report.Terminal{}))
+}
+
+func testGitSource(t *testing.T, when spec.G, it spec.S) {
+	when("#isGitSource", func() {
+		it("detects git URLs", func() {
+			for _, url := range []string{
+				"git://github.com/org/repo",
+				"git://github.com/org/repo#main",
+				"git@github.com:org/repo.git",
+				"ssh://git@github.com/org/repo.git",
+				"https://github.com/org/repo.git",
+				"https://github.com/org/repo.git#v1.2.3",
+			} {
+				h.AssertEq(t, isGitSource(url), true)
+			}
+		})
+
+		it("leaves local paths and plain URLs alone", func() {
+			for _, path := range []string{
+				"",
+				"./some-dir",
+				"/absolute/dir",
+				"some-app.zip",
+				"https://github.com/org/repo",
+			} {
+				h.AssertEq(t, isGitSource(path), false)
+			}
+		})
+	})
+
+	when("#cloneGitSource", func() {
+		var (
+			subject *Client
+			repoDir string
+			commit  string
+		)
+
+		git := func(args ...string) string {
+			cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+			cmd.Env = append(os.Environ(),
+				"GIT_AUTHOR_NAME=pack", "GIT_AUTHOR_EMAIL=pack@test",
+				"GIT_COMMITTER_NAME=pack", "GIT_COMMITTER_EMAIL=pack@test",
+			)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("git %v: %s: %s", args, err, output)
+			}
+			return strings.TrimSpace(string(output))
+		}
+
+		it.Before(func() {
+			if _, err := exec.LookPath("git"); err != nil {
+				t.Skip("git is not available")
+			}
+
+			var outBuf bytes.Buffer
+			subject = &Client{logger: ilogging.NewLogWithWriters(&outBuf, &outBuf)}
+
+			var err error
+			repoDir, err = ioutil.TempDir("", "git-source-repo")
+			h.AssertNil(t, err)
+
+			git("init", "--quiet", "--initial-branch=main")
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(repoDir, "app.txt"), []byte("main content"), 0644))
+			git("add", "app.txt")
+			git("commit", "--quiet", "-m", "initial commit")
+			commit = git("rev-parse", "HEAD")
+			git("tag", "v1.0.0")
+
+			git("checkout", "--quiet", "-b", "feature")
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(repoDir, "app.txt"), []byte("feature content"), 0644))
+			git("commit", "--quiet", "-am", "feature commit")
+			git("checkout", "--quiet", "main")
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(repoDir))
+		})
+
+		assertCloneContent := func(ref, content string) {
+			t.Helper()
+			clonePath, cleanup, err := subject.cloneGitSource(context.TODO(), repoDir+ref)
+			h.AssertNil(t, err)
+			defer cleanup()
+
+			appContent, err := ioutil.ReadFile(filepath.Join(clonePath, "app.txt"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(appContent), content)
+		}
+
+		it("checks out the remote HEAD without a fragment", func() {
+			assertCloneContent("", "main content")
+		})
+
+		it("supports branch fragments", func() {
+			assertCloneContent("#feature", "feature content")
+		})
+
+		it("supports tag fragments", func() {
+			assertCloneContent("#v1.0.0", "main content")
+		})
+
+		it("supports commit fragments", func() {
+			assertCloneContent("#"+commit, "main content")
+		})
+
+		it("errors when the ref can't be resolved", func() {
+			_, _, err := subject.cloneGitSource(context.TODO(), repoDir+"#does-not-exist")
+			h.AssertError(t, err, "resolving git ref 'does-not-exist'")
+		})
+
+		it("cleans up the clone", func() {
+			clonePath, cleanup, err := subject.cloneGitSource(context.TODO(), repoDir)
+			h.AssertNil(t, err)
+
+			cleanup()
+			_, err = os.Stat(clonePath)
+			h.AssertTrue(t, os.IsNotExist(err))
+		})
+	})
+}
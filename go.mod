@@ -7,6 +7,7 @@ require (
 	github.com/buildpacks/imgutil v0.0.0-20200127205531-eec2000815b1
 	github.com/buildpacks/lifecycle v0.5.1-0.20191217221752-3b74c943b7b3
 	github.com/dgodd/dockerdial v1.0.1
+	github.com/docker/cli v0.0.0-20191017083524-a8ff7f821017
 	github.com/docker/docker v1.4.2-0.20190924003213-a8608b5b67c7
 	github.com/docker/go-connections v0.4.0
 	github.com/golang/mock v1.3.1
@@ -22,3 +23,7 @@ require (
 )
 
 go 1.13
+
+// imgutil carries a local patch adding remote.WithTransport until it lands
+// upstream; see third_party/imgutil/README.md.
+replace github.com/buildpacks/imgutil => ./third_party/imgutil
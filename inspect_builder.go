@@ -2,13 +2,17 @@ package pack
 
 import (
 	"context"
+	"sort"
 	"strings"
 
+	"github.com/buildpacks/imgutil"
 	"github.com/pkg/errors"
 
+	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/dist"
 	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/stringset"
 	"github.com/buildpacks/pack/internal/style"
 )
 
@@ -20,12 +24,44 @@ type BuilderInfo struct {
 	RunImageMirrors []string
 	Buildpacks      []dist.BuildpackInfo
 	Order           dist.Order
+	OrderTree       []DetectionOrderGroup
 	Lifecycle       builder.LifecycleDescriptor
 	CreatedBy       builder.CreatorMetadata
+	MixinReport     []MixinReportEntry
+	APIReport       []APIReportEntry
+}
+
+// DetectionOrderGroup is one group in the resolved detection order tree.
+type DetectionOrderGroup struct {
+	Group []DetectionOrderEntry `json:"group"`
+}
+
+// DetectionOrderEntry is one buildpack reference in the detection order tree.
+// Meta (order) buildpacks carry the nested groups recorded in the builder's
+// buildpack layers metadata.
+type DetectionOrderEntry struct {
+	dist.BuildpackRef
+	Groups []DetectionOrderGroup `json:"groups,omitempty"`
+}
+
+// MixinReportEntry describes the mixins a buildpack requires for the builder's stack and
+// which of those the stack images do not provide. Run-stage mixins are excluded from
+// Missing since the run image's mixins are only known at build time.
+type MixinReportEntry struct {
+	Buildpack dist.BuildpackInfo `json:"buildpack"`
+	Required  []string           `json:"required"`
+	Missing   []string           `json:"missing,omitempty"`
+}
+
+// APIReportEntry records a buildpack whose declared Buildpack API version is not
+// supported by the builder's bundled lifecycle.
+type APIReportEntry struct {
+	Buildpack dist.BuildpackInfo `json:"buildpack"`
+	API       string             `json:"api"`
 }
 
 func (c *Client) InspectBuilder(name string, daemon bool) (*BuilderInfo, error) {
-	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, false)
+	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, image.PullNever)
 	if err != nil {
 		if errors.Cause(err) == image.ErrNotFound {
 			return nil, nil
@@ -48,6 +84,21 @@ func (c *Client) InspectBuilder(name string, daemon bool) (*BuilderInfo, error)
 		}
 	}
 
+	mixinReport, err := mixinReport(img, bldr.StackID, bldr.Mixins())
+	if err != nil {
+		return nil, errors.Wrapf(err, "validating mixins for builder %s", style.Symbol(name))
+	}
+
+	apiReport, err := apiReport(img, bldr.LifecycleDescriptor().API.BuildpackVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "validating buildpack apis for builder %s", style.Symbol(name))
+	}
+
+	orderTree, err := resolveOrderTree(img, bldr.Order())
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving detection order for builder %s", style.Symbol(name))
+	}
+
 	return &BuilderInfo{
 		Description:     bldr.Description(),
 		Stack:           bldr.StackID,
@@ -56,7 +107,155 @@ func (c *Client) InspectBuilder(name string, daemon bool) (*BuilderInfo, error)
 		RunImageMirrors: bldr.Stack().RunImage.Mirrors,
 		Buildpacks:      bldr.Buildpacks(),
 		Order:           bldr.Order(),
+		OrderTree:       orderTree,
 		Lifecycle:       bldr.LifecycleDescriptor(),
 		CreatedBy:       bldr.CreatedBy(),
+		MixinReport:     mixinReport,
+		APIReport:       apiReport,
 	}, nil
 }
+
+// resolveOrderTree expands the builder's top-level detection order into a tree,
+// following meta (order) buildpacks into the nested order recorded in the
+// buildpack layers metadata.
+func resolveOrderTree(builderImage imgutil.Image, order dist.Order) ([]DetectionOrderGroup, error) {
+	bpLayers, err := dist.BuildpackLayersForImage(builderImage)
+	if err != nil {
+		return nil, err
+	}
+	return orderTreeGroups(order, bpLayers, map[string]interface{}{}), nil
+}
+
+func orderTreeGroups(order dist.Order, bpLayers dist.BuildpackLayers, visited map[string]interface{}) []DetectionOrderGroup {
+	var groups []DetectionOrderGroup
+	for _, entry := range order {
+		group := DetectionOrderGroup{}
+		for _, ref := range entry.Group {
+			node := DetectionOrderEntry{BuildpackRef: ref}
+
+			// a buildpack already being expanded marks a cycle; leave it unexpanded
+			if nested, key, ok := nestedOrder(ref, bpLayers); ok {
+				if _, seen := visited[key]; !seen {
+					visited[key] = nil
+					node.Groups = orderTreeGroups(nested, bpLayers, visited)
+					delete(visited, key)
+				}
+			}
+
+			group.Group = append(group.Group, node)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// nestedOrder looks up the nested order of a meta (order) buildpack. A
+// reference without a version matches when exactly one version is present.
+func nestedOrder(ref dist.BuildpackRef, bpLayers dist.BuildpackLayers) (dist.Order, string, bool) {
+	versions, ok := bpLayers[ref.ID]
+	if !ok {
+		return nil, "", false
+	}
+
+	version := ref.Version
+	if version == "" && len(versions) == 1 {
+		for v := range versions {
+			version = v
+		}
+	}
+
+	info, ok := versions[version]
+	if !ok || len(info.Order) == 0 {
+		return nil, "", false
+	}
+	return info.Order, ref.ID + "@" + version, true
+}
+
+// mixinReport compares the mixins required by each of the builder's buildpacks against the
+// mixins the builder declares, reporting any gaps. Buildpacks without stacks (order
+// buildpacks) are skipped, as are run-stage requirements, which depend on the run image.
+func mixinReport(builderImage imgutil.Image, stackID string, providedMixins []string) ([]MixinReportEntry, error) {
+	bpLayers, err := dist.BuildpackLayersForImage(builderImage)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []MixinReportEntry
+	for id, bps := range bpLayers {
+		for ver, bp := range bps {
+			if len(bp.Stacks) == 0 {
+				continue
+			}
+
+			var required []string
+			for _, s := range bp.Stacks {
+				if s.ID == stackID {
+					required = s.Mixins
+					break
+				}
+			}
+
+			var validatable []string
+			for _, m := range required {
+				if !strings.HasPrefix(m, "run:") {
+					validatable = append(validatable, m)
+				}
+			}
+
+			_, missing, _ := stringset.Compare(providedMixins, validatable)
+			sort.Strings(missing)
+
+			report = append(report, MixinReportEntry{
+				Buildpack: dist.BuildpackInfo{ID: id, Version: ver},
+				Required:  required,
+				Missing:   missing,
+			})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Buildpack.ID != report[j].Buildpack.ID {
+			return report[i].Buildpack.ID < report[j].Buildpack.ID
+		}
+		return report[i].Buildpack.Version < report[j].Buildpack.Version
+	})
+
+	return report, nil
+}
+
+// apiReport lists the buildpacks whose declared Buildpack API version the builder's
+// lifecycle does not support. A builder without a lifecycle Buildpack API version
+// yields no entries; the missing version is warned about separately.
+func apiReport(builderImage imgutil.Image, lifecycleAPI *api.Version) ([]APIReportEntry, error) {
+	if lifecycleAPI == nil {
+		return nil, nil
+	}
+
+	bpLayers, err := dist.BuildpackLayersForImage(builderImage)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []APIReportEntry
+	for id, bps := range bpLayers {
+		for ver, bp := range bps {
+			if bp.API == nil || lifecycleAPI.SupportsVersion(bp.API) {
+				continue
+			}
+
+			report = append(report, APIReportEntry{
+				Buildpack: dist.BuildpackInfo{ID: id, Version: ver},
+				API:       bp.API.String(),
+			})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Buildpack.ID != report[j].Buildpack.ID {
+			return report[i].Buildpack.ID < report[j].Buildpack.ID
+		}
+		return report[i].Buildpack.Version < report[j].Buildpack.Version
+	})
+
+	return report, nil
+}
@@ -63,9 +63,9 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 			when(fmt.Sprintf("daemon is %t", useDaemon), func() {
 				it.Before(func() {
 					if useDaemon {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, false).Return(builderImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullNever).Return(builderImage, nil)
 					} else {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, false).Return(builderImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, image.PullNever).Return(builderImage, nil)
 					}
 				})
 
@@ -130,6 +130,82 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 						)
 					})
 
+					when("the builder has buildpack layers metadata", func() {
+						it.Before(func() {
+							h.AssertNil(t, builderImage.SetLabel(
+								"io.buildpacks.buildpack.layers",
+								`{"test.bp.one":{"1.0.0":{"layerDiffID":"sha256:abc","stacks":[{"id":"test.stack.id","mixins":["mixinOne","mixinAbsent","run:mixinIgnored"]}]}}}`,
+							))
+						})
+
+						it("reports required and missing mixins per buildpack", func() {
+							builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
+							h.AssertNil(t, err)
+							h.AssertEq(t, len(builderInfo.MixinReport), 1)
+
+							entry := builderInfo.MixinReport[0]
+							h.AssertEq(t, entry.Buildpack, dist.BuildpackInfo{ID: "test.bp.one", Version: "1.0.0"})
+							h.AssertEq(t, entry.Required, []string{"mixinOne", "mixinAbsent", "run:mixinIgnored"})
+							h.AssertEq(t, entry.Missing, []string{"mixinAbsent"})
+						})
+					})
+
+					when("a buildpack's api is not supported by the lifecycle", func() {
+						it.Before(func() {
+							h.AssertNil(t, builderImage.SetLabel(
+								"io.buildpacks.builder.metadata",
+								`{"buildpacks": [{"id": "test.bp.one", "version": "1.0.0"}], "lifecycle": {"version": "1.2.3", "api": {"buildpack": "0.2", "platform": "0.2"}}}`,
+							))
+							h.AssertNil(t, builderImage.SetLabel(
+								"io.buildpacks.buildpack.layers",
+								`{"test.bp.one":{"1.0.0":{"api":"0.1","layerDiffID":"sha256:abc"}},"test.bp.two":{"2.0.0":{"api":"0.2","layerDiffID":"sha256:def"}}}`,
+							))
+						})
+
+						it("reports the incompatible buildpack", func() {
+							builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
+							h.AssertNil(t, err)
+							h.AssertEq(t, len(builderInfo.APIReport), 1)
+
+							entry := builderInfo.APIReport[0]
+							h.AssertEq(t, entry.Buildpack, dist.BuildpackInfo{ID: "test.bp.one", Version: "1.0.0"})
+							h.AssertEq(t, entry.API, "0.1")
+						})
+					})
+
+					when("the detection order contains a meta buildpack", func() {
+						it.Before(func() {
+							h.AssertNil(t, builderImage.SetLabel(
+								"io.buildpacks.buildpack.order",
+								`[{"group": [{"id": "meta.bp", "version": "1.0.0"}]}]`,
+							))
+							h.AssertNil(t, builderImage.SetLabel(
+								"io.buildpacks.buildpack.layers",
+								`{"meta.bp":{"1.0.0":{"layerDiffID":"sha256:meta","order":[{"group":[{"id":"child.bp.one","version":"1.1.1"},{"id":"child.bp.two","optional":true}]}]}},"child.bp.one":{"1.1.1":{"layerDiffID":"sha256:one","stacks":[{"id":"test.stack.id"}]}},"child.bp.two":{"2.2.2":{"layerDiffID":"sha256:two","stacks":[{"id":"test.stack.id"}]}}}`,
+							))
+						})
+
+						it("populates the order tree with the nested groups", func() {
+							builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
+							h.AssertNil(t, err)
+							h.AssertEq(t, len(builderInfo.OrderTree), 1)
+							h.AssertEq(t, len(builderInfo.OrderTree[0].Group), 1)
+
+							metaEntry := builderInfo.OrderTree[0].Group[0]
+							h.AssertEq(t, metaEntry.ID, "meta.bp")
+							h.AssertEq(t, metaEntry.Version, "1.0.0")
+							h.AssertEq(t, len(metaEntry.Groups), 1)
+
+							nested := metaEntry.Groups[0].Group
+							h.AssertEq(t, len(nested), 2)
+							h.AssertEq(t, nested[0].ID, "child.bp.one")
+							h.AssertEq(t, nested[0].Version, "1.1.1")
+							h.AssertEq(t, len(nested[0].Groups), 0)
+							h.AssertEq(t, nested[1].ID, "child.bp.two")
+							h.AssertEq(t, nested[1].Optional, true)
+						})
+					})
+
 					it("set the defaults run image mirrors", func() {
 						builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
 						h.AssertNil(t, err)
@@ -188,7 +264,7 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 
 	when("fetcher fails to fetch the image", func() {
 		it.Before(func() {
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, false).Return(nil, errors.New("some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, image.PullNever).Return(nil, errors.New("some-error"))
 		})
 
 		it("returns an error", func() {
@@ -201,7 +277,7 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 		it.Before(func() {
 			notFoundImage := fakes.NewImage("", "", nil)
 			notFoundImage.Delete()
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, false).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
 		})
 
 		it("return nil metadata", func() {
@@ -0,0 +1,76 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// BuildpackInfo summarizes a buildpack package: the package's main buildpack
+// plus every buildpack the package carries, dependencies included.
+type BuildpackInfo struct {
+	Buildpack  dist.BuildpackDescriptor
+	Buildpacks []dist.BuildpackDescriptor
+}
+
+// InspectBuildpack reads the buildpack metadata of a buildpack package. The
+// locator may be an image name, inspected in the daemon or a registry depending
+// on daemon, or a path or URL to a buildpack archive, for which daemon is
+// ignored. A nil info is returned when the image is not present.
+func (c *Client) InspectBuildpack(ctx context.Context, locator string, daemon bool) (*BuildpackInfo, error) {
+	if isLocalOrURIBuildpack(locator) {
+		blob, err := c.downloader.Download(ctx, locator)
+		if err != nil {
+			return nil, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(locator))
+		}
+
+		bp, err := dist.BuildpackFromRootBlob(blob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading buildpack from %s", style.Symbol(locator))
+		}
+
+		desc := bp.Descriptor()
+		return &BuildpackInfo{
+			Buildpack:  desc,
+			Buildpacks: []dist.BuildpackDescriptor{desc},
+		}, nil
+	}
+
+	mainBP, depBPs, err := extractPackagedBuildpacks(ctx, locator, c.imageFetcher, !daemon, image.PullNever)
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	info := &BuildpackInfo{Buildpack: mainBP.Descriptor()}
+	for _, bp := range append([]dist.Buildpack{mainBP}, depBPs...) {
+		info.Buildpacks = append(info.Buildpacks, bp.Descriptor())
+	}
+	sort.Slice(info.Buildpacks, func(i, j int) bool {
+		if info.Buildpacks[i].Info.ID != info.Buildpacks[j].Info.ID {
+			return info.Buildpacks[i].Info.ID < info.Buildpacks[j].Info.ID
+		}
+		return info.Buildpacks[i].Info.Version < info.Buildpacks[j].Info.Version
+	})
+
+	return info, nil
+}
+
+// isLocalOrURIBuildpack reports whether the locator names a buildpack archive
+// or directory rather than a package image.
+func isLocalOrURIBuildpack(locator string) bool {
+	if paths.IsURI(locator) {
+		return true
+	}
+	_, err := os.Stat(locator)
+	return err == nil
+}
@@ -0,0 +1,141 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/blob"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestInspectBuildpack(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "InspectBuildpack", testInspectBuildpack, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectBuildpack(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockDownloader   *testmocks.MockDownloader
+		mockController   *gomock.Controller
+		packageImage     *fakes.Image
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDownloader = testmocks.NewMockDownloader(mockController)
+
+		subject = &Client{
+			logger:       logging.NewLogWithWriters(&out, &out),
+			imageFetcher: mockImageFetcher,
+			downloader:   mockDownloader,
+		}
+
+		packageImage = fakes.NewImage("some/package", "", nil)
+		h.AssertNil(t, packageImage.SetLabel(
+			"io.buildpacks.buildpackage.metadata",
+			`{"id": "meta.bp", "version": "0.0.1", "stacks": [{"id": "some.stack.id"}]}`,
+		))
+		h.AssertNil(t, packageImage.SetLabel(
+			"io.buildpacks.buildpack.layers",
+			`{
+  "meta.bp": {
+    "0.0.1": {
+      "api": "0.2",
+      "order": [{"group": [{"id": "child.bp", "version": "1.2.3"}]}],
+      "layerDiffID": "sha256:aaaa"
+    }
+  },
+  "child.bp": {
+    "1.2.3": {
+      "api": "0.2",
+      "stacks": [{"id": "some.stack.id"}],
+      "layerDiffID": "sha256:bbbb"
+    }
+  }
+}`,
+		))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("the locator is a package image", func() {
+		for _, useDaemon := range []bool{true, false} {
+			useDaemon := useDaemon
+			when(fmt.Sprintf("daemon is %t", useDaemon), func() {
+				it.Before(func() {
+					mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/package", useDaemon, image.PullNever).Return(packageImage, nil)
+				})
+
+				it("returns the main buildpack and every packaged buildpack", func() {
+					info, err := subject.InspectBuildpack(context.TODO(), "some/package", useDaemon)
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, info.Buildpack.Info, dist.BuildpackInfo{ID: "meta.bp", Version: "0.0.1"})
+					h.AssertEq(t, info.Buildpack.API.String(), "0.2")
+					h.AssertEq(t, len(info.Buildpack.Order), 1)
+					h.AssertEq(t, info.Buildpack.Order[0].Group[0].ID, "child.bp")
+
+					h.AssertEq(t, len(info.Buildpacks), 2)
+					h.AssertEq(t, info.Buildpacks[0].Info, dist.BuildpackInfo{ID: "child.bp", Version: "1.2.3"})
+					h.AssertEq(t, info.Buildpacks[0].Stacks[0].ID, "some.stack.id")
+					h.AssertEq(t, info.Buildpacks[1].Info, dist.BuildpackInfo{ID: "meta.bp", Version: "0.0.1"})
+				})
+			})
+		}
+
+		when("the image is not present", func() {
+			it("returns nil info", func() {
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "missing/package", true, image.PullNever).Return(nil, image.ErrNotFound)
+
+				info, err := subject.InspectBuildpack(context.TODO(), "missing/package", true)
+				h.AssertNil(t, err)
+				h.AssertNil(t, info)
+			})
+		})
+
+		when("the image is not a buildpack package", func() {
+			it("errors", func() {
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", true, image.PullNever).Return(fakes.NewImage("some/app", "", nil), nil)
+
+				_, err := subject.InspectBuildpack(context.TODO(), "some/app", true)
+				h.AssertError(t, err, "could not find label 'io.buildpacks.buildpackage.metadata'")
+			})
+		})
+	})
+
+	when("the locator is a buildpack archive", func() {
+		it("returns the buildpack's descriptor", func() {
+			mockDownloader.EXPECT().
+				Download(gomock.Any(), "https://example.fake/bp-one.tgz").
+				Return(blob.NewBlob("testdata/buildpack"), nil)
+
+			info, err := subject.InspectBuildpack(context.TODO(), "https://example.fake/bp-one.tgz", true)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, info.Buildpack.Info, dist.BuildpackInfo{ID: "bp.one", Version: "1.2.3"})
+			h.AssertEq(t, info.Buildpack.API.String(), "0.3")
+			h.AssertEq(t, len(info.Buildpack.Stacks), 1)
+			h.AssertEq(t, info.Buildpack.Stacks[0].ID, "some.stack.id")
+			h.AssertEq(t, len(info.Buildpacks), 1)
+		})
+	})
+}
@@ -31,8 +31,23 @@ type layersMetadata struct {
 	Stack    lifecycle.StackMetadata    `json:"stack" toml:"stack"`
 }
 
+// ImageDigest returns the canonical reference of an image: the registry digest
+// reference when daemon is false, the daemon image ID otherwise.
+func (c *Client) ImageDigest(name string, daemon bool) (string, error) {
+	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, image.PullNever)
+	if err != nil {
+		return "", err
+	}
+
+	identifier, err := img.Identifier()
+	if err != nil {
+		return "", err
+	}
+	return identifier.String(), nil
+}
+
 func (c *Client) InspectImage(name string, daemon bool) (*ImageInfo, error) {
-	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, false)
+	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, image.PullNever)
 	if err != nil {
 		if errors.Cause(err) == image.ErrNotFound {
 			return nil, nil
@@ -113,9 +113,9 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 			when(fmt.Sprintf("daemon is %t", useDaemon), func() {
 				it.Before(func() {
 					if useDaemon {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", true, false).Return(fakeImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", true, image.PullNever).Return(fakeImage, nil)
 					} else {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", false, false).Return(fakeImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", false, image.PullNever).Return(fakeImage, nil)
 					}
 				})
 
@@ -295,7 +295,7 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 
 	when("the image doesn't exist", func() {
 		it("returns nil", func() {
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "not/some-image", true, false).Return(nil, image.ErrNotFound)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "not/some-image", true, image.PullNever).Return(nil, image.ErrNotFound)
 
 			info, err := subject.InspectImage("not/some-image", true)
 			h.AssertNil(t, err)
@@ -305,7 +305,7 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 
 	when("there is an error fetching the image", func() {
 		it("returns the error", func() {
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "not/some-image", true, false).Return(nil, errors.New("some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "not/some-image", true, image.PullNever).Return(nil, errors.New("some-error"))
 
 			_, err := subject.InspectImage("not/some-image", true)
 			h.AssertError(t, err, "some-error")
@@ -315,7 +315,7 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 	when("the image is missing labels", func() {
 		it("returns empty data", func() {
 			mockImageFetcher.EXPECT().
-				Fetch(gomock.Any(), "missing/labels", true, false).
+				Fetch(gomock.Any(), "missing/labels", true, image.PullNever).
 				Return(fakes.NewImage("missing/labels", "", nil), nil)
 			info, err := subject.InspectImage("missing/labels", true)
 			h.AssertNil(t, err)
@@ -329,7 +329,7 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 		it.Before(func() {
 			badImage = fakes.NewImage("bad/image", "", nil)
 			mockImageFetcher.EXPECT().
-				Fetch(gomock.Any(), "bad/image", true, false).
+				Fetch(gomock.Any(), "bad/image", true, image.PullNever).
 				Return(badImage, nil)
 		})
 
@@ -349,7 +349,7 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 	when("lifecycle version is 0.4.x or earlier", func() {
 		it("includes an empty base image reference", func() {
 			oldImage := fakes.NewImage("old/image", "", nil)
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "old/image", true, false).Return(oldImage, nil)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "old/image", true, image.PullNever).Return(oldImage, nil)
 
 			h.AssertNil(t, oldImage.SetLabel(
 				"io.buildpacks.lifecycle.metadata",
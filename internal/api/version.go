@@ -92,6 +92,61 @@ func (v *Version) SupportsVersion(o *Version) bool {
 	return false
 }
 
+// Range is an inclusive range of API versions. An empty bound leaves that
+// side of the range open.
+type Range struct {
+	min *Version
+	max *Version
+}
+
+// NewRange parses an inclusive version range from its bounds. Either bound may
+// be empty, which leaves that side of the range open.
+func NewRange(min, max string) (*Range, error) {
+	r := &Range{}
+
+	var err error
+	if min != "" {
+		if r.min, err = NewVersion(min); err != nil {
+			return nil, err
+		}
+	}
+	if max != "" {
+		if r.max, err = NewVersion(max); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.min != nil && r.max != nil && r.min.Compare(r.max) > 0 {
+		return nil, errors.Errorf("invalid api range: minimum %s is greater than maximum %s", style.Symbol(min), style.Symbol(max))
+	}
+
+	return r, nil
+}
+
+// Contains determines whether the given version falls within the range.
+func (r *Range) Contains(v *Version) bool {
+	if r.min != nil && v.Compare(r.min) < 0 {
+		return false
+	}
+	if r.max != nil && v.Compare(r.max) > 0 {
+		return false
+	}
+	return true
+}
+
+func (r *Range) String() string {
+	switch {
+	case r.min == nil && r.max == nil:
+		return "any"
+	case r.min == nil:
+		return fmt.Sprintf("<= %s", r.max)
+	case r.max == nil:
+		return fmt.Sprintf(">= %s", r.min)
+	default:
+		return fmt.Sprintf("%s - %s", r.min, r.max)
+	}
+}
+
 func (v *Version) Equal(o *Version) bool {
 	return v.Compare(o) == 0
 }
@@ -3,6 +3,7 @@ package api_test
 import (
 	"testing"
 
+	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -11,6 +12,8 @@ import (
 )
 
 func TestAPIVersion(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
 	spec.Run(t, "APIVersion", testAPIVersion, spec.Parallel(), spec.Report(report.Terminal{}))
 }
 
@@ -92,4 +95,78 @@ func testAPIVersion(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("#NewRange", func() {
+		it("errors when the minimum is malformed", func() {
+			_, err := api.NewRange("not-a-version", "0.3")
+
+			h.AssertError(t, err, "could not parse 'not-a-version' as version")
+		})
+
+		it("errors when the maximum is malformed", func() {
+			_, err := api.NewRange("0.2", "not-a-version")
+
+			h.AssertError(t, err, "could not parse 'not-a-version' as version")
+		})
+
+		it("errors when the minimum is greater than the maximum", func() {
+			_, err := api.NewRange("0.3", "0.2")
+
+			h.AssertError(t, err, "invalid api range: minimum '0.3' is greater than maximum '0.2'")
+		})
+	})
+
+	when("Range#Contains", func() {
+		it("contains versions within the bounds", func() {
+			subject, err := api.NewRange("0.2", "0.4")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, subject.Contains(api.MustParse("0.2")), true)
+			h.AssertEq(t, subject.Contains(api.MustParse("0.3")), true)
+			h.AssertEq(t, subject.Contains(api.MustParse("0.4")), true)
+		})
+
+		it("excludes versions outside the bounds", func() {
+			subject, err := api.NewRange("0.2", "0.4")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, subject.Contains(api.MustParse("0.1")), false)
+			h.AssertEq(t, subject.Contains(api.MustParse("0.5")), false)
+			h.AssertEq(t, subject.Contains(api.MustParse("1.0")), false)
+		})
+
+		it("treats empty bounds as open", func() {
+			subject, err := api.NewRange("", "")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, subject.Contains(api.MustParse("0.1")), true)
+			h.AssertEq(t, subject.Contains(api.MustParse("9.9")), true)
+
+			subject, err = api.NewRange("0.2", "")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, subject.Contains(api.MustParse("0.1")), false)
+			h.AssertEq(t, subject.Contains(api.MustParse("9.9")), true)
+		})
+	})
+
+	when("Range#String", func() {
+		it("describes the bounds", func() {
+			subject, err := api.NewRange("0.2", "0.4")
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.String(), "0.2 - 0.4")
+
+			subject, err = api.NewRange("0.2", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.String(), ">= 0.2")
+
+			subject, err = api.NewRange("", "0.4")
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.String(), "<= 0.4")
+
+			subject, err = api.NewRange("", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.String(), "any")
+		})
+	})
 }
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
@@ -27,6 +28,12 @@ func ReadDirAsTar(srcDir, basePath string, uid, gid int, mode int64, normalizeMo
 	})
 }
 
+func ReadDirAsTarIgnoring(srcDir, basePath string, uid, gid int, mode int64, normalizeModTime bool, ignores ...*IgnoreMatcher) io.ReadCloser {
+	return GenerateTar(func(tw *tar.Writer) error {
+		return WriteDirToTarIgnoring(tw, srcDir, basePath, uid, gid, mode, normalizeModTime, ignores...)
+	})
+}
+
 func ReadZipAsTar(srcPath, basePath string, uid, gid int, mode int64, normalizeModTime bool) io.ReadCloser {
 	return GenerateTar(func(tw *tar.Writer) error {
 		return WriteZipToTar(tw, srcPath, basePath, uid, gid, mode, normalizeModTime)
@@ -147,6 +154,13 @@ func ReadTarEntry(rc io.Reader, entryPath string) (*tar.Header, []byte, error) {
 }
 
 func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64, normalizeModTime bool) error {
+	return WriteDirToTarIgnoring(tw, srcDir, basePath, uid, gid, mode, normalizeModTime)
+}
+
+// WriteDirToTarIgnoring writes srcDir to the tar writer, skipping any paths one
+// of the ignore matchers excludes. Excluded directories are skipped entirely,
+// so their contents cannot be re-included by negated patterns.
+func WriteDirToTarIgnoring(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64, normalizeModTime bool, ignores ...*IgnoreMatcher) error {
 	return filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -163,6 +177,10 @@ func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 				return err
 			}
 
+			if err := checkSymlinkCycle(file); err != nil {
+				return err
+			}
+
 			header, err = tar.FileInfoHeader(fi, target)
 			if err != nil {
 				return err
@@ -181,6 +199,15 @@ func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 			return nil
 		}
 
+		for _, matcher := range ignores {
+			if matcher != nil && matcher.Ignore(filepath.ToSlash(relPath), fi.IsDir()) {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
 		header.Name = filepath.ToSlash(filepath.Join(basePath, relPath))
 		finalizeHeader(header, uid, gid, mode, normalizeModTime)
 
@@ -204,6 +231,49 @@ func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 	})
 }
 
+// checkSymlinkCycle fails with an error naming the offending path when a
+// symlink is part of a cycle: it points at itself or an ancestor directory,
+// or a chain of links eventually leads back to one already visited. Dangling
+// symlinks are not an error and are preserved as-is.
+func checkSymlinkCycle(file string) error {
+	seen := map[string]struct{}{}
+
+	link, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+
+	current := link
+	for {
+		if _, ok := seen[current]; ok {
+			return errors.Errorf("symlink cycle detected at '%s'", file)
+		}
+		seen[current] = struct{}{}
+
+		fi, err := os.Lstat(current)
+		if err != nil {
+			return nil
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		target, err := os.Readlink(current)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(current), target)
+		}
+		target = filepath.Clean(target)
+
+		if target == link || strings.HasPrefix(link, target+string(filepath.Separator)) {
+			return errors.Errorf("symlink cycle detected at '%s'", file)
+		}
+		current = target
+	}
+}
+
 func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode int64, normalizeModTime bool) error {
 	zipReader, err := zip.OpenReader(srcZip)
 	if err != nil {
@@ -311,3 +381,56 @@ func IsZip(file io.Reader) (bool, error) {
 
 	return bytes.Equal(b, []byte("\x50\x4B\x03\x04")), nil
 }
+
+// ExtractTar unpacks a tar stream into destDir, refusing entries that would
+// escape it.
+func ExtractTar(reader io.Reader, destDir string) error {
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "invalid tar archive")
+		}
+
+		name := path.Clean(header.Name)
+		if name == "." || name == "/" {
+			continue
+		}
+		if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return errors.Errorf("entry '%s' is outside of the destination directory", header.Name)
+		}
+
+		dest := filepath.Join(destDir, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, dest); err != nil {
+				return err
+			}
+		default:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			fh, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(fh, tr); err != nil {
+				fh.Close()
+				return err
+			}
+			if err := fh.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
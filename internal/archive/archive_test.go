@@ -2,6 +2,7 @@ package archive_test
 
 import (
 	"archive/tar"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"math/rand"
@@ -183,6 +184,27 @@ func testArchive(t *testing.T, when spec.G, it spec.S) {
 					h.HasModTime(archive.NormalizedDateTime),
 				)
 			})
+
+			it("produces byte-identical archives regardless of mod times", func() {
+				srcCopy := filepath.Join(tmpDir, "src-copy")
+				h.AssertNil(t, os.MkdirAll(srcCopy, 0755))
+				h.RecursiveCopy(t, src, srcCopy)
+
+				tarBytes := func() []byte {
+					var buf bytes.Buffer
+					tw := tar.NewWriter(&buf)
+					h.AssertNil(t, archive.WriteDirToTar(tw, srcCopy, "/foo", 1234, 2345, 0777, true))
+					h.AssertNil(t, tw.Close())
+					return buf.Bytes()
+				}
+
+				first := tarBytes()
+
+				later := time.Now().Add(time.Hour)
+				h.AssertNil(t, os.Chtimes(filepath.Join(srcCopy, "some-file.txt"), later, later))
+
+				h.AssertEq(t, tarBytes(), first)
+			})
 		})
 
 		when("is posix", func() {
@@ -241,6 +263,55 @@ func testArchive(t *testing.T, when spec.G, it spec.S) {
 					verify.noMoreFilesExist()
 				})
 			})
+
+			when("a symlink loop is present", func() {
+				var tmpSrcDir string
+
+				it.Before(func() {
+					var err error
+					tmpSrcDir, err = ioutil.TempDir("", "symlink-loop-test")
+					h.AssertNil(t, err)
+				})
+
+				it.After(func() {
+					os.RemoveAll(tmpSrcDir)
+				})
+
+				it("fails on a self-referencing symlink", func() {
+					h.AssertNil(t, os.Symlink("a", filepath.Join(tmpSrcDir, "a")))
+
+					tw := tar.NewWriter(ioutil.Discard)
+					err := archive.WriteDirToTar(tw, tmpSrcDir, "/foo", 1234, 2345, 0777, true)
+					h.AssertError(t, err, "symlink cycle detected at")
+					h.AssertContains(t, err.Error(), filepath.Join(tmpSrcDir, "a"))
+				})
+
+				it("fails on symlinks that point at each other", func() {
+					h.AssertNil(t, os.Symlink("b", filepath.Join(tmpSrcDir, "a")))
+					h.AssertNil(t, os.Symlink("a", filepath.Join(tmpSrcDir, "b")))
+
+					tw := tar.NewWriter(ioutil.Discard)
+					err := archive.WriteDirToTar(tw, tmpSrcDir, "/foo", 1234, 2345, 0777, true)
+					h.AssertError(t, err, "symlink cycle detected at")
+				})
+
+				it("fails on a symlink to an ancestor directory", func() {
+					h.AssertNil(t, os.Mkdir(filepath.Join(tmpSrcDir, "sub"), 0755))
+					h.AssertNil(t, os.Symlink("..", filepath.Join(tmpSrcDir, "sub", "link")))
+
+					tw := tar.NewWriter(ioutil.Discard)
+					err := archive.WriteDirToTar(tw, tmpSrcDir, "/foo", 1234, 2345, 0777, true)
+					h.AssertError(t, err, "symlink cycle detected at")
+					h.AssertContains(t, err.Error(), filepath.Join(tmpSrcDir, "sub", "link"))
+				})
+
+				it("allows dangling symlinks", func() {
+					h.AssertNil(t, os.Symlink("does-not-exist", filepath.Join(tmpSrcDir, "a")))
+
+					tw := tar.NewWriter(ioutil.Discard)
+					h.AssertNil(t, archive.WriteDirToTar(tw, tmpSrcDir, "/foo", 1234, 2345, 0777, true))
+				})
+			})
 		})
 	})
 
@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IgnoreMatcher holds an ordered list of gitignore-style patterns and decides
+// whether paths relative to a source directory should be excluded from an archive.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+	include  bool
+}
+
+type ignorePattern struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ReadIgnoreFile parses the ignore file at the given path, returning a nil
+// matcher when the file does not exist.
+func ReadIgnoreFile(path string) (*IgnoreMatcher, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	matcher, err := NewIgnoreMatcher(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading ignore file '%s'", path)
+	}
+	return matcher, nil
+}
+
+// NewIgnoreMatcher parses gitignore-syntax patterns, one per line. Blank lines
+// and lines starting with '#' are skipped, a leading '!' negates a pattern, a
+// trailing '/' restricts it to directories, and patterns containing a '/' are
+// anchored to the source directory root.
+func NewIgnoreMatcher(reader io.Reader) (*IgnoreMatcher, error) {
+	return newMatcher(reader, false)
+}
+
+// NewIncludeMatcher parses patterns like NewIgnoreMatcher but inverts the
+// result: paths are excluded unless they, or one of their parent directories,
+// match a pattern. Directories themselves are never excluded, so their
+// contents can still be considered.
+func NewIncludeMatcher(reader io.Reader) (*IgnoreMatcher, error) {
+	return newMatcher(reader, true)
+}
+
+func newMatcher(reader io.Reader, include bool) (*IgnoreMatcher, error) {
+	matcher := &IgnoreMatcher{include: include}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		p.anchored = strings.Contains(line, "/")
+		p.pattern = strings.TrimPrefix(line, "/")
+
+		if _, err := path.Match(p.pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid ignore pattern '%s'", scanner.Text())
+		}
+		matcher.patterns = append(matcher.patterns, p)
+	}
+	return matcher, scanner.Err()
+}
+
+// Ignore reports whether the slash-separated path relative to the source
+// directory matches the patterns. The last matching pattern wins, so a negated
+// pattern can re-include a path excluded by an earlier one. For an include
+// matcher the decision is inverted: a file is ignored unless it or one of its
+// parent directories matches.
+func (m *IgnoreMatcher) Ignore(relPath string, isDir bool) bool {
+	if m.include {
+		curIsDir := isDir
+		for cur := relPath; cur != "." && cur != ""; cur = path.Dir(cur) {
+			for _, p := range m.patterns {
+				if p.dirOnly && !curIsDir {
+					continue
+				}
+				if p.match(cur) {
+					return false
+				}
+			}
+			curIsDir = true
+		}
+		return !isDir
+	}
+
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.match(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+func (p ignorePattern) match(relPath string) bool {
+	if p.anchored {
+		matched, _ := path.Match(p.pattern, relPath)
+		return matched
+	}
+	matched, _ := path.Match(p.pattern, path.Base(relPath))
+	return matched
+}
@@ -0,0 +1,174 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestIgnore(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Ignore", testIgnore, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testIgnore(t *testing.T, when spec.G, it spec.S) {
+	when("#NewIgnoreMatcher", func() {
+		it("skips blank lines and comments", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("\n# a comment\n*.log\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("app.log", false), true)
+			h.AssertEq(t, matcher.Ignore("app.txt", false), false)
+		})
+
+		it("rejects invalid patterns", func() {
+			_, err := archive.NewIgnoreMatcher(strings.NewReader("[\n"))
+			h.AssertError(t, err, "invalid ignore pattern '['")
+		})
+	})
+
+	when("#Ignore", func() {
+		it("matches basenames at any depth", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("node_modules\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("node_modules", true), true)
+			h.AssertEq(t, matcher.Ignore("sub/node_modules", true), true)
+			h.AssertEq(t, matcher.Ignore("node_modules_backup", true), false)
+		})
+
+		it("anchors patterns containing a slash to the root", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("/secrets.txt\nbuild/output\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("secrets.txt", false), true)
+			h.AssertEq(t, matcher.Ignore("sub/secrets.txt", false), false)
+			h.AssertEq(t, matcher.Ignore("build/output", true), true)
+			h.AssertEq(t, matcher.Ignore("sub/build/output", true), false)
+		})
+
+		it("restricts trailing-slash patterns to directories", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("build/\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("build", true), true)
+			h.AssertEq(t, matcher.Ignore("build", false), false)
+		})
+
+		it("lets the last matching pattern win", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("*.log\n!important.log\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("debug.log", false), true)
+			h.AssertEq(t, matcher.Ignore("important.log", false), false)
+		})
+
+		it("is case-sensitive", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("*.log\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("app.LOG", false), false)
+		})
+	})
+
+	when("#NewIncludeMatcher", func() {
+		it("ignores files unless they match a pattern", func() {
+			matcher, err := archive.NewIncludeMatcher(strings.NewReader("*.jar\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("app.jar", false), false)
+			h.AssertEq(t, matcher.Ignore("sub/app.jar", false), false)
+			h.AssertEq(t, matcher.Ignore("app.go", false), true)
+		})
+
+		it("keeps the contents of matching directories", func() {
+			matcher, err := archive.NewIncludeMatcher(strings.NewReader("src/\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("src/deep/app.go", false), false)
+			h.AssertEq(t, matcher.Ignore("other/app.go", false), true)
+		})
+
+		it("never ignores directories themselves", func() {
+			matcher, err := archive.NewIncludeMatcher(strings.NewReader("*.jar\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, matcher.Ignore("sub", true), false)
+		})
+
+		it("rejects invalid patterns", func() {
+			_, err := archive.NewIncludeMatcher(strings.NewReader("[\n"))
+			h.AssertError(t, err, "invalid ignore pattern '['")
+		})
+	})
+
+	when("#ReadIgnoreFile", func() {
+		it("returns a nil matcher when the file does not exist", func() {
+			matcher, err := archive.ReadIgnoreFile(filepath.Join("testdata", "does-not-exist"))
+			h.AssertNil(t, err)
+			h.AssertNil(t, matcher)
+		})
+	})
+
+	when("#ReadDirAsTarIgnoring", func() {
+		var tmpDir string
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "tar-ignore-test")
+			h.AssertNil(t, err)
+
+			for _, dir := range []string{"node_modules/some-dep", "src"} {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+			}
+			for _, file := range []string{"main.go", "debug.log", "important.log", "node_modules/some-dep/index.js", "src/app.go"} {
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, file), []byte("content"), 0644))
+			}
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("excludes matching paths from the tar headers", func() {
+			matcher, err := archive.NewIgnoreMatcher(strings.NewReader("node_modules/\n*.log\n!important.log\n"))
+			h.AssertNil(t, err)
+
+			rc := archive.ReadDirAsTarIgnoring(tmpDir, "/app", 0, 0, -1, true, matcher)
+			defer rc.Close()
+
+			names := tarEntryNames(t, rc)
+			h.AssertSliceContains(t, names, "/app/main.go", "/app/important.log", "/app/src", "/app/src/app.go")
+			for _, name := range names {
+				h.AssertNotContains(t, name, "node_modules")
+				h.AssertNotContains(t, name, "debug.log")
+			}
+		})
+
+		it("includes everything when the matcher is nil", func() {
+			rc := archive.ReadDirAsTarIgnoring(tmpDir, "/app", 0, 0, -1, true, nil)
+			defer rc.Close()
+
+			names := tarEntryNames(t, rc)
+			h.AssertSliceContains(t, names, "/app/debug.log", "/app/node_modules/some-dep/index.js")
+		})
+	})
+}
+
+func tarEntryNames(t *testing.T, rc io.Reader) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		h.AssertNil(t, err)
+		names = append(names, header.Name)
+	}
+	return names
+}
@@ -62,6 +62,27 @@ func (b blob) Open() (r io.ReadCloser, err error) {
 	return rc, nil
 }
 
+// ephemeralBlob is backed by a file nothing else owns, e.g. a cache-disabled
+// download: closing the reader returned by Open removes the backing file.
+type ephemeralBlob struct {
+	blob
+}
+
+func newEphemeralBlob(path string) Blob {
+	return &ephemeralBlob{blob: blob{path: path}}
+}
+
+func (b *ephemeralBlob) Open() (io.ReadCloser, error) {
+	rc, err := b.blob.Open()
+	if err != nil {
+		return nil, err
+	}
+	return ioutils.NewReadCloserWrapper(rc, func() error {
+		defer os.Remove(b.path)
+		return rc.Close()
+	}), nil
+}
+
 func isGZip(file io.ReadSeeker) (bool, error) {
 	b := make([]byte, 3)
 	if _, err := file.Seek(0, 0); err != nil {
@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync/atomic"
+	"time"
 
 	"github.com/mitchellh/ioprogress"
 	"github.com/pkg/errors"
@@ -21,19 +23,50 @@ import (
 
 const (
 	cacheDirPrefix = "c"
-	cacheVersion   = "2"
+	cacheVersion   = "3"
+
+	checksumExt = ".sha256"
 )
 
+// DefaultDownloadTimeout bounds how long an HTTP download may go without
+// receiving any data before it is abandoned.
+const DefaultDownloadTimeout = 30 * time.Second
+
 type downloader struct {
-	logger       logging.Logger
-	baseCacheDir string
+	logger        logging.Logger
+	baseCacheDir  string
+	cacheDisabled bool
+	timeout       time.Duration
+}
+
+type DownloaderOption func(d *downloader)
+
+// WithCacheDisabled makes the downloader fetch blobs on every invocation instead of
+// reusing previously downloaded artifacts.
+func WithCacheDisabled() DownloaderOption {
+	return func(d *downloader) {
+		d.cacheDisabled = true
+	}
+}
+
+// WithDownloadTimeout overrides how long an HTTP download may stall without
+// receiving data before it fails. Zero disables the limit.
+func WithDownloadTimeout(timeout time.Duration) DownloaderOption {
+	return func(d *downloader) {
+		d.timeout = timeout
+	}
 }
 
-func NewDownloader(logger logging.Logger, baseCacheDir string) *downloader { //nolint:golint,gosimple
-	return &downloader{
+func NewDownloader(logger logging.Logger, baseCacheDir string, opts ...DownloaderOption) *downloader { //nolint:golint,gosimple
+	d := &downloader{
 		logger:       logger,
 		baseCacheDir: baseCacheDir,
+		timeout:      DefaultDownloadTimeout,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, error) {
@@ -44,11 +77,15 @@ func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, erro
 		}
 
 		var path string
+		var ephemeral bool
 		switch parsedURL.Scheme {
 		case "file":
 			path, err = paths.URIToFilePath(pathOrURI)
 		case "http", "https":
 			path, err = d.handleHTTP(ctx, pathOrURI)
+			// with the cache disabled nothing else owns the downloaded file,
+			// so the blob removes it once it has been consumed
+			ephemeral = d.cacheDisabled
 		default:
 			err = fmt.Errorf("unsupported protocol %s in URI %s", style.Symbol(parsedURL.Scheme), style.Symbol(pathOrURI))
 		}
@@ -56,6 +93,9 @@ func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, erro
 			return nil, err
 		}
 
+		if ephemeral {
+			return newEphemeralBlob(path), nil
+		}
 		return &blob{path: path}, nil
 	}
 
@@ -74,6 +114,10 @@ func (d *downloader) handleFile(path string) string {
 }
 
 func (d *downloader) handleHTTP(ctx context.Context, uri string) (string, error) {
+	if d.cacheDisabled {
+		return d.downloadUncached(ctx, uri)
+	}
+
 	cacheDir := d.versionedCacheDir()
 
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -97,8 +141,17 @@ func (d *downloader) handleHTTP(ctx context.Context, uri string) (string, error)
 		etag = string(bytes)
 	}
 
-	reader, etag, err := d.downloadAsStream(ctx, uri, etag)
+	if etag != "" && !d.cachedBlobIsValid(cachePath) {
+		d.logger.Debugf("Cached version of %s failed checksum validation; re-downloading", style.Symbol(uri))
+		etag = ""
+	}
+
+	reader, remoteEtag, err := d.downloadAsStream(ctx, uri, etag)
 	if err != nil {
+		if etag != "" && d.cachedBlobIsValid(cachePath) {
+			d.logger.Warnf("Failed to check %s for updates; using cached version", style.Symbol(uri))
+			return cachePath, nil
+		}
 		return "", err
 	} else if reader == nil {
 		return cachePath, nil
@@ -111,21 +164,82 @@ func (d *downloader) handleHTTP(ctx context.Context, uri string) (string, error)
 	}
 	defer fh.Close()
 
-	_, err = io.Copy(fh, reader)
+	hasher := sha256.New()
+	_, err = io.Copy(fh, io.TeeReader(reader, hasher))
 	if err != nil {
 		return "", errors.Wrap(err, "writing cache")
 	}
 
-	if err = ioutil.WriteFile(etagFile, []byte(etag), 0744); err != nil {
+	if err = ioutil.WriteFile(cachePath+checksumExt, []byte(fmt.Sprintf("%x", hasher.Sum(nil))), 0744); err != nil {
+		return "", errors.Wrap(err, "writing checksum")
+	}
+
+	if err = ioutil.WriteFile(etagFile, []byte(remoteEtag), 0744); err != nil {
 		return "", errors.Wrap(err, "writing etag")
 	}
 
 	return cachePath, nil
 }
 
+// downloadUncached fetches the blob into a scratch file under the cache dir;
+// the returned blob is ephemeral, so the file only lives until it is consumed.
+func (d *downloader) downloadUncached(ctx context.Context, uri string) (string, error) {
+	reader, _, err := d.downloadAsStream(ctx, uri, "")
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(d.baseCacheDir, 0755); err != nil {
+		return "", err
+	}
+	fh, err := ioutil.TempFile(d.baseCacheDir, "pack-blob")
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+
+	if _, err = io.Copy(fh, reader); err != nil {
+		os.Remove(fh.Name())
+		return "", errors.Wrap(err, "writing blob")
+	}
+
+	return fh.Name(), nil
+}
+
+// cachedBlobIsValid reports whether the cached blob matches the checksum recorded when it
+// was downloaded. A missing or mismatched checksum invalidates the cached copy.
+func (d *downloader) cachedBlobIsValid(cachePath string) bool {
+	expected, err := ioutil.ReadFile(cachePath + checksumExt)
+	if err != nil {
+		return false
+	}
+
+	fh, err := os.Open(cachePath)
+	if err != nil {
+		return false
+	}
+	defer fh.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, fh); err != nil {
+		return false
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum(nil)) == string(expected)
+}
+
 func (d *downloader) downloadAsStream(ctx context.Context, uri string, etag string) (io.ReadCloser, string, error) {
+	watchdog := newStallWatchdog(uri, d.timeout)
+	if watchdog != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		watchdog.start(cancel)
+	}
+
 	req, err := http.NewRequest("GET", uri, nil)
 	if err != nil {
+		watchdog.stop()
 		return nil, "", err
 	}
 	req = req.WithContext(ctx)
@@ -136,14 +250,19 @@ func (d *downloader) downloadAsStream(ctx context.Context, uri string, etag stri
 
 	resp, err := (&http.Client{}).Do(req) //nolint:bodyclose
 	if err != nil {
+		err = watchdog.translate(err)
+		watchdog.stop()
 		return nil, "", err
 	}
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		d.logger.Infof("Downloading from %s", style.Symbol(uri))
-		return withProgress(logging.GetWriterForLevel(d.logger, logging.InfoLevel), resp.Body, resp.ContentLength), resp.Header.Get("Etag"), nil
+		body := watchdog.watch(resp.Body)
+		return withProgress(logging.GetWriterForLevel(d.logger, logging.InfoLevel), body, resp.ContentLength), resp.Header.Get("Etag"), nil
 	}
 
+	watchdog.stop()
+
 	if resp.StatusCode == 304 {
 		d.logger.Debugf("Using cached version of %s", style.Symbol(uri))
 		return nil, etag, nil
@@ -155,6 +274,77 @@ func (d *downloader) downloadAsStream(ctx context.Context, uri string, etag stri
 	)
 }
 
+// stallWatchdog cancels a download when no data arrives for its timeout. The
+// timer resets on every read, so slow-but-active downloads are not limited. A
+// nil watchdog (no timeout configured) is a no-op.
+type stallWatchdog struct {
+	uri     string
+	timeout time.Duration
+	started time.Time
+	timer   *time.Timer
+	expired int32
+}
+
+func newStallWatchdog(uri string, timeout time.Duration) *stallWatchdog {
+	if timeout <= 0 {
+		return nil
+	}
+	return &stallWatchdog{uri: uri, timeout: timeout}
+}
+
+func (w *stallWatchdog) start(cancel context.CancelFunc) {
+	w.started = time.Now()
+	w.timer = time.AfterFunc(w.timeout, func() {
+		atomic.StoreInt32(&w.expired, 1)
+		cancel()
+	})
+}
+
+func (w *stallWatchdog) stop() {
+	if w == nil {
+		return
+	}
+	w.timer.Stop()
+}
+
+// translate replaces the context-cancellation error produced by an expired
+// watchdog with one naming the URI and how long the download had been running.
+func (w *stallWatchdog) translate(err error) error {
+	if w == nil || atomic.LoadInt32(&w.expired) == 0 {
+		return err
+	}
+	return errors.Errorf(
+		"download from %s timed out: no data received for %s (%s elapsed)",
+		style.Symbol(w.uri), w.timeout, time.Since(w.started).Round(time.Millisecond),
+	)
+}
+
+func (w *stallWatchdog) watch(rc io.ReadCloser) io.ReadCloser {
+	if w == nil {
+		return rc
+	}
+	return &watchdogReader{ReadCloser: rc, watchdog: w}
+}
+
+type watchdogReader struct {
+	io.ReadCloser
+	watchdog *stallWatchdog
+}
+
+func (r *watchdogReader) Read(p []byte) (int, error) {
+	r.watchdog.timer.Reset(r.watchdog.timeout)
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = r.watchdog.translate(err)
+	}
+	return n, err
+}
+
+func (r *watchdogReader) Close() error {
+	r.watchdog.stop()
+	return r.ReadCloser.Close()
+}
+
 func withProgress(writer io.Writer, rc io.ReadCloser, length int64) io.ReadCloser {
 	return &progressReader{
 		Closer: rc,
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/heroku/color"
 	"github.com/onsi/gomega/ghttp"
@@ -136,6 +137,114 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("server is unreachable after a successful download", func() {
+				it.Before(func() {
+					server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Add("ETag", "A")
+						http.ServeFile(w, r, tgz)
+					})
+				})
+
+				it("uses the cached version", func() {
+					b, err := subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+
+					server.Close()
+
+					b, err = subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+				})
+			})
+
+			when("cached blob fails checksum validation", func() {
+				it.Before(func() {
+					server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Add("ETag", "A")
+						http.ServeFile(w, r, tgz)
+					})
+
+					server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Add("ETag", "A")
+						http.ServeFile(w, r, tgz)
+					})
+				})
+
+				it("re-downloads the blob", func() {
+					b, err := subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+
+					corruptCachedBlobs(t, cacheDir)
+
+					b, err = subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+				})
+			})
+
+			when("cache is disabled", func() {
+				it.Before(func() {
+					subject = blob.NewDownloader(logging.New(ioutil.Discard), cacheDir, blob.WithCacheDisabled())
+
+					for i := 0; i < 2; i++ {
+						server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+							h.AssertEq(t, r.Header.Get("If-None-Match"), "")
+							http.ServeFile(w, r, tgz)
+						})
+					}
+				})
+
+				it("downloads on every invocation", func() {
+					b, err := subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+
+					b, err = subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+				})
+
+				it("removes the downloaded file once the blob is consumed", func() {
+					b, err := subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+
+					b, err = subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+
+					var leftover []string
+					h.AssertNil(t, filepath.Walk(cacheDir, func(path string, fi os.FileInfo, err error) error {
+						if err != nil {
+							return err
+						}
+						if !fi.IsDir() {
+							leftover = append(leftover, path)
+						}
+						return nil
+					}))
+					h.AssertEq(t, len(leftover), 0)
+				})
+			})
+
+			when("the download stalls", func() {
+				it.Before(func() {
+					subject = blob.NewDownloader(logging.New(ioutil.Discard), cacheDir, blob.WithDownloadTimeout(100*time.Millisecond))
+
+					server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+						<-r.Context().Done()
+					})
+				})
+
+				it("fails naming the uri", func() {
+					_, err := subject.Download(context.TODO(), uri)
+					h.AssertError(t, err, "timed out")
+					h.AssertError(t, err, uri)
+				})
+			})
+
 			when("uri is invalid", func() {
 				when("uri file is not found", func() {
 					it.Before(func() {
@@ -162,6 +271,22 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 	})
 }
 
+// corruptCachedBlobs rewrites the contents of every cached blob (files without an
+// extension) so that they no longer match their recorded checksums.
+func corruptCachedBlobs(t *testing.T, cacheDir string) {
+	t.Helper()
+	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != "" {
+			return nil
+		}
+		return ioutil.WriteFile(path, []byte("corrupt"), 0744)
+	})
+	h.AssertNil(t, err)
+}
+
 func assertBlob(t *testing.T, b blob.Blob) {
 	t.Helper()
 	r, err := b.Open()
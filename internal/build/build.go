@@ -2,15 +2,23 @@ package build
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/style"
@@ -26,22 +34,39 @@ type Lifecycle struct {
 	builder            *builder.Builder
 	logger             logging.Logger
 	docker             client.CommonAPIClient
+	keychain           authn.Keychain
 	appPath            string
 	appOnce            *sync.Once
+	previousImage      string
 	httpProxy          string
 	httpsProxy         string
 	noProxy            string
 	version            string
 	platformAPIVersion string
+	timeout            time.Duration
+	phaseTimeouts      map[string]time.Duration
+	binDir             string
+	normalizeModTime   bool
+	appIgnore          *archive.IgnoreMatcher
+	volumes            []string
+	creationTime       *time.Time
+	workspace          string
+	reportPath         string
+	groupPath          string
+	defaultProcessType string
+	lifecycleImage     string
+	lifecycleVolume    string
+	uid                int
+	gid                int
+	overrideUser       bool
+	interactive        bool
+	daemonType         DaemonType
+	paths              ContainerPaths
+	logArtifact        *LogArtifact
 	LayersVolume       string
 	AppVolume          string
 }
 
-type Cache interface {
-	Name() string
-	Clear(context.Context) error
-}
-
 func init() {
 	rand.Seed(time.Now().UTC().UnixNano())
 }
@@ -51,16 +76,40 @@ func NewLifecycle(docker client.CommonAPIClient, logger logging.Logger) *Lifecyc
 }
 
 type LifecycleOptions struct {
-	AppPath    string
-	Image      name.Reference
-	Builder    *builder.Builder
-	RunImage   string
-	ClearCache bool
-	Publish    bool
-	HTTPProxy  string
-	HTTPSProxy string
-	NoProxy    string
-	Network    string
+	AppPath            string
+	Image              name.Reference
+	AdditionalTags     []string
+	Builder            *builder.Builder
+	Keychain           authn.Keychain // resolves registry credentials for phases contacting a registry; nil means the docker config file
+	RunImage           string
+	PreviousImage      string // analyze layer metadata from this image instead of Image, for layer reuse after a rename
+	ClearCache         cache.ClearPolicy
+	Publish            bool
+	HTTPProxy          string
+	HTTPSProxy         string
+	NoProxy            string
+	Network            string
+	Timeout            time.Duration
+	PhaseTimeouts      map[string]time.Duration
+	LifecycleBinDir    string
+	LogArtifactPath    string
+	CacheImage         string
+	CacheDir           string
+	CacheVolume        string
+	NormalizeModTime   bool
+	AppIgnore          *archive.IgnoreMatcher
+	Volumes            []string
+	CreationTime       *time.Time
+	Workspace          string
+	ReportPath         string
+	DetectOnly         bool   // stop after the detect phase; nothing is built or exported
+	TrustBuilder       bool   // run the combined creator phase instead of separate phase containers
+	Interactive        bool   // keep a failed phase's container and volumes around for inspection
+	GroupPath          string // write the group the detector selected (group.toml) to this path
+	DefaultProcessType string
+	LifecycleImage     string
+	UID                *int // run the lifecycle phases as this user ID instead of the builder's user
+	GID                *int // run the lifecycle phases as this group ID instead of the builder's group
 }
 
 // CombinedExporterCacher returns true if the lifecycle contains combined exporter/cacher phases and reversed analyzer/restorer phases.
@@ -68,83 +117,329 @@ func (l *Lifecycle) CombinedExporterCacher() bool {
 	return api.MustParse(l.platformAPIVersion).Compare(api.MustParse("0.2")) >= 0
 }
 
+// supportsDefaultProcess returns true if the lifecycle's exporter accepts a
+// -process-type override for the image's default process.
+func (l *Lifecycle) supportsDefaultProcess() bool {
+	return api.MustParse(l.platformAPIVersion).Compare(api.MustParse("0.2")) >= 0
+}
+
+// minLifecycleVersionSupportingCreator is the first lifecycle release shipping
+// the creator binary.
+const minLifecycleVersionSupportingCreator = "0.7.4"
+
+// SupportsCreator returns true if the lifecycle ships the creator binary,
+// which runs every phase in a single container.
+func (l *Lifecycle) SupportsCreator() bool {
+	return !semver.MustParse(l.version).LessThan(semver.MustParse(minLifecycleVersionSupportingCreator))
+}
+
 func (l *Lifecycle) Execute(ctx context.Context, opts LifecycleOptions) error {
+	timer := NewTimer()
 	l.Setup(opts)
 	defer l.Cleanup()
 
-	buildCache := cache.NewVolumeCache(opts.Image, "build", l.docker)
+	builderOS, err := l.builderOS(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "determining the OS of builder %s", style.Symbol(l.builder.Name()))
+	}
+	l.paths = PathsForOS(builderOS)
+
+	version, err := l.docker.ServerVersion(ctx)
+	if err != nil {
+		return errors.Wrap(err, "determining the daemon's version")
+	}
+	l.daemonType = DetectDaemonType(version)
+	if l.daemonType == DaemonTypePodman {
+		l.logger.Debug("Detected Podman daemon")
+	}
+
+	// An explicit UID/GID override wins over rootless detection.
+	if !l.overrideUser {
+		info, err := l.docker.Info(ctx)
+		if err != nil {
+			return errors.Wrap(err, "querying the daemon for rootless detection")
+		}
+		if IsRootlessDaemon(info) {
+			l.uid, l.gid = RootlessUserMapping(info, l.uid, l.gid)
+			l.overrideUser = true
+			l.logger.Debugf("Detected rootless daemon; running phases as user %d:%d", l.uid, l.gid)
+		}
+	}
+
+	if opts.LogArtifactPath != "" {
+		artifactFile, err := os.Create(opts.LogArtifactPath)
+		if err != nil {
+			return errors.Wrapf(err, "creating log artifact %s", style.Symbol(opts.LogArtifactPath))
+		}
+		defer artifactFile.Close()
+		l.logArtifact = NewLogArtifact(artifactFile)
+		defer func() { l.logArtifact = nil }()
+	}
+
+	var buildCache cache.Cache
+	if opts.CacheImage != "" {
+		cacheRef, err := name.ParseReference(opts.CacheImage, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid cache image name %s", style.Symbol(opts.CacheImage))
+		}
+		buildCache = cache.NewImageCache(cacheRef, l.docker)
+		l.logger.Debugf("Using build cache image %s", style.Symbol(buildCache.Name()))
+	} else if opts.CacheDir != "" {
+		buildCache = cache.NewDirCache(opts.CacheDir)
+		l.logger.Debugf("Using build cache dir %s", style.Symbol(buildCache.Name()))
+	} else if opts.CacheVolume != "" {
+		buildCache = cache.NewNamedVolumeCache(opts.CacheVolume, l.docker)
+		l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	} else {
+		buildCache = cache.NewVolumeCache(opts.Image, "build", l.docker)
+		l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	}
 	launchCache := cache.NewVolumeCache(opts.Image, "launch", l.docker)
-	l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
 
-	if opts.ClearCache {
+	cacheLock := cache.NewLock(buildCache.Name())
+	if err := cacheLock.Acquire(); err != nil {
+		return err
+	}
+	defer cacheLock.Release()
+
+	if opts.ClearCache.Build() {
+		l.reportReclaimedSize(ctx, "build", buildCache)
 		if err := buildCache.Clear(ctx); err != nil {
 			return errors.Wrap(err, "clearing build cache")
 		}
 		l.logger.Debugf("Build cache %s cleared", style.Symbol(buildCache.Name()))
 	}
+	if opts.ClearCache.Launch() {
+		l.reportReclaimedSize(ctx, "launch", launchCache)
+		if err := launchCache.Clear(ctx); err != nil {
+			return errors.Wrap(err, "clearing launch cache")
+		}
+		l.logger.Debugf("Launch cache %s cleared", style.Symbol(launchCache.Name()))
+	}
+
+	if l.daemonType == DaemonTypePodman {
+		// Docker creates named volumes referenced in binds implicitly;
+		// Podman's compatible API expects them to exist before a container
+		// mounts them. Cleared caches were just removed, so this runs after
+		// the clear steps.
+		if err := l.createPhaseVolumes(ctx, buildCache, launchCache); err != nil {
+			return err
+		}
+	}
+
+	if l.lifecycleImage != "" {
+		if err := l.populateLifecycleVolume(ctx); err != nil {
+			return errors.Wrapf(err, "preparing lifecycle image %s", style.Symbol(l.lifecycleImage))
+		}
+		l.logger.Debugf("Using lifecycle from image %s", style.Symbol(l.lifecycleImage))
+	}
+
+	if opts.TrustBuilder && !opts.DetectOnly {
+		if l.SupportsCreator() {
+			l.logger.Info(style.Step("CREATING"))
+			if err := timer.Record("CREATE", func() error { return l.Create(ctx, opts, buildCache, launchCache) }); err != nil {
+				return err
+			}
+
+			timer.Summarize(l.logger)
+			return nil
+		}
+		l.logger.Warnf("Builder is trusted but lifecycle %s does not have a creator; running the phases separately", style.Symbol(l.version))
+	}
 
 	l.logger.Info(style.Step("DETECTING"))
-	if err := l.Detect(ctx, opts.Network); err != nil {
+	if err := timer.Record("DETECT", func() error { return l.Detect(ctx, opts.Network) }); err != nil {
 		return err
 	}
 
+	if opts.DetectOnly {
+		return nil
+	}
+
 	if l.CombinedExporterCacher() {
 		l.logger.Info(style.Step("ANALYZING"))
-		if err := l.Analyze(ctx, opts.Image.Name(), buildCache.Name(), opts.Publish, opts.ClearCache); err != nil {
+		if err := timer.Record("ANALYZE", func() error {
+			return l.Analyze(ctx, opts.Image.Name(), buildCache, opts.Publish, opts.ClearCache.Build())
+		}); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("RESTORING"))
-		if opts.ClearCache {
+		if opts.ClearCache.Build() {
 			l.logger.Info("Skipping 'restore' due to clearing cache")
-		} else if err := l.Restore(ctx, buildCache.Name()); err != nil {
+		} else if err := timer.Record("RESTORE", func() error { return l.Restore(ctx, buildCache) }); err != nil {
 			return err
 		}
 	} else {
 		l.logger.Info(style.Step("RESTORING"))
-		if opts.ClearCache {
+		if opts.ClearCache.Build() {
 			l.logger.Info("Skipping 'restore' due to clearing cache")
-		} else if err := l.Restore(ctx, buildCache.Name()); err != nil {
+		} else if err := timer.Record("RESTORE", func() error { return l.Restore(ctx, buildCache) }); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("ANALYZING"))
-		if err := l.Analyze(ctx, opts.Image.Name(), buildCache.Name(), opts.Publish, opts.ClearCache); err != nil {
+		if err := timer.Record("ANALYZE", func() error {
+			return l.Analyze(ctx, opts.Image.Name(), buildCache, opts.Publish, opts.ClearCache.Build())
+		}); err != nil {
 			return err
 		}
 	}
 
 	l.logger.Info(style.Step("BUILDING"))
-	if err := l.Build(ctx, opts.Network); err != nil {
+	if err := timer.Record("BUILD", func() error { return l.Build(ctx, opts.Network) }); err != nil {
 		return err
 	}
 
 	l.logger.Info(style.Step("EXPORTING"))
-	if err := l.Export(ctx, opts.Image.Name(), opts.RunImage, opts.Publish, launchCache.Name(), buildCache.Name()); err != nil {
+	if err := timer.Record("EXPORT", func() error {
+		return l.Export(ctx, opts.Image.Name(), opts.RunImage, opts.Publish, launchCache.Name(), buildCache, opts.AdditionalTags)
+	}); err != nil {
 		return err
 	}
 
 	if !l.CombinedExporterCacher() {
 		l.logger.Info(style.Step("CACHING"))
-		if err := l.Cache(ctx, buildCache.Name()); err != nil {
+		if err := timer.Record("CACHE", func() error { return l.Cache(ctx, buildCache) }); err != nil {
 			return err
 		}
 	}
 
+	timer.Summarize(l.logger)
 	return nil
 }
 
+// reportReclaimedSize logs how much disk space clearing a cache is about to
+// free; sizes are best-effort, so failures only surface at debug level.
+func (l *Lifecycle) reportReclaimedSize(ctx context.Context, kind string, c cache.Cache) {
+	size, err := c.Size(ctx)
+	if err != nil {
+		l.logger.Debugf("Unable to determine size of %s cache %s: %s", kind, style.Symbol(c.Name()), err)
+		return
+	}
+	if size > 0 {
+		l.logger.Infof("Reclaiming approximately %s from %s cache %s", cache.FormatBytes(size), kind, style.Symbol(c.Name()))
+	}
+}
+
 func (l *Lifecycle) Setup(opts LifecycleOptions) {
 	l.LayersVolume = "pack-layers-" + randString(10)
 	l.AppVolume = "pack-app-" + randString(10)
 	l.appPath = opts.AppPath
 	l.appOnce = &sync.Once{}
+	l.previousImage = opts.PreviousImage
 	l.builder = opts.Builder
+	l.keychain = opts.Keychain
+	if l.keychain == nil {
+		l.keychain = authn.DefaultKeychain
+	}
 	l.httpProxy = opts.HTTPProxy
 	l.httpsProxy = opts.HTTPSProxy
 	l.noProxy = opts.NoProxy
 	l.version = opts.Builder.LifecycleDescriptor().Info.Version.String()
 	l.platformAPIVersion = opts.Builder.LifecycleDescriptor().API.PlatformVersion.String()
+	l.timeout = opts.Timeout
+	l.phaseTimeouts = opts.PhaseTimeouts
+	l.binDir = opts.LifecycleBinDir
+	l.normalizeModTime = opts.NormalizeModTime
+	l.appIgnore = opts.AppIgnore
+	l.volumes = opts.Volumes
+	l.creationTime = opts.CreationTime
+	l.workspace = opts.Workspace
+	l.reportPath = opts.ReportPath
+	l.groupPath = opts.GroupPath
+	l.defaultProcessType = opts.DefaultProcessType
+	l.lifecycleImage = opts.LifecycleImage
+	l.lifecycleVolume = ""
+	if opts.LifecycleImage != "" {
+		l.lifecycleVolume = "pack-lifecycle-" + randString(10)
+	}
+	l.uid = opts.Builder.UID
+	l.gid = opts.Builder.GID
+	l.overrideUser = opts.UID != nil || opts.GID != nil
+	l.interactive = opts.Interactive
+	if opts.UID != nil {
+		l.uid = *opts.UID
+	}
+	if opts.GID != nil {
+		l.gid = *opts.GID
+	}
+	l.paths = PathsForOS("linux")
+}
+
+// builderOS returns the OS of the builder image, so Windows builders get the
+// 'C:\'-rooted lifecycle paths.
+func (l *Lifecycle) builderOS(ctx context.Context) (string, error) {
+	inspect, _, err := l.docker.ImageInspectWithRaw(ctx, l.builder.Name())
+	if err != nil {
+		return "", err
+	}
+	return inspect.Os, nil
+}
+
+// lifecycleBinDir returns the directory containing the lifecycle binaries inside the builder,
+// falling back to the standard location for the builder's OS when no override is configured.
+func (l *Lifecycle) lifecycleBinDir() string {
+	if l.binDir != "" {
+		return l.binDir
+	}
+	return l.paths.LifecycleBinDir()
+}
+
+// workspaceDir returns the container path the app sources are mounted at and
+// the lifecycle runs in, falling back to the standard workspace directory.
+func (l *Lifecycle) workspaceDir() string {
+	if l.workspace != "" {
+		return l.workspace
+	}
+	return l.paths.AppDir()
+}
+
+// phaseTimeout returns the time limit for the named phase. Phases without an
+// explicit limit inherit the global timeout; zero means no limit.
+func (l *Lifecycle) phaseTimeout(name string) time.Duration {
+	if timeout, ok := l.phaseTimeouts[name]; ok {
+		return timeout
+	}
+	return l.timeout
+}
+
+// populateLifecycleVolume seeds the lifecycle volume with the lifecycle image's
+// binaries. Docker copies the image content at the mount path into a named
+// volume on first mount, so a create-and-remove is all that is needed.
+func (l *Lifecycle) populateLifecycleVolume(ctx context.Context) error {
+	ctr, err := l.docker.ContainerCreate(ctx, &dcontainer.Config{
+		Image:  l.lifecycleImage,
+		Labels: map[string]string{"author": "pack"},
+	}, &dcontainer.HostConfig{
+		Binds: []string{fmt.Sprintf("%s:%s", l.lifecycleVolume, l.paths.LifecycleBinDir())},
+	}, nil, "")
+	if err != nil {
+		return err
+	}
+	return l.docker.ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true})
+}
+
+// createPhaseVolumes creates the named volumes the phases mount, including the
+// volume-backed caches, for daemons that do not create volumes referenced in
+// binds on their own.
+func (l *Lifecycle) createPhaseVolumes(ctx context.Context, buildCache, launchCache cache.Cache) error {
+	names := []string{l.LayersVolume, l.AppVolume, launchCache.Name()}
+	if buildCache.Type() == cache.Volume {
+		names = append(names, buildCache.Name())
+	}
+	if l.lifecycleVolume != "" {
+		names = append(names, l.lifecycleVolume)
+	}
+	for _, name := range names {
+		if _, err := l.docker.VolumeCreate(ctx, volume.VolumeCreateBody{
+			Name:   name,
+			Labels: map[string]string{"author": "pack"},
+		}); err != nil {
+			return errors.Wrapf(err, "creating volume %s", style.Symbol(name))
+		}
+	}
+	return nil
 }
 
 func (l *Lifecycle) Cleanup() error {
@@ -155,6 +450,11 @@ func (l *Lifecycle) Cleanup() error {
 	if err := l.docker.VolumeRemove(context.Background(), l.AppVolume, true); err != nil {
 		reterr = errors.Wrapf(err, "failed to clean up app volume %s", l.AppVolume)
 	}
+	if l.lifecycleVolume != "" {
+		if err := l.docker.VolumeRemove(context.Background(), l.lifecycleVolume, true); err != nil {
+			reterr = errors.Wrapf(err, "failed to clean up lifecycle volume %s", l.lifecycleVolume)
+		}
+	}
 	return reterr
 }
 
@@ -0,0 +1,32 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// DaemonType identifies the engine behind the Docker-compatible API pack is
+// talking to; Podman implements the same API with a few behavioral
+// differences that the phases compensate for.
+type DaemonType string
+
+const (
+	DaemonTypeDocker DaemonType = "docker"
+	DaemonTypePodman DaemonType = "podman"
+)
+
+// DetectDaemonType classifies the daemon from its version response. Podman
+// advertises itself in the version's component list (and platform name), so
+// anything not recognizably Podman is treated as Docker.
+func DetectDaemonType(version types.Version) DaemonType {
+	for _, component := range version.Components {
+		if strings.Contains(strings.ToLower(component.Name), "podman") {
+			return DaemonTypePodman
+		}
+	}
+	if strings.Contains(strings.ToLower(version.Platform.Name), "podman") {
+		return DaemonTypePodman
+	}
+	return DaemonTypeDocker
+}
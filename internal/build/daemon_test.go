@@ -0,0 +1,69 @@
+package build_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+// recorded /version responses from the daemons' Docker-compatible APIs
+const (
+	dockerVersionResponse = `{
+  "Platform": {"Name": "Docker Engine - Community"},
+  "Components": [
+    {"Name": "Engine", "Version": "19.03.8", "Details": {"ApiVersion": "1.40", "Os": "linux"}},
+    {"Name": "containerd", "Version": "v1.2.13"}
+  ],
+  "Version": "19.03.8",
+  "ApiVersion": "1.40",
+  "Os": "linux"
+}`
+
+	podmanVersionResponse = `{
+  "Platform": {"Name": "linux/amd64/fedora-32"},
+  "Components": [
+    {"Name": "Podman Engine", "Version": "2.0.2", "Details": {"APIVersion": "1.40", "Os": "linux"}}
+  ],
+  "Version": "2.0.2",
+  "ApiVersion": "1.40",
+  "Os": "linux"
+}`
+)
+
+func TestDetectDaemonType(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "detectDaemonType", testDetectDaemonType, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDetectDaemonType(t *testing.T, when spec.G, it spec.S) {
+	when("#DetectDaemonType", func() {
+		it("classifies a Docker daemon", func() {
+			var version types.Version
+			h.AssertNil(t, json.Unmarshal([]byte(dockerVersionResponse), &version))
+
+			h.AssertEq(t, build.DetectDaemonType(version), build.DaemonTypeDocker)
+		})
+
+		it("classifies a Podman daemon by its engine component", func() {
+			var version types.Version
+			h.AssertNil(t, json.Unmarshal([]byte(podmanVersionResponse), &version))
+
+			h.AssertEq(t, build.DetectDaemonType(version), build.DaemonTypePodman)
+		})
+
+		it("classifies a Podman daemon by its platform name", func() {
+			version := types.Version{}
+			version.Platform.Name = "Podman Engine"
+
+			h.AssertEq(t, build.DetectDaemonType(version), build.DaemonTypePodman)
+		})
+	})
+}
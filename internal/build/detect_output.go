@@ -0,0 +1,90 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+// DetectResult is one buildpack's outcome in a detect trial, parsed from the
+// detector's output.
+type DetectResult struct {
+	Status    string // "pass", "skip", "fail" or "err"
+	Buildpack string // "id@version"
+	Detail    string // trailing detail, such as an exit code
+}
+
+// resultLinePattern matches the detector's per-buildpack result lines, e.g.
+// 'pass: some.buildpack@1.2.3' or 'err: other.buildpack@0.0.1 (1)'.
+var resultLinePattern = regexp.MustCompile(`^(pass|skip|fail|err):\s+(\S+)\s*(.*)$`)
+
+// resultsHeader starts a results section in the detector's output; the detector
+// prints one section per buildpack group it tries.
+const resultsHeader = "======== Results ========"
+
+// ParseDetectResults extracts the per-buildpack results from the detector's
+// output, one slice per tried buildpack group. Output without results sections
+// (the detector only prints them when detection is verbose enough) yields nil.
+func ParseDetectResults(output string) [][]DetectResult {
+	var (
+		groups  [][]DetectResult
+		current []DetectResult
+		inGroup bool
+	)
+	flush := func() {
+		if inGroup {
+			groups = append(groups, current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == resultsHeader {
+			flush()
+			inGroup = true
+			continue
+		}
+		if !inGroup {
+			continue
+		}
+		match := resultLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		current = append(current, DetectResult{
+			Status:    match[1],
+			Buildpack: match[2],
+			Detail:    strings.Trim(match[3], "()"),
+		})
+	}
+	flush()
+
+	return groups
+}
+
+// SummarizeDetectFailure renders an actionable summary of a failed detect
+// phase from the detector's own output: which buildpacks passed or failed in
+// each tried group, when the detector reported them.
+func SummarizeDetectFailure(logger logging.Logger, output string) {
+	groups := ParseDetectResults(output)
+	if len(groups) == 0 {
+		logger.Info("No buildpack group passed detection. Run with --verbose for the detector's full output.")
+		return
+	}
+
+	logger.Info("No buildpack group passed detection:")
+	for i, group := range groups {
+		logger.Infof("  Group #%d:", i+1)
+		for _, result := range group {
+			line := fmt.Sprintf("    %s: %s", result.Status, result.Buildpack)
+			if result.Detail != "" {
+				line += fmt.Sprintf(" (%s)", result.Detail)
+			}
+			logger.Info(line)
+		}
+	}
+	logger.Info("Run with --verbose for the detector's full output.")
+}
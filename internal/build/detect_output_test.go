@@ -0,0 +1,80 @@
+package build_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDetectOutput(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "DetectOutput", testDetectOutput, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDetectOutput(t *testing.T, when spec.G, it spec.S) {
+	detectorOutput := `Trying group 1 out of 2 with 2 buildpacks...
+======== Results ========
+pass: node-engine@0.0.124
+fail: npm@0.0.88
+Trying group 2 out of 2 with 2 buildpacks...
+======== Results ========
+skip: go-compiler@1.1.0
+err: go-mod@0.0.5 (1)
+Error: No buildpack groups passed detection.
+`
+
+	when("#ParseDetectResults", func() {
+		it("returns the per-buildpack results of every tried group", func() {
+			groups := build.ParseDetectResults(detectorOutput)
+
+			h.AssertEq(t, len(groups), 2)
+			h.AssertEq(t, groups[0], []build.DetectResult{
+				{Status: "pass", Buildpack: "node-engine@0.0.124"},
+				{Status: "fail", Buildpack: "npm@0.0.88"},
+			})
+			h.AssertEq(t, groups[1], []build.DetectResult{
+				{Status: "skip", Buildpack: "go-compiler@1.1.0"},
+				{Status: "err", Buildpack: "go-mod@0.0.5", Detail: "1"},
+			})
+		})
+
+		it("returns no groups when the output has no results sections", func() {
+			h.AssertEq(t, len(build.ParseDetectResults("Error: No buildpack groups passed detection.\n")), 0)
+		})
+	})
+
+	when("#SummarizeDetectFailure", func() {
+		it("renders every tried group with its buildpack results", func() {
+			var out bytes.Buffer
+			logger := ilogging.NewLogWithWriters(&out, &out)
+
+			build.SummarizeDetectFailure(logger, detectorOutput)
+
+			h.AssertContains(t, out.String(), "No buildpack group passed detection:")
+			h.AssertContains(t, out.String(), "  Group #1:")
+			h.AssertContains(t, out.String(), "    pass: node-engine@0.0.124")
+			h.AssertContains(t, out.String(), "    fail: npm@0.0.88")
+			h.AssertContains(t, out.String(), "  Group #2:")
+			h.AssertContains(t, out.String(), "    err: go-mod@0.0.5 (1)")
+			h.AssertContains(t, out.String(), "Run with --verbose for the detector's full output.")
+		})
+
+		it("only suggests --verbose when the output has no results sections", func() {
+			var out bytes.Buffer
+			logger := ilogging.NewLogWithWriters(&out, &out)
+
+			build.SummarizeDetectFailure(logger, "Error: No buildpack groups passed detection.\n")
+
+			h.AssertContains(t, out.String(), "No buildpack group passed detection. Run with --verbose for the detector's full output.")
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// LogArtifact records lifecycle output as a stream of JSON lines, one entry per log line,
+// tagged with the phase and level that produced it. Phase boundaries are recorded as
+// explicit start and end entries, with the end entry carrying the phase duration.
+type LogArtifact struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	clock   func() time.Time
+	started map[string]time.Time
+}
+
+type logArtifactEntry struct {
+	Time       string `json:"time"`
+	Phase      string `json:"phase"`
+	Event      string `json:"event,omitempty"`
+	Level      string `json:"level,omitempty"`
+	Message    string `json:"message,omitempty"`
+	DurationMS int64  `json:"duration-ms,omitempty"`
+}
+
+// NewLogArtifact creates a LogArtifact that writes entries to w. Writes to w are
+// serialized, so a single artifact may record concurrent phase writers.
+func NewLogArtifact(w io.Writer) *LogArtifact {
+	return &LogArtifact{
+		enc:     json.NewEncoder(w),
+		clock:   time.Now,
+		started: map[string]time.Time{},
+	}
+}
+
+// PhaseStarted records the beginning of the named phase.
+func (a *LogArtifact) PhaseStarted(phase string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock()
+	a.started[phase] = now
+	_ = a.enc.Encode(logArtifactEntry{
+		Time:  now.Format(time.RFC3339Nano),
+		Phase: phase,
+		Event: "phase-start",
+	})
+}
+
+// PhaseEnded records the completion of the named phase along with its duration.
+func (a *LogArtifact) PhaseEnded(phase string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock()
+	entry := logArtifactEntry{
+		Time:  now.Format(time.RFC3339Nano),
+		Phase: phase,
+		Event: "phase-end",
+	}
+	if started, ok := a.started[phase]; ok {
+		entry.DurationMS = now.Sub(started).Milliseconds()
+		delete(a.started, phase)
+	}
+	_ = a.enc.Encode(entry)
+}
+
+// Writer returns a writer that records each line written to it as an entry tagged with
+// the given phase and level.
+func (a *LogArtifact) Writer(phase, level string) io.Writer {
+	return &logArtifactWriter{artifact: a, phase: phase, level: level}
+}
+
+func (a *LogArtifact) writeLine(phase, level, message string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_ = a.enc.Encode(logArtifactEntry{
+		Time:    a.clock().Format(time.RFC3339Nano),
+		Phase:   phase,
+		Level:   level,
+		Message: message,
+	})
+}
+
+type logArtifactWriter struct {
+	artifact *LogArtifact
+	phase    string
+	level    string
+}
+
+func (w *logArtifactWriter) Write(buf []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		w.artifact.writeLine(w.phase, w.level, scanner.Text())
+	}
+	return len(buf), nil
+}
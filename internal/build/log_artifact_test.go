@@ -0,0 +1,73 @@
+package build_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLogArtifact(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "logArtifact", testLogArtifact, spec.Report(report.Terminal{}), spec.Sequential())
+}
+
+func testLogArtifact(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out      bytes.Buffer
+		artifact *build.LogArtifact
+	)
+
+	it.Before(func() {
+		artifact = build.NewLogArtifact(&out)
+	})
+
+	entries := func() []map[string]interface{} {
+		t.Helper()
+		var result []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var entry map[string]interface{}
+			h.AssertNil(t, json.Unmarshal([]byte(line), &entry))
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	when("#Writer", func() {
+		it("records each line tagged with phase and level", func() {
+			_, err := artifact.Writer("detector", "info").Write([]byte("line one\nline two\n"))
+			h.AssertNil(t, err)
+
+			recorded := entries()
+			h.AssertEq(t, len(recorded), 2)
+			h.AssertEq(t, recorded[0]["phase"], "detector")
+			h.AssertEq(t, recorded[0]["level"], "info")
+			h.AssertEq(t, recorded[0]["message"], "line one")
+			h.AssertEq(t, recorded[1]["message"], "line two")
+		})
+	})
+
+	when("#PhaseStarted and #PhaseEnded", func() {
+		it("records phase boundaries with a duration", func() {
+			artifact.PhaseStarted("builder")
+			artifact.PhaseEnded("builder")
+
+			recorded := entries()
+			h.AssertEq(t, len(recorded), 2)
+			h.AssertEq(t, recorded[0]["phase"], "builder")
+			h.AssertEq(t, recorded[0]["event"], "phase-start")
+			h.AssertEq(t, recorded[1]["event"], "phase-end")
+			h.AssertNotNil(t, recorded[0]["time"])
+			h.AssertNotNil(t, recorded[1]["time"])
+		})
+	})
+}
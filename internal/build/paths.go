@@ -0,0 +1,56 @@
+package build
+
+import (
+	"path"
+	"strings"
+)
+
+// ContainerPaths produces the in-container directories the lifecycle reads and
+// writes, rooted appropriately for the builder image's OS. Linux builders use
+// the standard absolute paths ('/layers', '/workspace', ...); Windows builders
+// use the same layout under 'C:\'.
+type ContainerPaths interface {
+	LayersDir() string
+	AppDir() string
+	CacheDir() string
+	LaunchCacheDir() string
+	PlatformDir() string
+	LifecycleBinDir() string
+	// Join joins path elements with the container OS's path separator.
+	Join(elem ...string) string
+}
+
+// PathsForOS returns the container path layout for the given image OS. Any OS
+// other than "windows" gets the Linux layout.
+func PathsForOS(os string) ContainerPaths {
+	if os == "windows" {
+		return windowsPaths{}
+	}
+	return linuxPaths{}
+}
+
+type linuxPaths struct{}
+
+func (linuxPaths) LayersDir() string       { return layersDir }
+func (linuxPaths) AppDir() string          { return appDir }
+func (linuxPaths) CacheDir() string        { return cacheDir }
+func (linuxPaths) LaunchCacheDir() string  { return launchCacheDir }
+func (linuxPaths) PlatformDir() string     { return platformDir }
+func (linuxPaths) LifecycleBinDir() string { return DefaultLifecycleBinDir }
+
+func (linuxPaths) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+type windowsPaths struct{}
+
+func (windowsPaths) LayersDir() string       { return `C:\layers` }
+func (windowsPaths) AppDir() string          { return `C:\workspace` }
+func (windowsPaths) CacheDir() string        { return `C:\cache` }
+func (windowsPaths) LaunchCacheDir() string  { return `C:\launch-cache` }
+func (windowsPaths) PlatformDir() string     { return `C:\platform` }
+func (windowsPaths) LifecycleBinDir() string { return `C:\cnb\lifecycle` }
+
+func (windowsPaths) Join(elem ...string) string {
+	return strings.Join(elem, `\`)
+}
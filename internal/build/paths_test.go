@@ -0,0 +1,69 @@
+package build_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestContainerPaths(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "ContainerPaths", testContainerPaths, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testContainerPaths(t *testing.T, when spec.G, it spec.S) {
+	when("#PathsForOS", func() {
+		when("the OS is linux", func() {
+			it("returns the standard absolute paths", func() {
+				paths := build.PathsForOS("linux")
+
+				h.AssertEq(t, paths.LayersDir(), "/layers")
+				h.AssertEq(t, paths.AppDir(), "/workspace")
+				h.AssertEq(t, paths.CacheDir(), "/cache")
+				h.AssertEq(t, paths.LaunchCacheDir(), "/launch-cache")
+				h.AssertEq(t, paths.PlatformDir(), "/platform")
+				h.AssertEq(t, paths.LifecycleBinDir(), "/cnb/lifecycle")
+			})
+
+			it("joins with forward slashes", func() {
+				paths := build.PathsForOS("linux")
+
+				h.AssertEq(t, paths.Join(paths.LifecycleBinDir(), "detector"), "/cnb/lifecycle/detector")
+			})
+		})
+
+		when("the OS is windows", func() {
+			it("returns the same layout under C:", func() {
+				paths := build.PathsForOS("windows")
+
+				h.AssertEq(t, paths.LayersDir(), `C:\layers`)
+				h.AssertEq(t, paths.AppDir(), `C:\workspace`)
+				h.AssertEq(t, paths.CacheDir(), `C:\cache`)
+				h.AssertEq(t, paths.LaunchCacheDir(), `C:\launch-cache`)
+				h.AssertEq(t, paths.PlatformDir(), `C:\platform`)
+				h.AssertEq(t, paths.LifecycleBinDir(), `C:\cnb\lifecycle`)
+			})
+
+			it("joins with backslashes", func() {
+				paths := build.PathsForOS("windows")
+
+				h.AssertEq(t, paths.Join(paths.LifecycleBinDir(), "detector"), `C:\cnb\lifecycle\detector`)
+			})
+		})
+
+		when("the OS is unknown", func() {
+			it("defaults to the linux layout", func() {
+				paths := build.PathsForOS("")
+
+				h.AssertEq(t, paths.LayersDir(), "/layers")
+			})
+		})
+	})
+}
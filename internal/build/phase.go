@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/docker/docker/api/types"
@@ -17,19 +20,30 @@ import (
 
 	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/logging"
 )
 
 type Phase struct {
-	name     string
-	logger   logging.Logger
-	docker   client.CommonAPIClient
-	ctrConf  *dcontainer.Config
-	hostConf *dcontainer.HostConfig
-	ctr      dcontainer.ContainerCreateCreatedBody
-	uid, gid int
-	appPath  string
-	appOnce  *sync.Once
+	name             string
+	logger           logging.Logger
+	docker           client.CommonAPIClient
+	ctrConf          *dcontainer.Config
+	hostConf         *dcontainer.HostConfig
+	ctr              dcontainer.ContainerCreateCreatedBody
+	uid, gid         int
+	appPath          string
+	appDir           string
+	appOnce          *sync.Once
+	timeout          time.Duration
+	artifact         *LogArtifact
+	capture          io.Writer
+	normalizeModTime bool
+	appIgnore        *archive.IgnoreMatcher
+	keepOnFailure    bool
+	failed           bool
+	daemonType       DaemonType
+	keychain         authn.Keychain
 }
 
 func (l *Lifecycle) NewPhase(name string, ops ...func(*Phase) (*Phase, error)) (*Phase, error) {
@@ -39,21 +53,35 @@ func (l *Lifecycle) NewPhase(name string, ops ...func(*Phase) (*Phase, error)) (
 	}
 	hostConf := &dcontainer.HostConfig{
 		Binds: []string{
-			fmt.Sprintf("%s:%s", l.LayersVolume, layersDir),
-			fmt.Sprintf("%s:%s", l.AppVolume, appDir),
+			fmt.Sprintf("%s:%s", l.LayersVolume, l.paths.LayersDir()),
+			fmt.Sprintf("%s:%s", l.AppVolume, l.workspaceDir()),
 		},
 	}
-	ctrConf.Cmd = []string{"/cnb/lifecycle/" + name}
+	if l.lifecycleVolume != "" {
+		hostConf.Binds = append(hostConf.Binds, fmt.Sprintf("%s:%s", l.lifecycleVolume, l.lifecycleBinDir()))
+	}
+	if l.overrideUser {
+		ctrConf.User = fmt.Sprintf("%d:%d", l.uid, l.gid)
+	}
+	ctrConf.Cmd = []string{l.paths.Join(l.lifecycleBinDir(), name)}
 	phase := &Phase{
-		ctrConf:  ctrConf,
-		hostConf: hostConf,
-		name:     name,
-		docker:   l.docker,
-		logger:   l.logger,
-		uid:      l.builder.UID,
-		gid:      l.builder.GID,
-		appPath:  l.appPath,
-		appOnce:  l.appOnce,
+		ctrConf:          ctrConf,
+		hostConf:         hostConf,
+		name:             name,
+		docker:           l.docker,
+		logger:           l.logger,
+		uid:              l.uid,
+		gid:              l.gid,
+		appPath:          l.appPath,
+		appDir:           l.workspaceDir(),
+		appOnce:          l.appOnce,
+		timeout:          l.phaseTimeout(name),
+		artifact:         l.logArtifact,
+		normalizeModTime: l.normalizeModTime,
+		appIgnore:        l.appIgnore,
+		keepOnFailure:    l.interactive,
+		daemonType:       l.daemonType,
+		keychain:         l.keychain,
 	}
 
 	if l.httpProxy != "" {
@@ -86,14 +114,33 @@ func WithArgs(args ...string) func(*Phase) (*Phase, error) {
 	}
 }
 
+func WithEnv(envs ...string) func(*Phase) (*Phase, error) {
+	return func(phase *Phase) (*Phase, error) {
+		phase.ctrConf.Env = append(phase.ctrConf.Env, envs...)
+		return phase, nil
+	}
+}
+
 func WithDaemonAccess() func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
 		phase.ctrConf.User = "root"
-		phase.hostConf.Binds = append(phase.hostConf.Binds, "/var/run/docker.sock:/var/run/docker.sock")
+		phase.hostConf.Binds = append(phase.hostConf.Binds, phase.daemonSocketBind())
 		return phase, nil
 	}
 }
 
+// daemonSocketBind mounts the daemon's socket at the path the lifecycle
+// expects. Podman serves the Docker-compatible API on its own socket, which
+// is mounted over the standard Docker socket path inside the container.
+func (p *Phase) daemonSocketBind() string {
+	if p.daemonType == DaemonTypePodman {
+		if host := p.docker.DaemonHost(); strings.HasPrefix(host, "unix://") {
+			return strings.TrimPrefix(host, "unix://") + ":/var/run/docker.sock"
+		}
+	}
+	return "/var/run/docker.sock:/var/run/docker.sock"
+}
+
 func WithRoot() func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
 		phase.ctrConf.User = "root"
@@ -110,7 +157,7 @@ func WithBinds(binds ...string) func(*Phase) (*Phase, error) {
 
 func WithRegistryAccess(repos ...string) func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repos...)
+		authConfig, err := auth.BuildEnvVar(phase.keychain, repos...)
 		if err != nil {
 			return nil, err
 		}
@@ -120,6 +167,15 @@ func WithRegistryAccess(repos ...string) func(*Phase) (*Phase, error) {
 	}
 }
 
+// WithLogCapture tees the phase's raw log output, without the phase-name
+// prefix, into the given writer.
+func WithLogCapture(w io.Writer) func(*Phase) (*Phase, error) {
+	return func(phase *Phase) (*Phase, error) {
+		phase.capture = w
+		return phase, nil
+	}
+}
+
 func WithNetwork(networkMode string) func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
 		phase.hostConf.NetworkMode = dcontainer.NetworkMode(networkMode)
@@ -128,8 +184,28 @@ func WithNetwork(networkMode string) func(*Phase) (*Phase, error) {
 }
 
 func (p *Phase) Run(ctx context.Context) error {
+	err := p.run(ctx)
+	if err != nil {
+		p.failed = true
+	}
+	return err
+}
+
+func (p *Phase) run(ctx context.Context) error {
 	var err error
 
+	// a build cancelled between phases stops here instead of creating
+	// another container
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
 	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, "")
 	if err != nil {
 		return errors.Wrapf(err, "failed to create '%s' container", p.name)
@@ -168,16 +244,47 @@ func (p *Phase) Run(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to copy files to '%s' container", p.name)
 	}
 
-	return container.Run(
+	var infoWriter io.Writer = logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.InfoLevel), p.name)
+	var errorWriter io.Writer = logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.ErrorLevel), p.name)
+	if p.capture != nil {
+		infoWriter = io.MultiWriter(infoWriter, p.capture)
+		errorWriter = io.MultiWriter(errorWriter, p.capture)
+	}
+	if p.artifact != nil {
+		infoWriter = io.MultiWriter(infoWriter, p.artifact.Writer(p.name, "info"))
+		errorWriter = io.MultiWriter(errorWriter, p.artifact.Writer(p.name, "error"))
+
+		p.artifact.PhaseStarted(p.name)
+		defer p.artifact.PhaseEnded(p.name)
+	}
+
+	err = container.Run(
 		ctx,
 		p.docker,
 		p.ctr.ID,
-		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.InfoLevel), p.name),
-		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.ErrorLevel), p.name),
+		infoWriter,
+		errorWriter,
 	)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		// the deferred Cleanup force-removes the container, terminating the phase
+		return errors.Errorf("'%s' phase exceeded its timeout of %s", p.name, p.timeout)
+	}
+	return err
 }
 
 func (p *Phase) Cleanup() error {
+	if p.failed && p.keepOnFailure && p.ctr.ID != "" {
+		// the container's volumes survive with it, so /layers and /workspace
+		// stay inspectable until the user removes the container
+		shortID := p.ctr.ID
+		if len(shortID) > 12 {
+			shortID = shortID[:12]
+		}
+		p.logger.Warnf("Keeping failed %s container %s for inspection", style.Symbol(p.name), style.Symbol(shortID))
+		p.logger.Infof("Open a shell over its /layers and /workspace volumes with:\n\tdocker run --rm -it --volumes-from=%s %s /bin/sh", shortID, p.ctrConf.Image)
+		p.logger.Infof("Remove it with:\n\tdocker rm %s", shortID)
+		return nil
+	}
 	return p.docker.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true})
 }
 
@@ -193,8 +300,13 @@ func (p *Phase) createAppReader() (io.ReadCloser, error) {
 			mode = 0777
 		}
 
-		return archive.ReadDirAsTar(p.appPath, appDir, p.uid, p.gid, mode, false), nil
+		ignores, err := archive.ReadIgnoreFile(filepath.Join(p.appPath, packIgnoreFile))
+		if err != nil {
+			return nil, err
+		}
+
+		return archive.ReadDirAsTarIgnoring(p.appPath, p.appDir, p.uid, p.gid, mode, p.normalizeModTime, ignores, p.appIgnore), nil
 	}
 
-	return archive.ReadZipAsTar(p.appPath, appDir, p.uid, p.gid, -1, false), nil
+	return archive.ReadZipAsTar(p.appPath, p.appDir, p.uid, p.gid, -1, p.normalizeModTime), nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/buildpacks/pack/internal/archive"
 
 	"github.com/buildpacks/imgutil/local"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/heroku/color"
@@ -317,6 +318,38 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 	})
 
 	when("#Cleanup", func() {
+		when("interactive is set and the phase failed", func() {
+			it("keeps the container for inspection", func() {
+				interactiveSubject := build.NewLifecycle(docker, logger)
+				builderImage, err := local.NewImage(repoName, docker, local.FromBaseImage(repoName))
+				h.AssertNil(t, err)
+				bldr, err := builder.FromImage(builderImage)
+				h.AssertNil(t, err)
+				interactiveSubject.Setup(build.LifecycleOptions{
+					AppPath:     filepath.Join("testdata", "fake-app"),
+					Builder:     bldr,
+					Interactive: true,
+				})
+				defer interactiveSubject.Cleanup()
+
+				phase, err := interactiveSubject.NewPhase("phase", build.WithArgs("read", "/workspace/not-a-file"))
+				h.AssertNil(t, err)
+				h.AssertNotNil(t, phase.Run(context.TODO()))
+				h.AssertNil(t, phase.Cleanup())
+
+				h.AssertContains(t, outBuf.String(), "for inspection")
+				h.AssertContains(t, outBuf.String(), "docker rm")
+
+				containers, err := docker.ContainerList(context.TODO(), types.ContainerListOptions{
+					All:     true,
+					Filters: filters.NewArgs(filters.KeyValuePair{Key: "ancestor", Value: repoName}),
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(containers), 1)
+				h.AssertNil(t, docker.ContainerRemove(context.TODO(), containers[0].ID, types.ContainerRemoveOptions{Force: true}))
+			})
+		})
+
 		it.Before(func() {
 			phase, err := subject.NewPhase("phase")
 			h.AssertNil(t, err)
@@ -1,10 +1,21 @@
 package build
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/style"
 )
 
 const (
@@ -13,43 +24,158 @@ const (
 	cacheDir       = "/cache"
 	launchCacheDir = "/launch-cache"
 	platformDir    = "/platform"
+
+	// DefaultLifecycleBinDir is where lifecycles following the standard layout install their binaries.
+	DefaultLifecycleBinDir = "/cnb/lifecycle"
+
+	// packIgnoreFile at the app root excludes matching paths from the app upload.
+	packIgnoreFile = ".packignore"
+
+	// reportFile is written by the exporter under the layers dir and records the
+	// exported image's reference, digest and buildpack metadata.
+	reportFile = "report.toml"
+
+	// groupFile is written by the detector under the layers dir and records the
+	// buildpack group that passed detection.
+	groupFile = "group.toml"
 )
 
+// reservedVolumePaths are directories the lifecycle itself mounts or writes to;
+// user volumes must not shadow them.
+var reservedVolumePaths = []string{layersDir, appDir, cacheDir, launchCacheDir, platformDir, "/cnb"}
+
+// ParseVolumes validates '<host path>:<target path>[:<mode>]' volume values and
+// normalizes them into Docker bind strings. Host paths must exist, target paths
+// must be absolute and outside the lifecycle-reserved directories, and the mode
+// defaults to read-only. The mounts only exist during the build; they are not
+// part of the final image.
+func ParseVolumes(volumes []string) ([]string, error) {
+	var binds []string
+	for _, volume := range volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, errors.Errorf("invalid volume %s: must be in the form '<host path>:<target path>[:<mode>]'", style.Symbol(volume))
+		}
+
+		hostPath, target, mode := parts[0], path.Clean(parts[1]), "ro"
+		if len(parts) == 3 {
+			mode = parts[2]
+		}
+		if mode != "ro" && mode != "rw" {
+			return nil, errors.Errorf("invalid volume %s: mode must be 'ro' or 'rw'", style.Symbol(volume))
+		}
+
+		if _, err := os.Stat(hostPath); err != nil {
+			return nil, errors.Wrapf(err, "invalid volume %s", style.Symbol(volume))
+		}
+
+		if !path.IsAbs(target) {
+			return nil, errors.Errorf("invalid volume %s: target path must be absolute", style.Symbol(volume))
+		}
+		for _, reserved := range reservedVolumePaths {
+			if target == reserved || strings.HasPrefix(target, reserved+"/") {
+				return nil, errors.Errorf("invalid volume %s: target path %s is reserved for the lifecycle", style.Symbol(volume), style.Symbol(reserved))
+			}
+		}
+
+		binds = append(binds, fmt.Sprintf("%s:%s:%s", hostPath, target, mode))
+	}
+	return binds, nil
+}
+
+// ValidateWorkspace checks a custom workspace path: it must be absolute and
+// must not shadow the directories the lifecycle itself mounts or writes to.
+// The empty string keeps the default workspace.
+func ValidateWorkspace(workspace string) error {
+	if workspace == "" {
+		return nil
+	}
+	if !path.IsAbs(workspace) {
+		return errors.Errorf("invalid workspace %s: must be an absolute path", style.Symbol(workspace))
+	}
+	cleaned := path.Clean(workspace)
+	for _, reserved := range reservedVolumePaths {
+		if reserved == appDir {
+			continue
+		}
+		if cleaned == reserved || strings.HasPrefix(cleaned, reserved+"/") {
+			return errors.Errorf("invalid workspace %s: path %s is reserved for the lifecycle", style.Symbol(workspace), style.Symbol(reserved))
+		}
+	}
+	return nil
+}
+
 func (l *Lifecycle) Detect(ctx context.Context, networkMode string) error {
+	var output bytes.Buffer
 	detect, err := l.NewPhase(
 		"detector",
 		WithArgs(
 			l.withLogLevel(
-				"-app", appDir,
-				"-platform", platformDir,
+				"-app", l.workspaceDir(),
+				"-platform", l.paths.PlatformDir(),
 			)...,
 		),
 		WithNetwork(networkMode),
+		WithBinds(l.volumes...),
+		WithLogCapture(&output),
 	)
 	if err != nil {
 		return err
 	}
 	defer detect.Cleanup()
-	return detect.Run(ctx)
+	if err := detect.Run(ctx); err != nil {
+		SummarizeDetectFailure(l.logger, output.String())
+		return err
+	}
+	return l.writeGroup(ctx, detect)
 }
 
-func (l *Lifecycle) Restore(ctx context.Context, cacheName string) error {
+// writeGroup copies the group the detector wrote under the layers dir out of
+// the detect container to the configured host path, if one was requested.
+func (l *Lifecycle) writeGroup(ctx context.Context, detect *Phase) error {
+	if l.groupPath == "" {
+		return nil
+	}
+	reader, _, err := l.docker.CopyFromContainer(ctx, detect.ctr.ID, l.paths.Join(l.paths.LayersDir(), groupFile))
+	if err != nil {
+		return errors.Wrap(err, "copying group out of the detect container")
+	}
+	defer reader.Close()
+	_, contents, err := archive.ReadTarEntry(reader, groupFile)
+	if err != nil {
+		return errors.Wrap(err, "reading group from detect container")
+	}
+	if err := ioutil.WriteFile(l.groupPath, contents, 0644); err != nil {
+		return err
+	}
+	l.logger.Debugf("Detected group written to %s", style.Symbol(l.groupPath))
+	return nil
+}
+
+func (l *Lifecycle) Restore(ctx context.Context, buildCache cache.Cache) error {
 	cacheFlag := "-path"
+	cacheImageFlag := "-image"
 	if l.CombinedExporterCacher() {
 		cacheFlag = "-cache-dir"
+		cacheImageFlag = "-cache-image"
 	}
 
-	restore, err := l.NewPhase(
-		"restorer",
-		WithDaemonAccess(),
-		WithArgs(
-			l.withLogLevel(
-				cacheFlag, cacheDir,
-				"-layers", layersDir,
-			)...,
-		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
+	ops := []func(*Phase) (*Phase, error){WithDaemonAccess()}
+	var cacheArgs []string
+	if buildCache.Type() == cache.Image {
+		cacheArgs = []string{cacheImageFlag, buildCache.Name()}
+		ops = append(ops, WithRegistryAccess(buildCache.Name()))
+	} else {
+		cacheArgs = []string{cacheFlag, l.paths.CacheDir()}
+		ops = append(ops, WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir())))
+	}
+	ops = append(ops, WithArgs(
+		l.withLogLevel(
+			append(cacheArgs, "-layers", l.paths.LayersDir())...,
+		)...,
+	))
+
+	restore, err := l.NewPhase("restorer", ops...)
 	if err != nil {
 		return err
 	}
@@ -57,8 +183,8 @@ func (l *Lifecycle) Restore(ctx context.Context, cacheName string) error {
 	return restore.Run(ctx)
 }
 
-func (l *Lifecycle) Analyze(ctx context.Context, repoName, cacheName string, publish, clearCache bool) error {
-	analyze, err := l.newAnalyze(repoName, cacheName, publish, clearCache)
+func (l *Lifecycle) Analyze(ctx context.Context, repoName string, buildCache cache.Cache, publish, clearCache bool) error {
+	analyze, err := l.newAnalyze(repoName, buildCache, publish, clearCache)
 	if err != nil {
 		return err
 	}
@@ -66,28 +192,46 @@ func (l *Lifecycle) Analyze(ctx context.Context, repoName, cacheName string, pub
 	return analyze.Run(ctx)
 }
 
-func (l *Lifecycle) newAnalyze(repoName, cacheName string, publish, clearCache bool) (*Phase, error) {
+func (l *Lifecycle) newAnalyze(repoName string, buildCache cache.Cache, publish, clearCache bool) (*Phase, error) {
+	// The analyzer reads layer metadata from the image being built unless a
+	// previous image was named, e.g. to keep reusing layers after a rename.
+	analyzedImage := repoName
+	if l.previousImage != "" {
+		analyzedImage = l.previousImage
+	}
+
 	args := []string{
-		"-layers", layersDir,
-		repoName,
+		"-layers", l.paths.LayersDir(),
+		analyzedImage,
 	}
 	if clearCache {
 		args = prependArg("-skip-layers", args)
 	} else if l.CombinedExporterCacher() {
-		args = append([]string{"-cache-dir", cacheDir}, args...)
+		if buildCache.Type() == cache.Image {
+			args = append([]string{"-cache-image", buildCache.Name()}, args...)
+		} else {
+			args = append([]string{"-cache-dir", l.paths.CacheDir()}, args...)
+		}
 	}
 
 	if publish {
-		return l.NewPhase(
-			"analyzer",
-			WithRegistryAccess(repoName),
+		registryRepos := []string{analyzedImage}
+		if buildCache.Type() == cache.Image {
+			registryRepos = append(registryRepos, buildCache.Name())
+		}
+
+		ops := []func(*Phase) (*Phase, error){
+			WithRegistryAccess(registryRepos...),
 			WithRoot(),
 			WithArgs(args...),
-			WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-		)
+		}
+		if buildCache.Type() == cache.Volume {
+			ops = append(ops, WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir())))
+		}
+		return l.NewPhase("analyzer", ops...)
 	}
-	return l.NewPhase(
-		"analyzer",
+
+	ops := []func(*Phase) (*Phase, error){
 		WithDaemonAccess(),
 		WithArgs(
 			l.withLogLevel(
@@ -97,8 +241,13 @@ func (l *Lifecycle) newAnalyze(repoName, cacheName string, publish, clearCache b
 				)...,
 			)...,
 		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
+	}
+	if buildCache.Type() == cache.Image {
+		ops = append(ops, WithRegistryAccess(buildCache.Name()))
+	} else {
+		ops = append(ops, WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir())))
+	}
+	return l.NewPhase("analyzer", ops...)
 }
 
 func prependArg(arg string, args []string) []string {
@@ -109,11 +258,12 @@ func (l *Lifecycle) Build(ctx context.Context, networkMode string) error {
 	build, err := l.NewPhase(
 		"builder",
 		WithArgs(
-			"-layers", layersDir,
-			"-app", appDir,
-			"-platform", platformDir,
+			"-layers", l.paths.LayersDir(),
+			"-app", l.workspaceDir(),
+			"-platform", l.paths.PlatformDir(),
 		),
 		WithNetwork(networkMode),
+		WithBinds(l.volumes...),
 	)
 	if err != nil {
 		return err
@@ -122,71 +272,236 @@ func (l *Lifecycle) Build(ctx context.Context, networkMode string) error {
 	return build.Run(ctx)
 }
 
-func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string, publish bool, launchCacheName, cacheName string) error {
-	export, err := l.newExport(repoName, runImage, publish, launchCacheName, cacheName)
+func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string, publish bool, launchCacheName string, buildCache cache.Cache, additionalTags []string) error {
+	export, err := l.newExport(repoName, runImage, publish, launchCacheName, buildCache, additionalTags)
 	if err != nil {
 		return err
 	}
 	defer export.Cleanup()
-	return export.Run(ctx)
+	if err := export.Run(ctx); err != nil {
+		return err
+	}
+	return l.writeReport(ctx, export)
 }
 
-func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCacheName, cacheName string) (*Phase, error) {
+// writeReport copies the report the exporter wrote under the layers dir out of
+// the export container to the configured host path, if one was requested.
+func (l *Lifecycle) writeReport(ctx context.Context, export *Phase) error {
+	if l.reportPath == "" {
+		return nil
+	}
+	reader, _, err := l.docker.CopyFromContainer(ctx, export.ctr.ID, l.paths.Join(l.paths.LayersDir(), reportFile))
+	if err != nil {
+		return errors.Wrap(err, "copying report out of the export container")
+	}
+	defer reader.Close()
+	if err := WriteReportFile(reader, l.reportPath); err != nil {
+		return err
+	}
+	l.logger.Debugf("Build report written to %s", style.Symbol(l.reportPath))
+	return nil
+}
+
+// WriteReportFile extracts the lifecycle's report from the tar stream the daemon
+// returns for a container copy and writes it to dest.
+func WriteReportFile(r io.Reader, dest string) error {
+	_, contents, err := archive.ReadTarEntry(r, reportFile)
+	if err != nil {
+		return errors.Wrap(err, "reading report from export container")
+	}
+	return ioutil.WriteFile(dest, contents, 0644)
+}
+
+func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCacheName string, buildCache cache.Cache, additionalTags []string) (*Phase, error) {
 	var binds []string
 	args := []string{
 		"-image", runImage,
-		"-layers", layersDir,
-		"-app", appDir,
+		"-layers", l.paths.LayersDir(),
+		"-app", l.workspaceDir(),
 		repoName,
 	}
+	// The exporter writes every given name from the same manifest, so additional
+	// tags are extra image arguments rather than separate export runs.
+	args = append(args, additionalTags...)
 
+	if l.defaultProcessType != "" {
+		if l.supportsDefaultProcess() {
+			args = append([]string{"-process-type", l.defaultProcessType}, args...)
+		} else {
+			l.logger.Warn("You specified a default process type but that is not supported by this version of the lifecycle")
+		}
+	}
+
+	cacheIsImage := buildCache.Type() == cache.Image
 	if l.CombinedExporterCacher() {
-		args = append([]string{"-cache-dir", cacheDir}, args...)
-		binds = []string{fmt.Sprintf("%s:%s", cacheName, cacheDir)}
+		if cacheIsImage {
+			args = append([]string{"-cache-image", buildCache.Name()}, args...)
+		} else {
+			args = append([]string{"-cache-dir", l.paths.CacheDir()}, args...)
+			binds = []string{fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir())}
+		}
+	}
+
+	// The exporter reads SOURCE_DATE_EPOCH to stamp created layers with a fixed
+	// time, making unchanged layers reproduce identical digests across builds.
+	var envs []string
+	if l.creationTime != nil {
+		envs = append(envs, fmt.Sprintf("SOURCE_DATE_EPOCH=%d", l.creationTime.Unix()))
 	}
 
 	if publish {
+		registryRepos := append([]string{repoName, runImage}, additionalTags...)
+		if l.CombinedExporterCacher() && cacheIsImage {
+			registryRepos = append(registryRepos, buildCache.Name())
+		}
+
 		return l.NewPhase(
 			"exporter",
-			WithRegistryAccess(repoName, runImage),
+			WithRegistryAccess(registryRepos...),
 			WithArgs(
 				l.withLogLevel(args...)...,
 			),
 			WithRoot(),
 			WithBinds(binds...),
+			WithEnv(envs...),
 		)
 	}
 
-	args = append([]string{"-daemon", "-launch-cache", launchCacheDir}, args...)
-	binds = append(binds, fmt.Sprintf("%s:%s", launchCacheName, launchCacheDir))
-	return l.NewPhase(
-		"exporter",
+	args = append([]string{"-daemon", "-launch-cache", l.paths.LaunchCacheDir()}, args...)
+	binds = append(binds, fmt.Sprintf("%s:%s", launchCacheName, l.paths.LaunchCacheDir()))
+	ops := []func(*Phase) (*Phase, error){
 		WithDaemonAccess(),
 		WithArgs(
 			l.withLogLevel(args...)...,
 		),
 		WithBinds(binds...),
-	)
+		WithEnv(envs...),
+	}
+	if l.CombinedExporterCacher() && cacheIsImage {
+		ops = append(ops, WithRegistryAccess(buildCache.Name()))
+	}
+	return l.NewPhase("exporter", ops...)
 }
 
 // The cache phase is obsolete with Platform API 0.2 and will be removed in the future.
-func (l *Lifecycle) Cache(ctx context.Context, cacheName string) error {
-	cache, err := l.NewPhase(
-		"cacher",
+func (l *Lifecycle) Cache(ctx context.Context, buildCache cache.Cache) error {
+	ops := []func(*Phase) (*Phase, error){WithDaemonAccess()}
+	var cacheArgs []string
+	if buildCache.Type() == cache.Image {
+		cacheArgs = []string{"-image", buildCache.Name()}
+		ops = append(ops, WithRegistryAccess(buildCache.Name()))
+	} else {
+		cacheArgs = []string{"-path", l.paths.CacheDir()}
+		ops = append(ops, WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir())))
+	}
+	ops = append(ops, WithArgs(
+		l.withLogLevel(
+			append(cacheArgs, "-layers", l.paths.LayersDir())...,
+		)...,
+	))
+
+	cachePhase, err := l.NewPhase("cacher", ops...)
+	if err != nil {
+		return err
+	}
+	defer cachePhase.Cleanup()
+	return cachePhase.Run(ctx)
+}
+
+// Create runs the lifecycle's creator, which combines the detect, analyze,
+// restore, build and export phases in a single container. It is reserved for
+// trusted builders because the one container holds daemon and registry access
+// for the whole build.
+func (l *Lifecycle) Create(ctx context.Context, opts LifecycleOptions, buildCache, launchCache cache.Cache) error {
+	create, err := l.newCreate(opts, buildCache, launchCache)
+	if err != nil {
+		return err
+	}
+	defer create.Cleanup()
+	if err := create.Run(ctx); err != nil {
+		return err
+	}
+	return l.writeReport(ctx, create)
+}
+
+func (l *Lifecycle) newCreate(opts LifecycleOptions, buildCache, launchCache cache.Cache) (*Phase, error) {
+	args := []string{
+		"-app", l.workspaceDir(),
+		"-platform", l.paths.PlatformDir(),
+		"-run-image", opts.RunImage,
+	}
+	for _, tag := range opts.AdditionalTags {
+		args = append(args, "-tag", tag)
+	}
+	// The creator analyzes layer metadata from the image being built unless a
+	// previous image was named, matching the standalone analyzer's behavior.
+	if l.previousImage != "" {
+		args = append(args, "-previous-image", l.previousImage)
+	}
+	if opts.ClearCache.Build() {
+		args = append(args, "-skip-restore")
+	}
+	if l.defaultProcessType != "" {
+		if l.supportsDefaultProcess() {
+			args = append(args, "-process-type", l.defaultProcessType)
+		} else {
+			l.logger.Warn("You specified a default process type but that is not supported by this version of the lifecycle")
+		}
+	}
+
+	var binds []string
+	cacheIsImage := buildCache.Type() == cache.Image
+	if cacheIsImage {
+		args = append(args, "-cache-image", buildCache.Name())
+	} else {
+		args = append(args, "-cache-dir", l.paths.CacheDir())
+		binds = append(binds, fmt.Sprintf("%s:%s", buildCache.Name(), l.paths.CacheDir()))
+	}
+	binds = append(binds, l.volumes...)
+
+	var envs []string
+	if l.creationTime != nil {
+		envs = append(envs, fmt.Sprintf("SOURCE_DATE_EPOCH=%d", l.creationTime.Unix()))
+	}
+
+	repoName := opts.Image.Name()
+	if opts.Publish {
+		registryRepos := append([]string{repoName, opts.RunImage}, opts.AdditionalTags...)
+		if l.previousImage != "" {
+			registryRepos = append(registryRepos, l.previousImage)
+		}
+		if cacheIsImage {
+			registryRepos = append(registryRepos, buildCache.Name())
+		}
+
+		return l.NewPhase(
+			"creator",
+			WithRegistryAccess(registryRepos...),
+			WithRoot(),
+			WithArgs(
+				l.withLogLevel(append(args, repoName)...)...,
+			),
+			WithNetwork(opts.Network),
+			WithBinds(binds...),
+			WithEnv(envs...),
+		)
+	}
+
+	args = append(args, "-daemon", "-launch-cache", l.paths.LaunchCacheDir())
+	binds = append(binds, fmt.Sprintf("%s:%s", launchCache.Name(), l.paths.LaunchCacheDir()))
+	ops := []func(*Phase) (*Phase, error){
 		WithDaemonAccess(),
 		WithArgs(
-			l.withLogLevel(
-				"-path", cacheDir,
-				"-layers", layersDir,
-			)...,
+			l.withLogLevel(append(args, repoName)...)...,
 		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
-	if err != nil {
-		return err
+		WithNetwork(opts.Network),
+		WithBinds(binds...),
+		WithEnv(envs...),
+	}
+	if cacheIsImage {
+		ops = append(ops, WithRegistryAccess(buildCache.Name()))
 	}
-	defer cache.Cleanup()
-	return cache.Run(ctx)
+	return l.NewPhase("creator", ops...)
 }
 
 func (l *Lifecycle) withLogLevel(args ...string) []string {
@@ -0,0 +1,64 @@
+package build_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestReport(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Report", testReport, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testReport(t *testing.T, when spec.G, it spec.S) {
+	var tmpDir string
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pack.build.report.test.")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#WriteReportFile", func() {
+		it("writes the report with the image reference and digest", func() {
+			contents := `[image]
+  tags = ["registry.example.com/some/app:latest"]
+  digest = "sha256:1234567890abcdef"
+`
+			tarReader, err := archive.CreateSingleFileTarReader("report.toml", contents)
+			h.AssertNil(t, err)
+
+			dest := filepath.Join(tmpDir, "report.toml")
+			h.AssertNil(t, build.WriteReportFile(tarReader, dest))
+
+			written, err := ioutil.ReadFile(dest)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(written), `tags = ["registry.example.com/some/app:latest"]`)
+			h.AssertContains(t, string(written), `digest = "sha256:1234567890abcdef"`)
+		})
+
+		it("errors when the stream contains no report", func() {
+			tarReader, err := archive.CreateSingleFileTarReader("other.toml", "some-contents")
+			h.AssertNil(t, err)
+
+			err = build.WriteReportFile(tarReader, filepath.Join(tmpDir, "report.toml"))
+			h.AssertError(t, err, "reading report from export container")
+		})
+	})
+}
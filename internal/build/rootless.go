@@ -0,0 +1,34 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// IsRootlessDaemon reports whether the daemon described by info runs
+// rootless, i.e. inside a user namespace owned by an unprivileged user.
+// Docker and Podman both advertise this as a 'name=rootless' security option.
+func IsRootlessDaemon(info types.Info) bool {
+	for _, opt := range info.SecurityOptions {
+		for _, field := range strings.Split(opt, ",") {
+			if field == "name=rootless" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RootlessUserMapping returns the user and group ID the lifecycle phases
+// should run as on the daemon described by info. A rootless daemon maps
+// container root back to the daemon's owner, so phases run as root to keep
+// the files they create owned by that user on the host; the builder's IDs
+// would instead map to subordinate IDs the user may not control. On a
+// regular daemon the provided IDs are returned unchanged.
+func RootlessUserMapping(info types.Info, uid, gid int) (int, int) {
+	if IsRootlessDaemon(info) {
+		return 0, 0
+	}
+	return uid, gid
+}
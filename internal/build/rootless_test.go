@@ -0,0 +1,53 @@
+package build_test
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRootless(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "rootless", testRootless, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRootless(t *testing.T, when spec.G, it spec.S) {
+	when("#IsRootlessDaemon", func() {
+		it("detects a rootless security option", func() {
+			info := types.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}}
+			h.AssertTrue(t, build.IsRootlessDaemon(info))
+		})
+
+		it("detects rootless within a combined security option", func() {
+			info := types.Info{SecurityOptions: []string{"name=rootless,note=experimental"}}
+			h.AssertTrue(t, build.IsRootlessDaemon(info))
+		})
+
+		it("reports false for a regular daemon", func() {
+			info := types.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=apparmor"}}
+			h.AssertFalse(t, build.IsRootlessDaemon(info))
+		})
+	})
+
+	when("#RootlessUserMapping", func() {
+		it("maps phases to root on a rootless daemon", func() {
+			info := types.Info{SecurityOptions: []string{"name=rootless"}}
+			uid, gid := build.RootlessUserMapping(info, 1000, 1000)
+			h.AssertEq(t, uid, 0)
+			h.AssertEq(t, gid, 0)
+		})
+
+		it("keeps the builder's IDs on a regular daemon", func() {
+			uid, gid := build.RootlessUserMapping(types.Info{}, 1000, 1001)
+			h.AssertEq(t, uid, 1000)
+			h.AssertEq(t, gid, 1001)
+		})
+	})
+}
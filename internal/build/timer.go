@@ -0,0 +1,50 @@
+package build
+
+import (
+	"time"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+// Timer records how long each lifecycle phase takes so a timing summary can
+// be printed after a successful build.
+type Timer struct {
+	started time.Time
+	phases  []phaseTiming
+}
+
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+func NewTimer() *Timer {
+	return &Timer{started: time.Now()}
+}
+
+// Record runs the given phase and, when it succeeds, stores its duration
+// under name.
+func (t *Timer) Record(name string, phase func() error) error {
+	started := time.Now()
+	if err := phase(); err != nil {
+		return err
+	}
+	t.phases = append(t.phases, phaseTiming{name: name, duration: time.Since(started)})
+	return nil
+}
+
+// Summarize prints the total time elapsed since the timer was created; the
+// per-phase breakdown is only visible at debug level.
+func (t *Timer) Summarize(logger logging.Logger) {
+	logger.Infof("Build completed in %s", roundDuration(time.Since(t.started)))
+	for _, phase := range t.phases {
+		logger.Debugf("%s phase took %s", phase.name, roundDuration(phase.duration))
+	}
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d >= 10*time.Second {
+		return d.Round(time.Second)
+	}
+	return d.Round(100 * time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package build_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTimer(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Timer", testTimer, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testTimer(t *testing.T, when spec.G, it spec.S) {
+	var (
+		timer  *build.Timer
+		outBuf bytes.Buffer
+		logger *ilogging.LogWithWriters
+	)
+
+	it.Before(func() {
+		timer = build.NewTimer()
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+	})
+
+	when("#Record", func() {
+		it("runs the phase and returns its error", func() {
+			var ran bool
+			h.AssertNil(t, timer.Record("DETECT", func() error {
+				ran = true
+				return nil
+			}))
+			h.AssertTrue(t, ran)
+
+			err := timer.Record("BUILD", func() error { return errors.New("some error") })
+			h.AssertError(t, err, "some error")
+		})
+	})
+
+	when("#Summarize", func() {
+		it("prints the total build time", func() {
+			h.AssertNil(t, timer.Record("DETECT", func() error { return nil }))
+			timer.Summarize(logger)
+
+			h.AssertContains(t, outBuf.String(), "Build completed in ")
+		})
+
+		it("hides the per-phase breakdown unless debug logging is enabled", func() {
+			h.AssertNil(t, timer.Record("DETECT", func() error { return nil }))
+			timer.Summarize(logger)
+			h.AssertNotContains(t, outBuf.String(), "DETECT phase took ")
+
+			logger.WantVerbose(true)
+			timer.Summarize(logger)
+			h.AssertContains(t, outBuf.String(), "DETECT phase took ")
+		})
+
+		it("does not record failed phases", func() {
+			h.AssertError(t, timer.Record("EXPORT", func() error { return errors.New("some error") }), "some error")
+
+			logger.WantVerbose(true)
+			timer.Summarize(logger)
+			h.AssertNotContains(t, outBuf.String(), "EXPORT phase took ")
+		})
+	})
+}
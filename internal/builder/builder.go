@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -60,6 +61,7 @@ type Builder struct {
 	StackID              string
 	replaceOrder         bool
 	order                dist.Order
+	flattenLayers        int
 }
 
 type orderTOML struct {
@@ -207,6 +209,13 @@ func (b *Builder) SetOrder(order dist.Order) {
 	b.replaceOrder = true
 }
 
+// SetFlatten caps the number of image layers the builder's buildpacks are
+// written as. Buildpacks are combined into at most maxLayers layers; zero
+// keeps one layer per buildpack.
+func (b *Builder) SetFlatten(maxLayers int) {
+	b.flattenLayers = maxLayers
+}
+
 func (b *Builder) SetDescription(description string) {
 	b.metadata.Description = description
 }
@@ -256,41 +265,48 @@ func (b *Builder) Save(logger logging.Logger) error {
 		return errors.Wrap(err, "validating buildpacks")
 	}
 
-	bpLayers := dist.BuildpackLayers{}
-	if _, err := dist.GetLabel(b.image, dist.BuildpackLayersLabel, &bpLayers); err != nil {
+	bpLayers, err := dist.BuildpackLayersForImage(b.image)
+	if err != nil {
 		return err
 	}
 
-	for _, bp := range b.additionalBuildpacks {
-		bpLayerTar, err := dist.BuildpackToLayerTar(tmpDir, bp)
+	for gi, group := range b.buildpackLayerGroups() {
+		var bpLayerTar string
+		if len(group) == 1 {
+			bpLayerTar, err = dist.BuildpackToLayerTar(tmpDir, group[0])
+		} else {
+			bpLayerTar, err = flattenedBuildpackLayerTar(tmpDir, group, gi)
+		}
 		if err != nil {
 			return err
 		}
 
 		if err := b.image.AddLayer(bpLayerTar); err != nil {
 			return errors.Wrapf(err,
-				"adding layer tar for buildpack %s",
-				style.Symbol(bp.Descriptor().Info.FullName()),
+				"adding layer tar for buildpack(s) %s",
+				style.Symbol(buildpackNames(group)),
 			)
 		}
 
 		diffID, err := dist.LayerDiffID(bpLayerTar)
 		if err != nil {
 			return errors.Wrapf(err,
-				"getting content hashes for buildpack %s",
-				style.Symbol(bp.Descriptor().Info.FullName()),
+				"getting content hashes for buildpack(s) %s",
+				style.Symbol(buildpackNames(group)),
 			)
 		}
 
-		bpInfo := bp.Descriptor().Info
-		if _, ok := bpLayers[bpInfo.ID][bpInfo.Version]; ok {
-			logger.Warnf(
-				"buildpack %s already exists on builder and will be overwritten",
-				style.Symbol(bpInfo.FullName()),
-			)
-		}
+		for _, bp := range group {
+			bpInfo := bp.Descriptor().Info
+			if _, ok := bpLayers[bpInfo.ID][bpInfo.Version]; ok {
+				logger.Warnf(
+					"buildpack %s already exists on builder and will be overwritten",
+					style.Symbol(bpInfo.FullName()),
+				)
+			}
 
-		dist.AddBuildpackToLayersMD(bpLayers, bp.Descriptor(), diffID.String())
+			dist.AddBuildpackToLayersMD(bpLayers, bp.Descriptor(), diffID.String())
+		}
 	}
 
 	if err := dist.SetLabel(b.image, dist.BuildpackLayersLabel, bpLayers); err != nil {
@@ -347,6 +363,91 @@ func (b *Builder) Save(logger logging.Logger) error {
 	return b.image.Save()
 }
 
+// buildpackLayerGroups partitions the builder's additional buildpacks into the
+// groups that become image layers. Without flattening each buildpack gets its
+// own group; otherwise buildpacks are chunked into at most flattenLayers groups.
+func (b *Builder) buildpackLayerGroups() [][]dist.Buildpack {
+	bps := b.additionalBuildpacks
+	if b.flattenLayers <= 0 || b.flattenLayers >= len(bps) {
+		groups := make([][]dist.Buildpack, 0, len(bps))
+		for _, bp := range bps {
+			groups = append(groups, []dist.Buildpack{bp})
+		}
+		return groups
+	}
+
+	groupSize := (len(bps) + b.flattenLayers - 1) / b.flattenLayers
+	var groups [][]dist.Buildpack
+	for len(bps) > 0 {
+		if len(bps) < groupSize {
+			groupSize = len(bps)
+		}
+		groups = append(groups, bps[:groupSize])
+		bps = bps[groupSize:]
+	}
+	return groups
+}
+
+// flattenedBuildpackLayerTar writes the contents of every buildpack in the
+// group into a single layer tar.
+func flattenedBuildpackLayerTar(dest string, group []dist.Buildpack, idx int) (string, error) {
+	layerTar := filepath.Join(dest, fmt.Sprintf("flattened-buildpacks-%d.tar", idx))
+	fh, err := os.Create(layerTar)
+	if err != nil {
+		return "", errors.Wrap(err, "create file for tar")
+	}
+	defer fh.Close()
+
+	tw := tar.NewWriter(fh)
+	defer tw.Close()
+
+	for _, bp := range group {
+		if err := embedBuildpackTar(tw, bp); err != nil {
+			return "", errors.Wrapf(err,
+				"flattening buildpack %s",
+				style.Symbol(bp.Descriptor().Info.FullName()),
+			)
+		}
+	}
+
+	return layerTar, nil
+}
+
+func embedBuildpackTar(tw *tar.Writer, bp dist.Buildpack) error {
+	rc, err := bp.Open()
+	if err != nil {
+		return errors.Wrap(err, "opening buildpack blob")
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to get next tar entry")
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "failed to write header for '%s'", header.Name)
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return errors.Wrapf(err, "failed to write contents to '%s'", header.Name)
+		}
+	}
+	return nil
+}
+
+func buildpackNames(group []dist.Buildpack) string {
+	names := make([]string, 0, len(group))
+	for _, bp := range group {
+		names = append(names, bp.Descriptor().Info.FullName())
+	}
+	return strings.Join(names, ", ")
+}
+
 func processOrder(buildpacks []dist.BuildpackInfo, order dist.Order) (dist.Order, error) {
 	resolvedOrder := dist.Order{}
 
@@ -400,6 +501,9 @@ func validateBuildpacks(stackID string, mixins []string, lifecycleDescriptor Lif
 		bpLookup[bp.FullName()] = nil
 	}
 
+	// collect every stack mismatch instead of stopping at the first, so one
+	// create-builder run surfaces all offending buildpacks
+	var incompatible []string
 	for _, bp := range bpsToValidate {
 		bpd := bp.Descriptor()
 
@@ -414,6 +518,19 @@ func validateBuildpacks(stackID string, mixins []string, lifecycleDescriptor Lif
 		}
 
 		if len(bpd.Stacks) >= 1 { // standard buildpack
+			if !bpd.SupportsStack(stackID) {
+				supported := make([]string, 0, len(bpd.Stacks))
+				for _, s := range bpd.Stacks {
+					supported = append(supported, s.ID)
+				}
+				incompatible = append(incompatible, dist.BuildpackStackUnsupportedError{
+					Buildpack:       bpd.Info.FullName(),
+					Stack:           stackID,
+					SupportedStacks: supported,
+				}.Error())
+				continue
+			}
+
 			if err := bpd.EnsureStackSupport(stackID, mixins, false); err != nil {
 				return err
 			}
@@ -431,6 +548,10 @@ func validateBuildpacks(stackID string, mixins []string, lifecycleDescriptor Lif
 		}
 	}
 
+	if len(incompatible) > 0 {
+		return errors.New(strings.Join(incompatible, "; "))
+	}
+
 	return nil
 }
 
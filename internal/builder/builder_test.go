@@ -491,6 +491,31 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 					})
 				})
 
+				when("multiple buildpacks do not support the stack", func() {
+					it("returns an error listing each of them with their supported stacks", func() {
+						bp1, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+							API:    api.MustParse("0.2"),
+							Info:   bp1v1.Descriptor().Info,
+							Stacks: []dist.Stack{{ID: "other.stack.id"}},
+						}, 0644)
+						h.AssertNil(t, err)
+
+						bp2, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+							API:    api.MustParse("0.2"),
+							Info:   bp2v1.Descriptor().Info,
+							Stacks: []dist.Stack{{ID: "another.stack.id"}, {ID: "yet.another.stack.id"}},
+						}, 0644)
+						h.AssertNil(t, err)
+
+						subject.AddBuildpack(bp1)
+						subject.AddBuildpack(bp2)
+						err = subject.Save(logger)
+
+						h.AssertError(t, err, "buildpack 'buildpack-1-id@buildpack-1-version-1' does not support stack 'some.stack.id' (supported stack(s): other.stack.id)")
+						h.AssertError(t, err, "buildpack 'buildpack-2-id@buildpack-2-version-1' does not support stack 'some.stack.id' (supported stack(s): another.stack.id, yet.another.stack.id)")
+					})
+				})
+
 				when("buildpack is not compatible with lifecycle", func() {
 					it("returns an error", func() {
 						bp, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
@@ -677,6 +702,46 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("#SetFlatten", func() {
+			it.Before(func() {
+				subject.AddBuildpack(bp1v1)
+				subject.AddBuildpack(bp1v2)
+				subject.AddBuildpack(bp2v1)
+				subject.SetFlatten(1)
+
+				h.AssertNil(t, subject.Save(logger))
+				h.AssertEq(t, baseImage.IsSaved(), true)
+			})
+
+			it("adds the buildpacks as a single image layer", func() {
+				layerTar, err := baseImage.FindLayerWithPath("/cnb/buildpacks/buildpack-1-id/buildpack-1-version-1")
+				h.AssertNil(t, err)
+
+				h.AssertOnTarEntry(t, layerTar, "/cnb/buildpacks/buildpack-1-id/buildpack-1-version-2",
+					h.IsDirectory(),
+				)
+				h.AssertOnTarEntry(t, layerTar, "/cnb/buildpacks/buildpack-2-id/buildpack-2-version-1",
+					h.IsDirectory(),
+				)
+			})
+
+			it("resolves each buildpack's metadata to the flattened layer", func() {
+				label, err := baseImage.Label("io.buildpacks.buildpack.layers")
+				h.AssertNil(t, err)
+
+				var layers dist.BuildpackLayers
+				h.AssertNil(t, json.Unmarshal([]byte(label), &layers))
+
+				diffID := layers["buildpack-1-id"]["buildpack-1-version-1"].LayerDiffID
+				h.AssertEq(t, layers["buildpack-1-id"]["buildpack-1-version-2"].LayerDiffID, diffID)
+				h.AssertEq(t, layers["buildpack-2-id"]["buildpack-2-version-1"].LayerDiffID, diffID)
+
+				rc, err := baseImage.GetLayer(diffID)
+				h.AssertNil(t, err)
+				h.AssertNil(t, rc.Close())
+			})
+		})
+
 		when("#AddBuildpack", func() {
 			it.Before(func() {
 				subject.AddBuildpack(bp1v1)
@@ -8,15 +8,21 @@ import (
 	"regexp"
 
 	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/imgutil"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/style"
 )
 
 const (
 	DefaultLifecycleVersion    = "0.6.1"
 	DefaultBuildpackAPIVersion = "0.2"
+
+	// LifecycleMetadataLabel is the label a standalone lifecycle image describes itself with.
+	LifecycleMetadataLabel = "io.buildpacks.lifecycle.metadata"
 )
 
 type Blob interface {
@@ -83,6 +89,26 @@ func (l *lifecycle) Descriptor() LifecycleDescriptor {
 	return l.descriptor
 }
 
+// DescriptorFromImage reads a standalone lifecycle image's descriptor from its
+// LifecycleMetadataLabel.
+func DescriptorFromImage(img imgutil.Image) (LifecycleDescriptor, error) {
+	var metadata LifecycleMetadata
+	if ok, err := dist.GetLabel(img, LifecycleMetadataLabel, &metadata); err != nil {
+		return LifecycleDescriptor{}, err
+	} else if !ok {
+		return LifecycleDescriptor{}, fmt.Errorf(
+			"lifecycle image %s missing label %s",
+			style.Symbol(img.Name()),
+			style.Symbol(LifecycleMetadataLabel),
+		)
+	}
+
+	return LifecycleDescriptor{
+		Info: metadata.LifecycleInfo,
+		API:  metadata.API,
+	}, nil
+}
+
 // Binaries returns a list of all binaries contained in the lifecycle.
 func (l *lifecycle) binaries() []string {
 	binaries := []string{
@@ -21,7 +21,8 @@ type CreatorMetadata struct {
 
 type LifecycleMetadata struct {
 	LifecycleInfo
-	API LifecycleAPI `json:"api"`
+	API         LifecycleAPI `json:"api"`
+	LayerDiffID string       `json:"layerDiffID,omitempty"`
 }
 
 type StackMetadata struct {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/dist"
 	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/style"
 )
 
@@ -20,6 +21,7 @@ const (
 	URILocator
 	IDLocator
 	PackageLocator
+	RegistryLocator
 )
 
 const fromBuilderPrefix = "from=builder"
@@ -31,6 +33,7 @@ func (l LocatorType) String() string {
 		"URILocator",
 		"IDLocator",
 		"PackageLocator",
+		"RegistryLocator",
 	}[l]
 }
 
@@ -61,6 +64,10 @@ func GetLocatorType(locator string, buildpacksFromBuilder []dist.BuildpackInfo)
 		return IDLocator, nil
 	}
 
+	if registry.IsRegistryRef(locator) {
+		return RegistryLocator, nil
+	}
+
 	if _, err := name.ParseReference(locator); err == nil {
 		return PackageLocator, nil
 	}
@@ -102,6 +102,19 @@ func testGetLocatorType(t *testing.T, when spec.G, it spec.S) {
 			locator:      "registry.com/cnbs/some-bp:some-tag@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
 			expectedType: buildpack.PackageLocator,
 		},
+		{
+			locator:      "cnbs/some-bp@1.2.3",
+			expectedType: buildpack.RegistryLocator,
+		},
+		{
+			locator:      "cnbs/some-bp@latest",
+			expectedType: buildpack.RegistryLocator,
+		},
+		{
+			locator:      "cnbs/some-bp@1.2.3",
+			builderBPs:   []dist.BuildpackInfo{{ID: "cnbs/some-bp", Version: "1.2.3"}},
+			expectedType: buildpack.IDLocator,
+		},
 	} {
 		tc := tc
 
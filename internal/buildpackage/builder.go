@@ -1,6 +1,7 @@
 package buildpackage
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 
@@ -36,7 +37,9 @@ func (p *PackageBuilder) AddDependency(buildpack dist.Buildpack) {
 	p.dependencies = append(p.dependencies, buildpack)
 }
 
-func (p *PackageBuilder) Save(repoName string, publish bool) (imgutil.Image, error) {
+// validate checks the package contents and returns the merged set of stacks
+// the package supports.
+func (p *PackageBuilder) validate() ([]dist.Stack, error) {
 	if p.buildpack == nil {
 		return nil, errors.New("buildpack must be set")
 	}
@@ -60,6 +63,17 @@ func (p *PackageBuilder) Save(repoName string, publish bool) (imgutil.Image, err
 		return nil, errors.Errorf("no compatible stacks among provided buildpacks")
 	}
 
+	return stacks, nil
+}
+
+// Save writes the package image as repoName and any additionalNames, all of
+// which share a single manifest.
+func (p *PackageBuilder) Save(repoName string, publish bool, additionalNames ...string) (imgutil.Image, error) {
+	stacks, err := p.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	image, err := p.imageFactory.NewImage(repoName, !publish)
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating image")
@@ -104,13 +118,95 @@ func (p *PackageBuilder) Save(repoName string, publish bool) (imgutil.Image, err
 		return nil, err
 	}
 
-	if err := image.Save(); err != nil {
+	if err := image.Save(additionalNames...); err != nil {
 		return nil, err
 	}
 
 	return image, nil
 }
 
+// SaveAsOCILayout writes the package as an OCI image layout directory under
+// dest, for transfer to air-gapped environments. The layers match those of a
+// package image saved to a daemon or registry.
+func (p *PackageBuilder) SaveAsOCILayout(dest string) error {
+	stacks, err := p.validate()
+	if err != nil {
+		return err
+	}
+
+	layout, err := newOCILayoutWriter(dest)
+	if err != nil {
+		return errors.Wrapf(err, "initializing OCI layout at %s", style.Symbol(dest))
+	}
+
+	tmpDir, err := ioutil.TempDir("", "package-buildpack")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var layers []ociDescriptor
+	bpLayers := dist.BuildpackLayers{}
+	for _, bp := range append(p.dependencies, p.buildpack) {
+		bpLayerTar, err := dist.BuildpackToLayerTar(tmpDir, bp)
+		if err != nil {
+			return err
+		}
+
+		desc, err := layout.writeBlobFromFile(bpLayerTar, ociLayerMediaType)
+		if err != nil {
+			return errors.Wrapf(err, "adding layer for buildpack %s", style.Symbol(bp.Descriptor().Info.FullName()))
+		}
+
+		layers = append(layers, desc)
+		dist.AddBuildpackToLayersMD(bpLayers, bp.Descriptor(), desc.Digest)
+	}
+
+	metadataJSON, err := json.Marshal(Metadata{
+		BuildpackInfo: p.buildpack.Descriptor().Info,
+		Stacks:        stacks,
+	})
+	if err != nil {
+		return err
+	}
+
+	bpLayersJSON, err := json.Marshal(bpLayers)
+	if err != nil {
+		return err
+	}
+
+	config := ociImageConfig{
+		Architecture: "amd64",
+		OS:           "linux",
+		Config: ociContainerConfig{Labels: map[string]string{
+			MetadataLabel:             string(metadataJSON),
+			dist.BuildpackLayersLabel: string(bpLayersJSON),
+		}},
+		RootFS: ociRootFS{Type: "layers"},
+	}
+	for _, layer := range layers {
+		// layers are uncompressed, so their blob digests double as diff IDs
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, layer.Digest)
+	}
+
+	configDesc, err := layout.writeJSONBlob(ociConfigMediaType, config)
+	if err != nil {
+		return err
+	}
+
+	manifestDesc, err := layout.writeJSONBlob(ociManifestMediaType, ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Config:        configDesc,
+		Layers:        layers,
+	})
+	if err != nil {
+		return err
+	}
+
+	return layout.writeIndex(manifestDesc)
+}
+
 func validateBuildpacks(mainBP dist.Buildpack, depBPs []dist.Buildpack) error {
 	depsWithRefs := map[dist.BuildpackInfo][]dist.BuildpackInfo{}
 
@@ -3,6 +3,10 @@ package buildpackage_test
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/buildpacks/imgutil/fakes"
@@ -148,6 +152,52 @@ func testPackageBuilder(t *testing.T, when spec.G, it spec.S) {
 					h.AssertError(t, err, "buildpack 'bp.present.id@bp.present.version' references buildpack 'bp.missing.id@bp.missing.version' which is not present")
 				})
 			})
+
+			when("there is a referenced buildpack from a deeply nested meta-buildpack that is not present", func() {
+				it("should error", func() {
+					mainBP, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+						API: api.MustParse("0.2"),
+						Info: dist.BuildpackInfo{
+							ID:      "bp.1.id",
+							Version: "bp.1.version",
+						},
+						Order: dist.Order{{
+							Group: []dist.BuildpackRef{
+								{BuildpackInfo: dist.BuildpackInfo{ID: "bp.nested.id", Version: "bp.nested.version"}},
+							},
+						}},
+					}, 0644)
+					h.AssertNil(t, err)
+					subject.SetBuildpack(mainBP)
+
+					nestedBP, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+						API:  api.MustParse("0.2"),
+						Info: dist.BuildpackInfo{ID: "bp.nested.id", Version: "bp.nested.version"},
+						Order: dist.Order{{
+							Group: []dist.BuildpackRef{
+								{BuildpackInfo: dist.BuildpackInfo{ID: "bp.nested.nested.id", Version: "bp.nested.nested.version"}},
+							},
+						}},
+					}, 0644)
+					h.AssertNil(t, err)
+					subject.AddDependency(nestedBP)
+
+					deeplyNestedBP, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+						API:  api.MustParse("0.2"),
+						Info: dist.BuildpackInfo{ID: "bp.nested.nested.id", Version: "bp.nested.nested.version"},
+						Order: dist.Order{{
+							Group: []dist.BuildpackRef{
+								{BuildpackInfo: dist.BuildpackInfo{ID: "bp.missing.id", Version: "bp.missing.version"}},
+							},
+						}},
+					}, 0644)
+					h.AssertNil(t, err)
+					subject.AddDependency(deeplyNestedBP)
+
+					_, err = subject.Save(fakePackageImage.Name(), false)
+					h.AssertError(t, err, "buildpack 'bp.nested.nested.id@bp.nested.nested.version' references buildpack 'bp.missing.id@bp.missing.version' which is not present")
+				})
+			})
 		})
 
 		when("validate stacks", func() {
@@ -464,4 +514,84 @@ func testPackageBuilder(t *testing.T, when spec.G, it spec.S) {
 			buildpackExists("bp.1.id", "bp.1.version")
 		})
 	})
+
+	when("#SaveAsOCILayout", func() {
+		var tmpDir string
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "oci-layout-test")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("writes a layout whose config carries the buildpack layers label", func() {
+			buildpack, err := ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+				API: api.MustParse("0.2"),
+				Info: dist.BuildpackInfo{
+					ID:      "bp.1.id",
+					Version: "bp.1.version",
+				},
+				Stacks: []dist.Stack{{ID: "stack.id.1"}},
+			}, 0644)
+			h.AssertNil(t, err)
+			subject.SetBuildpack(buildpack)
+
+			h.AssertNil(t, subject.SaveAsOCILayout(tmpDir))
+
+			_, err = os.Stat(filepath.Join(tmpDir, "oci-layout"))
+			h.AssertNil(t, err)
+
+			readJSONBlob := func(digest string, v interface{}) {
+				t.Helper()
+				contents, err := ioutil.ReadFile(filepath.Join(tmpDir, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:")))
+				h.AssertNil(t, err)
+				h.AssertNil(t, json.Unmarshal(contents, v))
+			}
+
+			indexContents, err := ioutil.ReadFile(filepath.Join(tmpDir, "index.json"))
+			h.AssertNil(t, err)
+
+			var index struct {
+				Manifests []struct {
+					Digest string `json:"digest"`
+				} `json:"manifests"`
+			}
+			h.AssertNil(t, json.Unmarshal(indexContents, &index))
+			h.AssertEq(t, len(index.Manifests), 1)
+
+			var manifest struct {
+				Config struct {
+					Digest string `json:"digest"`
+				} `json:"config"`
+				Layers []struct {
+					Digest string `json:"digest"`
+				} `json:"layers"`
+			}
+			readJSONBlob(index.Manifests[0].Digest, &manifest)
+			h.AssertEq(t, len(manifest.Layers), 1)
+
+			var config struct {
+				Config struct {
+					Labels map[string]string `json:"Labels"`
+				} `json:"config"`
+			}
+			readJSONBlob(manifest.Config.Digest, &config)
+
+			bpLayersLabel, ok := config.Config.Labels["io.buildpacks.buildpack.layers"]
+			h.AssertTrue(t, ok)
+
+			bpLayers := dist.BuildpackLayers{}
+			h.AssertNil(t, json.Unmarshal([]byte(bpLayersLabel), &bpLayers))
+			h.AssertEq(t, bpLayers["bp.1.id"]["bp.1.version"].LayerDiffID, manifest.Layers[0].Digest)
+
+			metadata := buildpackage.Metadata{}
+			h.AssertNil(t, json.Unmarshal([]byte(config.Config.Labels["io.buildpacks.buildpackage.metadata"]), &metadata))
+			h.AssertEq(t, metadata.ID, "bp.1.id")
+			h.AssertEq(t, metadata.Stacks, []dist.Stack{{ID: "stack.id.1"}})
+		})
+	})
 }
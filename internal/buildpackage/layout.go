@@ -0,0 +1,211 @@
+package buildpackage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+const (
+	ociLayoutVersion     = "1.0.0"
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociConfigMediaType   = "application/vnd.oci.image.config.v1+json"
+	ociLayerMediaType    = "application/vnd.oci.image.layer.v1.tar"
+
+	// RefNameAnnotation is the standard OCI annotation recording the reference
+	// an image in a layout's index was known by when it was written.
+	RefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociImageConfig struct {
+	Architecture string             `json:"architecture"`
+	OS           string             `json:"os"`
+	Config       ociContainerConfig `json:"config"`
+	RootFS       ociRootFS          `json:"rootfs"`
+}
+
+type ociContainerConfig struct {
+	Labels map[string]string `json:"Labels,omitempty"`
+}
+
+type ociRootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// ociLayoutWriter assembles a single-image OCI image layout directory, the
+// on-disk format defined by the OCI image specification.
+type ociLayoutWriter struct {
+	dest string
+}
+
+func newOCILayoutWriter(dest string) (*ociLayoutWriter, error) {
+	if err := os.MkdirAll(filepath.Join(dest, "blobs", "sha256"), 0755); err != nil {
+		return nil, err
+	}
+
+	layoutJSON := fmt.Sprintf(`{"imageLayoutVersion": %q}`, ociLayoutVersion)
+	if err := ioutil.WriteFile(filepath.Join(dest, "oci-layout"), []byte(layoutJSON), 0644); err != nil {
+		return nil, err
+	}
+
+	return &ociLayoutWriter{dest: dest}, nil
+}
+
+func (w *ociLayoutWriter) writeBlob(mediaType string, contents io.Reader) (ociDescriptor, error) {
+	blobFile, err := ioutil.TempFile(w.dest, "blob-")
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer os.Remove(blobFile.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(blobFile, hash), contents)
+	if closeErr := blobFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	hexDigest := fmt.Sprintf("%x", hash.Sum(nil))
+	if err := os.Rename(blobFile.Name(), filepath.Join(w.dest, "blobs", "sha256", hexDigest)); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	return ociDescriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hexDigest,
+		Size:      size,
+	}, nil
+}
+
+func (w *ociLayoutWriter) writeBlobFromFile(path, mediaType string) (ociDescriptor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer file.Close()
+
+	return w.writeBlob(mediaType, file)
+}
+
+func (w *ociLayoutWriter) writeJSONBlob(mediaType string, v interface{}) (ociDescriptor, error) {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	return w.writeBlob(mediaType, bytes.NewReader(contents))
+}
+
+func (w *ociLayoutWriter) writeIndex(manifests ...ociDescriptor) error {
+	contents, err := json.Marshal(ociIndex{
+		SchemaVersion: 2,
+		Manifests:     manifests,
+	})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(w.dest, "index.json"), contents, 0644)
+}
+
+// ImageLayoutWriter assembles an OCI image layout holding any number of
+// registry images, e.g. for offline bundles. Each image's manifest is recorded
+// in the layout's index annotated with the reference it was written as.
+type ImageLayoutWriter struct {
+	layout    *ociLayoutWriter
+	manifests []ociDescriptor
+}
+
+func NewImageLayoutWriter(dest string) (*ImageLayoutWriter, error) {
+	layout, err := newOCILayoutWriter(dest)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageLayoutWriter{layout: layout}, nil
+}
+
+// AppendImage copies img's blobs into the layout verbatim, keeping the
+// original media types and digests, and records its manifest under refName.
+func (w *ImageLayoutWriter) AppendImage(img v1.Image, refName string) error {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return err
+		}
+		contents, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+		_, err = w.layout.writeBlob(string(mediaType), contents)
+		contents.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	rawConfig, err := img.RawConfigFile()
+	if err != nil {
+		return err
+	}
+	if _, err := w.layout.writeBlob(string(manifest.Config.MediaType), bytes.NewReader(rawConfig)); err != nil {
+		return err
+	}
+
+	rawManifest, err := img.RawManifest()
+	if err != nil {
+		return err
+	}
+	mediaType, err := img.MediaType()
+	if err != nil {
+		return err
+	}
+	manifestDesc, err := w.layout.writeBlob(string(mediaType), bytes.NewReader(rawManifest))
+	if err != nil {
+		return err
+	}
+	manifestDesc.Annotations = map[string]string{RefNameAnnotation: refName}
+
+	w.manifests = append(w.manifests, manifestDesc)
+	return nil
+}
+
+// Finish writes the layout's index referencing every appended image.
+func (w *ImageLayoutWriter) Finish() error {
+	return w.layout.writeIndex(w.manifests...)
+}
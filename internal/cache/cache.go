@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// Type identifies the kind of storage backing a cache.
+type Type int
+
+const (
+	Image Type = iota
+	Volume
+	Dir
+)
+
+// Cache is a build cache the lifecycle stores layers in between builds. The
+// Type tells the build how the cache is attached to the phase containers.
+type Cache interface {
+	Name() string
+	Type() Type
+	Clear(ctx context.Context) error
+	// Size returns the cache's approximate disk usage in bytes; a cache that
+	// does not exist, or whose usage cannot be determined, has size 0.
+	Size(ctx context.Context) (int64, error)
+}
+
+// FormatBytes renders an approximate byte count in a human-readable unit.
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
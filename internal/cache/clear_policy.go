@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ClearPolicy selects which of an image's caches are cleared before a build.
+type ClearPolicy int
+
+const (
+	// ClearNone keeps all caches.
+	ClearNone ClearPolicy = iota
+	// ClearBuild clears the build cache, keeping the launch cache.
+	ClearBuild
+	// ClearLaunch clears the launch cache, keeping the build cache.
+	ClearLaunch
+	// ClearAll clears both the build and launch caches.
+	ClearAll
+)
+
+// ParseClearPolicy converts a string to a ClearPolicy. An empty string defaults to ClearNone.
+func ParseClearPolicy(policy string) (ClearPolicy, error) {
+	switch policy {
+	case "":
+		return ClearNone, nil
+	case "build":
+		return ClearBuild, nil
+	case "launch":
+		return ClearLaunch, nil
+	case "all":
+		return ClearAll, nil
+	}
+	return ClearNone, errors.Errorf("invalid clear cache value %s: accepted values are 'build', 'launch' and 'all'", style.Symbol(policy))
+}
+
+// Build reports whether the policy clears the build cache.
+func (p ClearPolicy) Build() bool {
+	return p == ClearBuild || p == ClearAll
+}
+
+// Launch reports whether the policy clears the launch cache.
+func (p ClearPolicy) Launch() bool {
+	return p == ClearLaunch || p == ClearAll
+}
+
+func (p ClearPolicy) String() string {
+	switch p {
+	case ClearBuild:
+		return "build"
+	case ClearLaunch:
+		return "launch"
+	case ClearAll:
+		return "all"
+	}
+	return "none"
+}
@@ -0,0 +1,68 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestClearPolicy(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "ClearPolicy", testClearPolicy, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testClearPolicy(t *testing.T, when spec.G, it spec.S) {
+	when("#ParseClearPolicy", func() {
+		it("defaults to clearing nothing", func() {
+			policy, err := cache.ParseClearPolicy("")
+			h.AssertNil(t, err)
+			h.AssertEq(t, policy, cache.ClearNone)
+		})
+
+		it("parses the accepted values", func() {
+			for value, expected := range map[string]cache.ClearPolicy{
+				"build":  cache.ClearBuild,
+				"launch": cache.ClearLaunch,
+				"all":    cache.ClearAll,
+			} {
+				policy, err := cache.ParseClearPolicy(value)
+				h.AssertNil(t, err)
+				h.AssertEq(t, policy, expected)
+			}
+		})
+
+		it("errors on unknown values", func() {
+			_, err := cache.ParseClearPolicy("everything")
+			h.AssertError(t, err, "invalid clear cache value 'everything': accepted values are 'build', 'launch' and 'all'")
+		})
+	})
+
+	when("cache selection", func() {
+		it("clears neither cache by default", func() {
+			h.AssertEq(t, cache.ClearNone.Build(), false)
+			h.AssertEq(t, cache.ClearNone.Launch(), false)
+		})
+
+		it("clears only the build cache for 'build'", func() {
+			h.AssertEq(t, cache.ClearBuild.Build(), true)
+			h.AssertEq(t, cache.ClearBuild.Launch(), false)
+		})
+
+		it("clears only the launch cache for 'launch'", func() {
+			h.AssertEq(t, cache.ClearLaunch.Build(), false)
+			h.AssertEq(t, cache.ClearLaunch.Launch(), true)
+		})
+
+		it("clears both caches for 'all'", func() {
+			h.AssertEq(t, cache.ClearAll.Build(), true)
+			h.AssertEq(t, cache.ClearAll.Launch(), true)
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// DirCache is a build cache backed by a host directory that is bind-mounted
+// into the build containers, for CI systems that can persist a directory but
+// not a Docker named volume.
+type DirCache struct {
+	dir string
+}
+
+func NewDirCache(dir string) Cache {
+	return &DirCache{dir: dir}
+}
+
+func (c *DirCache) Name() string {
+	return c.dir
+}
+
+func (c *DirCache) Type() Type {
+	return Dir
+}
+
+func (c *DirCache) Clear(ctx context.Context) error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return err
+	}
+	return os.MkdirAll(c.dir, 0777)
+}
+
+// Size sums the regular files under the cache directory; a directory that
+// does not exist has size 0.
+func (c *DirCache) Size(ctx context.Context) (int64, error) {
+	var total int64
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return total, err
+}
@@ -0,0 +1,102 @@
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDirCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "DirCache", testDirCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDirCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir  string
+		subject cache.Cache
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pack.cache.dir.test.")
+		h.AssertNil(t, err)
+		subject = cache.NewDirCache(tmpDir)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	it("names the cache after the host directory", func() {
+		h.AssertEq(t, subject.Name(), tmpDir)
+	})
+
+	it("has the dir type", func() {
+		h.AssertEq(t, subject.Type(), cache.Dir)
+	})
+
+	when("#Clear", func() {
+		when("the directory has contents", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "committed"), 0777))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, "committed", "some-layer.tar"), []byte("some-data"), 0666))
+			})
+
+			it("removes the contents but keeps the directory", func() {
+				h.AssertNil(t, subject.Clear(context.TODO()))
+
+				fis, err := ioutil.ReadDir(tmpDir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(fis), 0)
+			})
+		})
+
+		when("the directory does not exist", func() {
+			it("creates it", func() {
+				missingDir := filepath.Join(tmpDir, "not", "yet", "created")
+				h.AssertNil(t, cache.NewDirCache(missingDir).Clear(context.TODO()))
+
+				fi, err := os.Stat(missingDir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, fi.Mode().IsDir(), true)
+			})
+		})
+	})
+
+	when("#Size", func() {
+		when("the directory has contents", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "committed"), 0777))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, "committed", "some-layer.tar"), []byte("some-data"), 0666))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, "other-layer.tar"), []byte("more"), 0666))
+			})
+
+			it("sums the regular files", func() {
+				size, err := subject.Size(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, size, int64(len("some-data")+len("more")))
+			})
+		})
+
+		when("the directory does not exist", func() {
+			it("reports zero", func() {
+				missingDir := filepath.Join(tmpDir, "not", "yet", "created")
+				size, err := cache.NewDirCache(missingDir).Size(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, size, int64(0))
+			})
+		})
+	})
+}
@@ -2,8 +2,6 @@ package cache
 
 import (
 	"context"
-	"crypto/sha256"
-	"fmt"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
@@ -15,10 +13,9 @@ type ImageCache struct {
 	image  string
 }
 
-func NewImageCache(imageRef name.Reference, dockerClient client.CommonAPIClient) *ImageCache {
-	sum := sha256.Sum256([]byte(imageRef.Name()))
+func NewImageCache(imageRef name.Reference, dockerClient client.CommonAPIClient) Cache {
 	return &ImageCache{
-		image:  fmt.Sprintf("pack-cache-%x", sum[:6]),
+		image:  imageRef.Name(),
 		docker: dockerClient,
 	}
 }
@@ -27,6 +24,10 @@ func (c *ImageCache) Name() string {
 	return c.image
 }
 
+func (c *ImageCache) Type() Type {
+	return Image
+}
+
 func (c *ImageCache) Clear(ctx context.Context) error {
 	_, err := c.docker.ImageRemove(ctx, c.Name(), types.ImageRemoveOptions{
 		Force: true,
@@ -36,3 +37,16 @@ func (c *ImageCache) Clear(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Size returns the cache image's size in the daemon; a missing image has
+// size 0.
+func (c *ImageCache) Size(ctx context.Context) (int64, error) {
+	inspect, _, err := c.docker.ImageInspectWithRaw(ctx, c.Name())
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return inspect.Size, nil
+}
@@ -101,7 +101,7 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		var (
 			imageName    string
 			dockerClient client.CommonAPIClient
-			subject      *cache.ImageCache
+			subject      cache.Cache
 			ctx          context.Context
 		)
 
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Lock guards a cache against concurrent builds on the same host. Acquire
+// fails fast when another live build holds the lock, so two builds never
+// write to the same cache at once; locks left behind by a dead process are
+// taken over.
+type Lock struct {
+	name string
+	path string
+}
+
+func NewLock(name string) *Lock {
+	sum := sha256.Sum256([]byte(name))
+	return &Lock{
+		name: name,
+		path: filepath.Join(os.TempDir(), fmt.Sprintf("pack-cache-%x.lock", sum[:6])),
+	}
+}
+
+func (l *Lock) Acquire() error {
+	for {
+		f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d", os.Getpid())
+			f.Close()
+			return err
+		}
+		if !os.IsExist(err) {
+			return errors.Wrapf(err, "locking build cache %s", style.Symbol(l.name))
+		}
+
+		if pid, err := l.holder(); err == nil && processAlive(pid) {
+			return errors.Errorf("build cache %s is in use by another build (pid %d): wait for it to finish or use a different cache", style.Symbol(l.name), pid)
+		}
+
+		// The lock belongs to a process that no longer exists; take it over.
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing stale lock for build cache %s", style.Symbol(l.name))
+		}
+	}
+}
+
+func (l *Lock) Release() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// holder returns the pid recorded in the lock file.
+func (l *Lock) holder() (int, error) {
+	contents, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(contents)))
+}
+
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLock(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Lock", testLock, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLock(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cacheName string
+		subject   *Lock
+	)
+
+	it.Before(func() {
+		cacheName = "pack-cache-" + h.RandString(10)
+		subject = NewLock(cacheName)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, subject.Release())
+	})
+
+	it("acquires and releases the lock", func() {
+		h.AssertNil(t, subject.Acquire())
+		h.AssertNil(t, subject.Release())
+		h.AssertNil(t, subject.Acquire())
+	})
+
+	it("fails fast when another live build holds the lock", func() {
+		h.AssertNil(t, subject.Acquire())
+
+		err := NewLock(cacheName).Acquire()
+		h.AssertError(t, err, "is in use by another build")
+	})
+
+	it("does not conflict with locks for other caches", func() {
+		h.AssertNil(t, subject.Acquire())
+
+		other := NewLock("pack-cache-" + h.RandString(10))
+		h.AssertNil(t, other.Acquire())
+		h.AssertNil(t, other.Release())
+	})
+
+	it("takes over a lock left by a dead process", func() {
+		cmd := exec.Command("true")
+		h.AssertNil(t, cmd.Run())
+		h.AssertNil(t, ioutil.WriteFile(subject.path, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0644))
+
+		h.AssertNil(t, subject.Acquire())
+
+		pid, err := subject.holder()
+		h.AssertNil(t, err)
+		h.AssertEq(t, pid, os.Getpid())
+	})
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const cacheVolumePrefix = "pack-cache-"
+
+// cacheVolumeNamePattern matches volume names produced by volumeName.
+var cacheVolumeNamePattern = regexp.MustCompile(`^pack-cache-[0-9a-f]{12}\.[^.]+$`)
+
+// OrphanedCacheVolumes returns the cache volumes among volumeNames that were
+// not derived from any of imageRefs, using the same naming scheme as
+// NewVolumeCache. Volumes that do not look like pack cache volumes are never
+// reported.
+func OrphanedCacheVolumes(volumeNames []string, imageRefs []name.Reference) []string {
+	inUse := map[string]struct{}{}
+	for _, ref := range imageRefs {
+		sum := sha256.Sum256([]byte(ref.Name()))
+		inUse[fmt.Sprintf("%x", sum[:6])] = struct{}{}
+	}
+
+	var orphans []string
+	for _, volume := range volumeNames {
+		if !cacheVolumeNamePattern.MatchString(volume) {
+			continue
+		}
+		hash := volume[len(cacheVolumePrefix) : len(cacheVolumePrefix)+12]
+		if _, ok := inUse[hash]; !ok {
+			orphans = append(orphans, volume)
+		}
+	}
+	return orphans
+}
@@ -0,0 +1,77 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPrune(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Prune", testPrune, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPrune(t *testing.T, when spec.G, it spec.S) {
+	when("#OrphanedCacheVolumes", func() {
+		var existingRef name.Reference
+
+		it.Before(func() {
+			var err error
+			existingRef, err = name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+		})
+
+		it("keeps cache volumes derived from existing images", func() {
+			volumes := []string{
+				cache.NewVolumeCache(existingRef, "build", nil).Name(),
+				cache.NewVolumeCache(existingRef, "launch", nil).Name(),
+			}
+
+			orphans := cache.OrphanedCacheVolumes(volumes, []name.Reference{existingRef})
+			h.AssertEq(t, len(orphans), 0)
+		})
+
+		it("reports cache volumes whose image no longer exists", func() {
+			deletedRef, err := name.ParseReference("my/deleted", name.WeakValidation)
+			h.AssertNil(t, err)
+			orphanVolume := cache.NewVolumeCache(deletedRef, "build", nil).Name()
+
+			volumes := []string{
+				cache.NewVolumeCache(existingRef, "build", nil).Name(),
+				orphanVolume,
+			}
+
+			orphans := cache.OrphanedCacheVolumes(volumes, []name.Reference{existingRef})
+			h.AssertEq(t, orphans, []string{orphanVolume})
+		})
+
+		it("ignores volumes that are not pack cache volumes", func() {
+			volumes := []string{
+				"some-user-volume",
+				"pack-layers-abcdefghij",
+				"pack-app-abcdefghij",
+				"pack-cache-not-hex.build",
+			}
+
+			orphans := cache.OrphanedCacheVolumes(volumes, nil)
+			h.AssertEq(t, len(orphans), 0)
+		})
+
+		it("resolves implied tags and registries", func() {
+			impliedRef, err := name.ParseReference("index.docker.io/my/repo:latest", name.WeakValidation)
+			h.AssertNil(t, err)
+			volumes := []string{cache.NewVolumeCache(existingRef, "build", nil).Name()}
+
+			orphans := cache.OrphanedCacheVolumes(volumes, []name.Reference{impliedRef})
+			h.AssertEq(t, len(orphans), 0)
+		})
+	})
+}
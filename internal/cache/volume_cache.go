@@ -14,10 +14,26 @@ type VolumeCache struct {
 	volume string
 }
 
-func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient) *VolumeCache {
+func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient) Cache {
+	return &VolumeCache{
+		volume: volumeName(imageRef, suffix),
+		docker: dockerClient,
+	}
+}
+
+// volumeName derives the cache volume name for an image reference; hashing the
+// normalized reference keeps distinct references from colliding.
+func volumeName(imageRef name.Reference, suffix string) string {
 	sum := sha256.Sum256([]byte(imageRef.Name()))
+	return fmt.Sprintf("pack-cache-%x.%s", sum[:6], suffix)
+}
+
+// NewNamedVolumeCache pins the cache to an explicit volume name instead of the
+// image-derived default, so that separate builds can share a pre-warmed cache.
+// Access to the volume is not synchronized; concurrent builds must not share one.
+func NewNamedVolumeCache(volume string, dockerClient client.CommonAPIClient) Cache {
 	return &VolumeCache{
-		volume: fmt.Sprintf("pack-cache-%x.%s", sum[:6], suffix),
+		volume: volume,
 		docker: dockerClient,
 	}
 }
@@ -26,6 +42,10 @@ func (c *VolumeCache) Name() string {
 	return c.volume
 }
 
+func (c *VolumeCache) Type() Type {
+	return Volume
+}
+
 func (c *VolumeCache) Clear(ctx context.Context) error {
 	err := c.docker.VolumeRemove(ctx, c.Name(), true)
 	if err != nil && !client.IsErrNotFound(err) {
@@ -33,3 +53,18 @@ func (c *VolumeCache) Clear(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Size returns the volume's disk usage as the daemon reports it; volumes the
+// daemon does not report usage for have size 0.
+func (c *VolumeCache) Size(ctx context.Context) (int64, error) {
+	du, err := c.docker.DiskUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, vol := range du.Volumes {
+		if vol != nil && vol.Name == c.volume && vol.UsageData != nil && vol.UsageData.Size > 0 {
+			return vol.UsageData.Size, nil
+		}
+	}
+	return 0, nil
+}
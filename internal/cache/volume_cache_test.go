@@ -7,11 +7,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/heroku/color"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -105,11 +107,31 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#NewNamedVolumeCache", func() {
+		var dockerClient client.CommonAPIClient
+
+		it.Before(func() {
+			var err error
+			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			h.AssertNil(t, err)
+		})
+
+		it("uses the supplied name instead of the calculated one", func() {
+			subject := cache.NewNamedVolumeCache("some-volume", dockerClient)
+			h.AssertEq(t, subject.Name(), "some-volume")
+		})
+
+		it("has the volume type", func() {
+			subject := cache.NewNamedVolumeCache("some-volume", dockerClient)
+			h.AssertEq(t, subject.Type(), cache.Volume)
+		})
+	})
+
 	when("#Clear", func() {
 		var (
 			volumeName   string
 			dockerClient client.CommonAPIClient
-			subject      *cache.VolumeCache
+			subject      cache.Cache
 			ctx          context.Context
 		)
 
@@ -153,3 +175,77 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 }
+
+// TestVolumeCacheSize doesn't need a daemon; sizes come from a faked DiskUsage.
+func TestVolumeCacheSize(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "VolumeCacheSize", testVolumeCacheSize, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+type fakeDiskUsageClient struct {
+	client.CommonAPIClient
+	du  types.DiskUsage
+	err error
+}
+
+func (f *fakeDiskUsageClient) DiskUsage(ctx context.Context) (types.DiskUsage, error) {
+	return f.du, f.err
+}
+
+func testVolumeCacheSize(t *testing.T, when spec.G, it spec.S) {
+	when("#Size", func() {
+		when("the daemon reports usage for the volume", func() {
+			it("returns the reported size", func() {
+				subject := cache.NewNamedVolumeCache("some-volume", &fakeDiskUsageClient{
+					du: types.DiskUsage{
+						Volumes: []*types.Volume{
+							{Name: "other-volume", UsageData: &types.VolumeUsageData{Size: 512}},
+							{Name: "some-volume", UsageData: &types.VolumeUsageData{Size: 1024}},
+						},
+					},
+				})
+
+				size, err := subject.Size(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, size, int64(1024))
+			})
+		})
+
+		when("the volume has no usage data", func() {
+			it("reports zero", func() {
+				subject := cache.NewNamedVolumeCache("some-volume", &fakeDiskUsageClient{
+					du: types.DiskUsage{
+						Volumes: []*types.Volume{{Name: "some-volume"}},
+					},
+				})
+
+				size, err := subject.Size(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, size, int64(0))
+			})
+		})
+
+		when("the volume does not exist", func() {
+			it("reports zero", func() {
+				subject := cache.NewNamedVolumeCache("some-volume", &fakeDiskUsageClient{})
+
+				size, err := subject.Size(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, size, int64(0))
+			})
+		})
+
+		when("the daemon errors", func() {
+			it("returns the error", func() {
+				subject := cache.NewNamedVolumeCache("some-volume", &fakeDiskUsageClient{
+					err: errors.New("some-error"),
+				})
+
+				_, err := subject.Size(context.TODO())
+				h.AssertError(t, err, "some-error")
+			})
+		})
+	})
+}
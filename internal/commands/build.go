@@ -1,17 +1,25 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/envfile"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/project"
 	"github.com/buildpacks/pack/internal/style"
@@ -19,17 +27,45 @@ import (
 )
 
 type BuildFlags struct {
-	AppPath        string
-	Builder        string
-	RunImage       string
-	Env            []string
-	EnvFiles       []string
-	Publish        bool
-	NoPull         bool
-	ClearCache     bool
-	Buildpacks     []string
-	Network        string
-	DescriptorPath string
+	AppPath         string
+	Builder         string
+	RunImage        string
+	PreviousImage   string
+	Env             []string
+	EnvFiles        []string
+	EnvPrefixes     []string
+	Publish         bool
+	NoPull          bool
+	ClearCache      string
+	Buildpacks      []string
+	Registry        string
+	Network         string
+	DescriptorPath  string
+	Timeout         time.Duration
+	PhaseTimeouts   []string
+	LifecycleBinDir string
+	Preflight       bool
+	LogArtifactPath string
+	CacheImage      string
+	CacheDir        string
+	CacheVolume     string
+	PullPolicy      string
+	AdditionalTags  []string
+	Volumes         []string
+	Timestamp       string
+	CreationTime    string
+	Workspace       string
+	Report          string
+	Labels          []string
+	DefaultProcess  string
+	LifecycleImage  string
+	UID             int
+	GID             int
+	Platforms       []string
+	DetectOnly      bool
+	TrustBuilder    bool
+	Interactive     bool
+	Yes             bool
 }
 
 func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
@@ -43,10 +79,31 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			imageName := args[0]
 			if flags.Builder == "" {
-				suggestSettingBuilder(logger, packClient)
+				suggestSettingBuilder(logger, cfg, packClient)
 				return MakeSoftError()
 			}
 
+			if cmd.Flags().Changed("pull-policy") && cmd.Flags().Changed("no-pull") {
+				return errors.New("--no-pull and --pull-policy cannot be used together; use --pull-policy")
+			}
+			if flags.CacheImage != "" && flags.CacheDir != "" {
+				return errors.New("--cache-image and --cache-dir cannot be used together")
+			}
+			if flags.CacheVolume != "" && (flags.CacheImage != "" || flags.CacheDir != "") {
+				return errors.New("--cache-volume cannot be used with --cache-image or --cache-dir")
+			}
+			if flags.NoPull {
+				logger.Warn("Flag --no-pull has been deprecated, please use `--pull-policy=never` instead")
+			}
+
+			pullPolicy, err := image.ParsePullPolicy(flags.PullPolicy)
+			if err != nil {
+				return err
+			}
+			if flags.NoPull {
+				pullPolicy = image.PullNever
+			}
+
 			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath)
 			if err != nil {
 				return err
@@ -55,11 +112,56 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				logger.Debugf("Using project descriptor located at '%s'", actualDescriptorPath)
 			}
 
-			env, err := parseEnv(descriptor, flags.EnvFiles, flags.Env)
+			env, err := parseEnv(descriptor, flags.EnvFiles, flags.EnvPrefixes, flags.Env)
+			if err != nil {
+				return err
+			}
+
+			phaseTimeouts, err := parsePhaseTimeouts(flags.PhaseTimeouts)
+			if err != nil {
+				return err
+			}
+
+			creationTime, err := parseTimestamp(flags.Timestamp)
+			if err != nil {
+				return err
+			}
+
+			createdAt, err := parseCreationTime(flags.CreationTime)
+			if err != nil {
+				return err
+			}
+
+			clearCache, err := cache.ParseClearPolicy(flags.ClearCache)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("clear-cache") && !flags.Yes {
+				if !confirmClearCache(logger, cmd.InOrStdin()) {
+					logger.Info("Build canceled; caches were not cleared")
+					return nil
+				}
+			}
+
+			labels, err := parseLabels(flags.Labels)
 			if err != nil {
 				return err
 			}
 
+			var uid, gid *int
+			if cmd.Flags().Changed("uid") {
+				if flags.UID < 0 {
+					return errors.Errorf("invalid uid %s: must be a non-negative integer", style.Symbol(strconv.Itoa(flags.UID)))
+				}
+				uid = &flags.UID
+			}
+			if cmd.Flags().Changed("gid") {
+				if flags.GID < 0 {
+					return errors.Errorf("invalid gid %s: must be a non-negative integer", style.Symbol(strconv.Itoa(flags.GID)))
+				}
+				gid = &flags.GID
+			}
+
 			buildpacks := flags.Buildpacks
 			if len(buildpacks) == 0 {
 				buildpacks = []string{}
@@ -79,23 +181,71 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				}
 			}
 
-			if err := packClient.Build(ctx, pack.BuildOptions{
+			trustBuilder := flags.TrustBuilder || IsTrustedBuilder(cfg, flags.Builder)
+			if trustBuilder {
+				logger.Debugf("Builder %s is trusted", style.Symbol(flags.Builder))
+			}
+
+			buildOpts := pack.BuildOptions{
 				AppPath:           flags.AppPath,
 				Builder:           flags.Builder,
 				AdditionalMirrors: getMirrors(cfg),
 				RunImage:          flags.RunImage,
+				PreviousImage:     flags.PreviousImage,
 				Env:               env,
+				ProjectDescriptor: descriptor,
 				Image:             imageName,
 				Publish:           flags.Publish,
 				NoPull:            flags.NoPull,
-				ClearCache:        flags.ClearCache,
+				ClearCache:        clearCache,
 				Buildpacks:        buildpacks,
+				Registry:          flags.Registry,
 				ContainerConfig: pack.ContainerConfig{
 					Network: flags.Network,
 				},
-			}); err != nil {
+				Timeout:         flags.Timeout,
+				PhaseTimeouts:   phaseTimeouts,
+				LifecycleBinDir: flags.LifecycleBinDir,
+				Preflight:       flags.Preflight,
+				LogArtifactPath: flags.LogArtifactPath,
+				CacheImage:      flags.CacheImage,
+				CacheDir:        flags.CacheDir,
+				CacheVolume:     flags.CacheVolume,
+				PullPolicy:      pullPolicy,
+				AdditionalTags:  flags.AdditionalTags,
+				Volumes:         flags.Volumes,
+				CreationTime:    creationTime,
+				CreatedAt:       createdAt,
+				Workspace:       flags.Workspace,
+				ReportPath:      flags.Report,
+				Labels:          labels,
+				DefaultProcess:  flags.DefaultProcess,
+				LifecycleImage:  flags.LifecycleImage,
+				UID:             uid,
+				GID:             gid,
+				Platforms:       flags.Platforms,
+				DetectOnly:      flags.DetectOnly,
+				TrustBuilder:    trustBuilder,
+				Interactive:     flags.Interactive,
+			}
+
+			if logging.IsQuiet(logger) && !flags.DetectOnly {
+				result, err := packClient.BuildWithResult(ctx, buildOpts)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(logger.Writer(), result.Digest)
+				return nil
+			}
+
+			if err := packClient.Build(ctx, buildOpts); err != nil {
 				return err
 			}
+
+			if flags.DetectOnly {
+				return nil
+			}
+
 			logger.Infof("Successfully built image %s", style.Symbol(imageName))
 			return nil
 		}),
@@ -107,26 +257,160 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 }
 
 func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Config) {
-	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
+	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file, a git URL with an optional '#<ref>' fragment, or '-' to read a tar stream from stdin\n  (defaults to current working directory)")
 	cmd.Flags().StringVarP(&buildFlags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
-	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
+	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image).\nA comma-separated list is tried in order, using the first image that is\n  pullable and matches the builder's stack")
+	cmd.Flags().StringVar(&buildFlags.PreviousImage, "previous-image", "", "Image to reuse unchanged layers and metadata from, for example the app's previous name after a rename\n  (defaults to the image being built)")
 	cmd.Flags().StringArrayVarP(&buildFlags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file.")
 	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
-	cmd.Flags().BoolVar(&buildFlags.NoPull, "no-pull", false, "Skip pulling builder and run images before use")
-	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
-	cmd.Flags().StringSliceVarP(&buildFlags.Buildpacks, "buildpack", "b", nil, "Buildpack reference in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  the name of a packaged buildpack image"+multiValueHelp("buildpack"))
+	cmd.Flags().StringArrayVar(&buildFlags.EnvPrefixes, "env-prefix", nil, "Forward every current environment variable whose name starts with the prefix,\n  for example 'CI_'; values set with --env take precedence"+multiValueHelp("prefix"))
+	cmd.Flags().BoolVar(&buildFlags.NoPull, "no-pull", false, "Skip pulling builder and run images before use (deprecated, use --pull-policy=never)")
+	cmd.Flags().StringVar(&buildFlags.PullPolicy, "pull-policy", "", "Pull policy for builder, run and buildpack images ('always', 'never' or 'if-not-present') (default 'always')")
+	cmd.Flags().StringVar(&buildFlags.ClearCache, "clear-cache", "", "Clear the image's associated caches before building,\n  one of 'build', 'launch' or 'all' (bare flag clears all)")
+	cmd.Flags().Lookup("clear-cache").NoOptDefVal = "all"
+	cmd.Flags().BoolVar(&buildFlags.Yes, "yes", false, "Skip the confirmation prompt that --clear-cache shows on a terminal")
+	cmd.Flags().StringVar(&buildFlags.CacheImage, "cache-image", "", "Cache build layers in a registry image instead of a local volume,\n  for CI environments without persistent volumes")
+	cmd.Flags().StringVar(&buildFlags.CacheDir, "cache-dir", "", "Cache build layers in a host directory bind mount instead of a named volume,\n  for CI environments that persist a directory between builds")
+	cmd.Flags().StringVar(&buildFlags.CacheVolume, "cache-volume", "", "Cache build layers in the named volume instead of the image-derived default,\n  to share a pre-warmed cache between builds (concurrent builds must not share a volume)")
+	cmd.Flags().StringSliceVarP(&buildFlags.Buildpacks, "buildpack", "b", nil, "Buildpack reference in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  the name of a packaged buildpack image, or\n  a '<namespace>/<name>@<version>' reference resolved via --buildpack-registry"+multiValueHelp("buildpack"))
+	cmd.Flags().StringVar(&buildFlags.Registry, "buildpack-registry", "", "Buildpack registry index used to resolve '<namespace>/<name>@<version>' buildpacks,\n  a git URL or the path to a local checkout")
+	cmd.Flags().StringSliceVarP(&buildFlags.AdditionalTags, "tag", "t", nil, "Additional tag to apply to the built image, in the form '<image-name>:<tag>',\n  pushed along with the image name when --publish is set"+multiValueHelp("tag"))
+	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount host volume into the detect and build containers, in the form '<host path>:<target path>[:ro|rw]'\n  (defaults to read-only; these volumes are not present in the final image)"+multiValueHelp("volume"))
 	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Connect detect and build containers to network")
 	cmd.Flags().StringVarP(&buildFlags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().DurationVar(&buildFlags.Timeout, "timeout", 0, "Maximum duration for each lifecycle phase, for example '10m' (defaults to unlimited)")
+	cmd.Flags().StringArrayVar(&buildFlags.PhaseTimeouts, "phase-timeout", nil, "Maximum duration for a single lifecycle phase, in the form '<phase>=<duration>',\n  overriding --timeout for that phase"+multiValueHelp("phase"))
+	cmd.Flags().StringVar(&buildFlags.LifecycleBinDir, "lifecycle-bin-dir", "", "Directory containing the lifecycle binaries inside the builder,\n  for lifecycles that don't follow the default layout (defaults to '"+build.DefaultLifecycleBinDir+"')")
+	cmd.Flags().StringVar(&buildFlags.LifecycleImage, "lifecycle-image", "", "Run the lifecycle from this image instead of the one embedded in the builder,\n  to pin the lifecycle independently of the builder")
+	var defaultPlatforms []string
+	if cfg.DefaultPlatform != "" {
+		defaultPlatforms = []string{cfg.DefaultPlatform}
+	}
+	cmd.Flags().StringSliceVar(&buildFlags.Platforms, "platform", defaultPlatforms, "Select this '<os>/<arch>[/<variant>]' manifest, for example 'linux/arm64', when the builder,\n  run or buildpack image is a multi-arch manifest list (defaults to the daemon's platform).\nBuilding for multiple platforms requires --publish and pushes a manifest list under the image name"+multiValueHelp("platform"))
+	cmd.Flags().IntVar(&buildFlags.UID, "uid", 0, "Run the lifecycle phases as this user ID instead of the builder's user,\n  for example to match mounted volume ownership; the build gains whatever access that user has")
+	cmd.Flags().IntVar(&buildFlags.GID, "gid", 0, "Run the lifecycle phases as this group ID instead of the builder's group,\n  with the same caveats as --uid")
+	cmd.Flags().BoolVar(&buildFlags.TrustBuilder, "trust-builder", false, "Trust the builder to run privileged lifecycle phases in a single container, which is faster.\nSuggested builders and builders in the config's trusted-builders list are trusted by default")
+	cmd.Flags().BoolVar(&buildFlags.DetectOnly, "detect-only", false, "Run only the lifecycle's detect phase and report the buildpack group that would be used,\n  without building an image")
+	cmd.Flags().BoolVar(&buildFlags.Interactive, "interactive", false, "Keep the container of a failed lifecycle phase, along with its /layers and /workspace volumes,\n  and print how to open a shell over them for debugging")
+	cmd.Flags().BoolVar(&buildFlags.Preflight, "preflight", false, "Verify the builder is usable before uploading the app and starting the build")
+	cmd.Flags().StringVar(&buildFlags.LogArtifactPath, "log-artifact", "", "Write a structured (JSONL) log of the build to this path,\n  with each line tagged with its lifecycle phase and level")
+	cmd.Flags().StringVar(&buildFlags.Timestamp, "timestamp", "", "Creation time for exported layers as an RFC3339 timestamp, for reproducible builds\n  (defaults to the SOURCE_DATE_EPOCH environment variable, then to the current time)")
+	cmd.Flags().StringVar(&buildFlags.CreationTime, "creation-time", "", "RFC3339 timestamp recorded as the image config's 'created' field, for reproducibility and provenance;\n  layer times are controlled separately by --timestamp (defaults to the exporter's fixed creation time)")
+	cmd.Flags().StringVar(&buildFlags.Workspace, "workspace", "", "Container path where app sources are mounted and the lifecycle runs (defaults to '/workspace')")
+	cmd.Flags().StringVar(&buildFlags.Report, "report", "", "Path to write the lifecycle's build report (report.toml),\n  containing the image reference and digest")
+	cmd.Flags().StringArrayVarP(&buildFlags.Labels, "label", "l", nil, "Set a label on the built image, in the form '<key>=<value>'"+multiValueHelp("label"))
+	cmd.Flags().StringVarP(&buildFlags.DefaultProcess, "default-process", "D", "", "Buildpack-defined process type to set as the image's default entrypoint, for example 'web'\n  (defaults to the buildpacks' choice)")
 }
 
-func parseEnv(project project.Descriptor, envFiles []string, envVars []string) (map[string]string, error) {
+// confirmClearCache prompts before the caches are deleted. Only a terminal is
+// prompted; non-interactive runs (CI, piped input) proceed as before.
+func confirmClearCache(logger logging.Logger, in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok || !terminal.IsTerminal(int(f.Fd())) {
+		return true
+	}
+	fmt.Fprint(logger.Writer(), "This will delete the image's existing caches. Continue? (y/N): ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+var lifecyclePhases = []string{"detector", "analyzer", "restorer", "builder", "exporter", "cacher", "creator"}
+
+func parsePhaseTimeouts(items []string) (map[string]time.Duration, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	timeouts := map[string]time.Duration{}
+	for _, item := range items {
+		arr := strings.SplitN(item, "=", 2)
+		if len(arr) != 2 {
+			return nil, errors.Errorf("invalid phase timeout %s: must be in the form '<phase>=<duration>'", style.Symbol(item))
+		}
+
+		phase := arr[0]
+		if !contains(lifecyclePhases, phase) {
+			return nil, errors.Errorf("invalid phase timeout %s: unknown phase %s", style.Symbol(item), style.Symbol(phase))
+		}
+
+		duration, err := time.ParseDuration(arr[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid phase timeout %s", style.Symbol(item))
+		}
+		timeouts[phase] = duration
+	}
+	return timeouts, nil
+}
+
+// parseTimestamp resolves the creation time for exported layers from the
+// --timestamp flag, falling back to the SOURCE_DATE_EPOCH environment
+// variable. Nil keeps the exporter's default behavior.
+func parseTimestamp(flagValue string) (*time.Time, error) {
+	if flagValue != "" {
+		parsed, err := time.Parse(time.RFC3339, flagValue)
+		if err != nil {
+			return nil, errors.Errorf("invalid timestamp %s: must be an RFC3339 time, for example '2006-01-02T15:04:05Z'", style.Symbol(flagValue))
+		}
+		return &parsed, nil
+	}
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid SOURCE_DATE_EPOCH value %s: must be seconds since the Unix epoch", style.Symbol(epoch))
+		}
+		parsed := time.Unix(seconds, 0).UTC()
+		return &parsed, nil
+	}
+	return nil, nil
+}
+
+// parseCreationTime resolves the image config's 'created' timestamp from the
+// --creation-time flag. Nil keeps the exporter's default behavior.
+func parseCreationTime(flagValue string) (*time.Time, error) {
+	if flagValue == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, flagValue)
+	if err != nil {
+		return nil, errors.Errorf("invalid creation time %s: must be an RFC3339 time, for example '2006-01-02T15:04:05Z'", style.Symbol(flagValue))
+	}
+	return &parsed, nil
+}
+
+// parseLabels converts repeated '<key>=<value>' label flags into a map.
+func parseLabels(labels []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, label := range labels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid label %s: must be in the form '<key>=<value>'", style.Symbol(label))
+		}
+		parsed[parts[0]] = parts[1]
+	}
+	return parsed, nil
+}
+
+func contains(arr []string, str string) bool {
+	for _, item := range arr {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
+
+func parseEnv(project project.Descriptor, envFiles []string, envPrefixes []string, envVars []string) (map[string]string, error) {
 	env := map[string]string{}
 
 	for _, envVar := range project.Build.Env {
 		env[envVar.Name] = envVar.Value
 	}
 	for _, envFile := range envFiles {
-		envFileVars, err := parseEnvFile(envFile)
+		envFileVars, err := envfile.Parse(envFile)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to parse env file '%s'", envFile)
 		}
@@ -135,26 +419,36 @@ func parseEnv(project project.Descriptor, envFiles []string, envVars []string) (
 			env[k] = v
 		}
 	}
+	prefixVars, err := collectEnvWithPrefixes(envPrefixes, os.Environ())
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range prefixVars {
+		env[k] = v
+	}
 	for _, envVar := range envVars {
 		env = addEnvVar(env, envVar)
 	}
 	return env, nil
 }
 
-func parseEnvFile(filename string) (map[string]string, error) {
-	out := make(map[string]string)
-	f, err := ioutil.ReadFile(filename)
-	if err != nil {
-		return nil, errors.Wrapf(err, "open %s", filename)
-	}
-	for _, line := range strings.Split(string(f), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+// collectEnvWithPrefixes returns every variable from environ (in 'VAR=VALUE'
+// form) whose name starts with one of the prefixes. Values set with --env
+// take precedence as they are applied after the collected variables.
+func collectEnvWithPrefixes(prefixes []string, environ []string) (map[string]string, error) {
+	env := map[string]string{}
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			return nil, errors.New("invalid env prefix: must not be empty")
+		}
+		for _, item := range environ {
+			arr := strings.SplitN(item, "=", 2)
+			if len(arr) == 2 && strings.HasPrefix(arr[0], prefix) {
+				env[arr[0]] = arr[1]
+			}
 		}
-		out = addEnvVar(out, line)
 	}
-	return out, nil
+	return env, nil
 }
 
 func addEnvVar(env map[string]string, item string) map[string]string {
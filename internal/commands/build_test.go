@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 	"github.com/spf13/cobra"
@@ -16,12 +18,16 @@ import (
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/commands/testmocks"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/image"
 	ilogging "github.com/buildpacks/pack/internal/logging"
 	"github.com/buildpacks/pack/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
 func TestBuildCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
 	spec.Run(t, "Commands", testBuildCommand, spec.Random(), spec.Report(report.Terminal{}))
 }
 
@@ -75,6 +81,308 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("a uid and gid are given", func() {
+			it("forwards the user override onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithUserIDs(1001, 2002)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--uid", "1001", "--gid", "2002"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when the uid is negative", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--uid", "-2"})
+				h.AssertError(t, command.Execute(), "invalid uid '-2': must be a non-negative integer")
+			})
+
+			it("errors when the gid is negative", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--gid", "-2"})
+				h.AssertError(t, command.Execute(), "invalid gid '-2': must be a non-negative integer")
+			})
+		})
+
+		when("a platform is given", func() {
+			it("forwards the platform onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatforms("linux/arm64")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--platform", "linux/arm64"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("forwards multiple platforms onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatforms("linux/amd64", "linux/arm64")).
+					Return(nil)
+
+				command.SetArgs([]string{
+					"image", "--builder", "my-builder", "--publish",
+					"--platform", "linux/amd64",
+					"--platform", "linux/arm64",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("defaults to the platform from the config", func() {
+				cfg.DefaultPlatform = "linux/arm/v7"
+				command = commands.Build(logger, cfg, mockClient)
+
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatforms("linux/arm/v7")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--detect-only is given", func() {
+			it("forwards the flag and does not report a built image", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDetectOnly("my-builder", "")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--detect-only"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
+			})
+		})
+
+		when("timeouts are given", func() {
+			it("forwards the global timeout onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithTimeouts(10*time.Minute, nil)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timeout", "10m"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("forwards phase timeouts onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithTimeouts(10*time.Minute, map[string]time.Duration{
+						"restorer": 30 * time.Minute,
+						"builder":  5 * time.Minute,
+					})).
+					Return(nil)
+
+				command.SetArgs([]string{
+					"image", "--builder", "my-builder", "--timeout", "10m",
+					"--phase-timeout", "restorer=30m",
+					"--phase-timeout", "builder=5m",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for an unknown phase", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "compile=5m"})
+				err := command.Execute()
+				h.AssertError(t, err, "unknown phase 'compile'")
+			})
+
+			it("errors for a malformed phase timeout", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "builder"})
+				err := command.Execute()
+				h.AssertError(t, err, "must be in the form '<phase>=<duration>'")
+			})
+		})
+
+		when("preflight is requested", func() {
+			it("forwards the preflight option onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPreflight()).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--preflight"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("a pull policy is given", func() {
+			it("forwards the policy onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPullPolicy(image.PullIfNotPresent)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--pull-policy", "if-not-present"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for an unknown policy", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--pull-policy", "sometimes"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid pull policy 'sometimes'")
+			})
+
+			it("errors when combined with --no-pull", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--pull-policy", "never", "--no-pull"})
+				err := command.Execute()
+				h.AssertError(t, err, "--no-pull and --pull-policy cannot be used together")
+			})
+		})
+
+		when("--no-pull is given", func() {
+			it("maps it to the never policy and warns it is deprecated", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPullPolicy(image.PullNever)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--no-pull"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Flag --no-pull has been deprecated")
+			})
+		})
+
+		when("a cache image is requested", func() {
+			it("forwards the cache image onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCacheImage("some/cache-image")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--cache-image", "some/cache-image"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("a log artifact is requested", func() {
+			it("forwards the log artifact path onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLogArtifactPath("build.jsonl")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--log-artifact", "build.jsonl"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("a previous image is given", func() {
+			it("forwards the previous image onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPreviousImage("some/previous-app")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--previous-image", "some/previous-app"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("additional tags are given", func() {
+			it("forwards the tags onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAdditionalTags([]string{"image:latest", "image:v1.2.3"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--tag", "image:latest", "--tag", "image:v1.2.3"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("quiet is set", func() {
+			it.Before(func() {
+				quietLogger := ilogging.NewLogWithWriters(&outBuf, &outBuf)
+				quietLogger.WantQuiet(true)
+				logger = quietLogger
+				command = commands.Build(logger, cfg, mockClient)
+			})
+
+			it("prints only the image digest on success", func() {
+				mockClient.EXPECT().
+					BuildWithResult(gomock.Any(), EqBuildOptionsWithImage("my-builder", "image")).
+					Return(&pack.BuildResult{ImageRef: "index.docker.io/library/image:latest", Digest: "sha256:1234567890abcdef"}, nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+				h.AssertEq(t, outBuf.String(), "sha256:1234567890abcdef\n")
+			})
+
+			it("resolves the digest remotely when publishing", func() {
+				mockClient.EXPECT().
+					BuildWithResult(gomock.Any(), EqBuildOptionsWithImage("my-builder", "image")).
+					Return(&pack.BuildResult{ImageRef: "index.docker.io/library/image:latest", Digest: "index.docker.io/image@sha256:1234567890abcdef"}, nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--publish"})
+				h.AssertNil(t, command.Execute())
+				h.AssertEq(t, outBuf.String(), "index.docker.io/image@sha256:1234567890abcdef\n")
+			})
+		})
+
+		when("a timestamp is given", func() {
+			it("forwards the creation time onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCreationTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timestamp", "2020-01-01T00:00:00Z"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("falls back to SOURCE_DATE_EPOCH", func() {
+				h.AssertNil(t, os.Setenv("SOURCE_DATE_EPOCH", "1577836800"))
+				defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCreationTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for a malformed timestamp", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--timestamp", "last tuesday"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid timestamp 'last tuesday': must be an RFC3339 time")
+			})
+
+			it("errors for a malformed SOURCE_DATE_EPOCH", func() {
+				h.AssertNil(t, os.Setenv("SOURCE_DATE_EPOCH", "not-a-number"))
+				defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid SOURCE_DATE_EPOCH value 'not-a-number': must be seconds since the Unix epoch")
+			})
+		})
+
+		when("a creation time is given", func() {
+			it("forwards the config's created time onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCreatedAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "2020-01-01T00:00:00Z"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for a malformed creation time", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--creation-time", "last tuesday"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid creation time 'last tuesday': must be an RFC3339 time")
+			})
+		})
+
+		when("a label is provided", func() {
+			it("forwards the labels onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithLabels(map[string]string{
+						"org.opencontainers.image.revision": "abc123",
+					})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--label", "org.opencontainers.image.revision=abc123"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors for a malformed label", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--label", "no-value"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid label 'no-value': must be in the form '<key>=<value>'")
+			})
+		})
+
 		when("an env file is provided", func() {
 			var envPath string
 
@@ -141,6 +449,50 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("env prefixes are provided", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.Setenv("PACKTESTCI_JOB", "some-job"))
+				h.AssertNil(t, os.Setenv("PACKTESTCI_JOB_ID", "42"))
+				h.AssertNil(t, os.Setenv("PACKTESTOTHER_VAR", "ignored"))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Unsetenv("PACKTESTCI_JOB"))
+				h.AssertNil(t, os.Unsetenv("PACKTESTCI_JOB_ID"))
+				h.AssertNil(t, os.Unsetenv("PACKTESTOTHER_VAR"))
+			})
+
+			it("forwards matching env variables, with overlapping prefixes collected once", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+						"PACKTESTCI_JOB":    "some-job",
+						"PACKTESTCI_JOB_ID": "42",
+					})).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--env-prefix", "PACKTESTCI_", "--env-prefix", "PACKTESTCI_JOB"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("lets explicit --env values override forwarded variables", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+						"PACKTESTCI_JOB":    "overridden",
+						"PACKTESTCI_JOB_ID": "42",
+					})).
+					Return(nil)
+
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--env-prefix", "PACKTESTCI_", "--env", "PACKTESTCI_JOB=overridden"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when a prefix is empty", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "image", "--env-prefix", ""})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid env prefix: must not be empty")
+			})
+		})
+
 		when("user specifies an invalid project descriptor file", func() {
 			it("should show an error", func() {
 				projectTomlPath := "/incorrect/path/to/project.toml"
@@ -277,6 +629,138 @@ func EqBuildOptionsWithNetwork(network string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithUserIDs(uid, gid int) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("UID=%d, GID=%d", uid, gid),
+		equals: func(o pack.BuildOptions) bool {
+			return o.UID != nil && *o.UID == uid && o.GID != nil && *o.GID == gid
+		},
+	}
+}
+
+func EqBuildOptionsWithPlatforms(platforms ...string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Platforms=%v", platforms),
+		equals: func(o pack.BuildOptions) bool {
+			if len(o.Platforms) != len(platforms) {
+				return false
+			}
+			for i, platform := range platforms {
+				if o.Platforms[i] != platform {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+func EqBuildOptionsWithDetectOnly(builder, appPath string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("DetectOnly=true, Builder=%s and AppPath=%s", builder, appPath),
+		equals: func(o pack.BuildOptions) bool {
+			return o.DetectOnly && o.Builder == builder && o.AppPath == appPath
+		},
+	}
+}
+
+func EqBuildOptionsWithTimeouts(timeout time.Duration, phaseTimeouts map[string]time.Duration) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Timeout=%s and PhaseTimeouts=%+v", timeout, phaseTimeouts),
+		equals: func(o pack.BuildOptions) bool {
+			if o.Timeout != timeout || len(o.PhaseTimeouts) != len(phaseTimeouts) {
+				return false
+			}
+			for phase, limit := range phaseTimeouts {
+				if o.PhaseTimeouts[phase] != limit {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+func EqBuildOptionsWithPreflight() gomock.Matcher {
+	return buildOptionsMatcher{
+		description: "Preflight=true",
+		equals: func(o pack.BuildOptions) bool {
+			return o.Preflight
+		},
+	}
+}
+
+func EqBuildOptionsWithPullPolicy(policy image.PullPolicy) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("PullPolicy=%s", policy),
+		equals: func(o pack.BuildOptions) bool {
+			return o.PullPolicy == policy
+		},
+	}
+}
+
+func EqBuildOptionsWithCacheImage(cacheImage string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CacheImage=%s", cacheImage),
+		equals: func(o pack.BuildOptions) bool {
+			return o.CacheImage == cacheImage
+		},
+	}
+}
+
+func EqBuildOptionsWithLogArtifactPath(path string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("LogArtifactPath=%s", path),
+		equals: func(o pack.BuildOptions) bool {
+			return o.LogArtifactPath == path
+		},
+	}
+}
+
+func EqBuildOptionsWithCreatedAt(createdAt time.Time) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CreatedAt=%s", createdAt),
+		equals: func(o pack.BuildOptions) bool {
+			return o.CreatedAt != nil && o.CreatedAt.Equal(createdAt)
+		},
+	}
+}
+
+func EqBuildOptionsWithPreviousImage(previousImage string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("PreviousImage=%s", previousImage),
+		equals: func(o pack.BuildOptions) bool {
+			return o.PreviousImage == previousImage
+		},
+	}
+}
+
+func EqBuildOptionsWithAdditionalTags(tags []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("AdditionalTags=%+v", tags),
+		equals: func(o pack.BuildOptions) bool {
+			if len(o.AdditionalTags) != len(tags) {
+				return false
+			}
+			for i, tag := range tags {
+				if o.AdditionalTags[i] != tag {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+func EqBuildOptionsWithCreationTime(creationTime time.Time) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CreationTime=%s", creationTime),
+		equals: func(o pack.BuildOptions) bool {
+			return o.CreationTime != nil && o.CreationTime.Equal(creationTime)
+		},
+	}
+}
+
 func EqBuildOptionsWithEnv(env map[string]string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Env=%+v", env),
@@ -296,6 +780,25 @@ func EqBuildOptionsWithEnv(env map[string]string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithLabels(labels map[string]string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Labels=%+v", labels),
+		equals: func(o pack.BuildOptions) bool {
+			for k, v := range o.Labels {
+				if labels[k] != v {
+					return false
+				}
+			}
+			for k, v := range labels {
+				if o.Labels[k] != v {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
 func EqBuildOptionsWithBuildpacks(buildpacks []string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Buildpacks=%+v", buildpacks),
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type BundleFlags struct {
+	Output string
+}
+
+func Bundle(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags BundleFlags
+	ctx := createCancellableContext()
+
+	cmd := &cobra.Command{
+		Use:   "bundle <builder-ref>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Package a builder and its run images into a portable archive for air-gapped installs",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			builderName := args[0]
+			if err := packClient.Bundle(ctx, pack.BundleOptions{
+				BuilderImage: builderName,
+				Output:       flags.Output,
+			}); err != nil {
+				return err
+			}
+			logger.Infof("Successfully wrote bundle %s", style.Symbol(flags.Output))
+			return nil
+		}),
+	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "bundle.tar", "Path to write the bundle archive")
+	cmd.AddCommand(bundleLoad(logger, packClient))
+	AddHelpFlag(cmd, "bundle")
+	return cmd
+}
+
+func bundleLoad(logger logging.Logger, packClient PackClient) *cobra.Command {
+	ctx := createCancellableContext()
+
+	cmd := &cobra.Command{
+		Use:   "load <path>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Load the images from a bundle archive into the daemon",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			return packClient.LoadBundle(ctx, pack.LoadBundleOptions{
+				Path: args[0],
+			})
+		}),
+	}
+	AddHelpFlag(cmd, "load")
+	return cmd
+}
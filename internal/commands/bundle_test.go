@@ -0,0 +1,97 @@
+package commands_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBundleCommand(t *testing.T) {
+	spec.Run(t, "Commands", testBundleCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBundleCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.Bundle(logger, mockClient)
+	})
+
+	when("#Bundle", func() {
+		it("bundles a builder to the default output", func() {
+			mockClient.EXPECT().
+				Bundle(gomock.Any(), EqBundleOptions("some/builder", "bundle.tar")).
+				Return(nil)
+
+			command.SetArgs([]string{"some/builder"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("bundles a builder to a custom output", func() {
+			mockClient.EXPECT().
+				Bundle(gomock.Any(), EqBundleOptions("some/builder", "custom.tar")).
+				Return(nil)
+
+			command.SetArgs([]string{"some/builder", "--output", "custom.tar"})
+			h.AssertNil(t, command.Execute())
+		})
+	})
+
+	when("#BundleLoad", func() {
+		it("loads a bundle from the given path", func() {
+			mockClient.EXPECT().
+				LoadBundle(gomock.Any(), pack.LoadBundleOptions{Path: "some-bundle.tar"}).
+				Return(nil)
+
+			command.SetArgs([]string{"load", "some-bundle.tar"})
+			h.AssertNil(t, command.Execute())
+		})
+	})
+}
+
+func EqBundleOptions(builder, output string) gomock.Matcher {
+	return bundleOptionsMatcher{
+		description: fmt.Sprintf("BuilderImage=%s and Output=%s", builder, output),
+		equals: func(o pack.BundleOptions) bool {
+			return o.BuilderImage == builder && o.Output == output
+		},
+	}
+}
+
+type bundleOptionsMatcher struct {
+	equals      func(pack.BundleOptions) bool
+	description string
+}
+
+func (m bundleOptionsMatcher) Matches(x interface{}) bool {
+	if b, ok := x.(pack.BundleOptions); ok {
+		return m.equals(b)
+	}
+	return false
+}
+
+func (m bundleOptionsMatcher) String() string {
+	return "is a BundleOptions with " + m.description
+}
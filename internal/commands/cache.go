@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+func Cache(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Interact with pack's local caches",
+	}
+	cmd.AddCommand(cacheClear(logger))
+	AddHelpFlag(cmd, "cache")
+	return cmd
+}
+
+func cacheClear(logger logging.Logger) *cobra.Command {
+	var clearBlobs bool
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Args:  cobra.NoArgs,
+		Short: "Clear pack's local caches",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if !clearBlobs {
+				return errors.New("nothing to clear: specify --blobs")
+			}
+
+			packHome, err := config.PackHome()
+			if err != nil {
+				return errors.Wrap(err, "getting pack home")
+			}
+
+			blobCacheDir := filepath.Join(packHome, "download-cache")
+			if err := os.RemoveAll(blobCacheDir); err != nil {
+				return errors.Wrapf(err, "clearing blob cache '%s'", blobCacheDir)
+			}
+			logger.Infof("Successfully cleared blob cache %s", style.Symbol(blobCacheDir))
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&clearBlobs, "blobs", false, "Clear the downloaded blob cache")
+	AddHelpFlag(cmd, "clear")
+	return cmd
+}
@@ -17,11 +17,18 @@ import (
 //go:generate mockgen -package testmocks -destination testmocks/mock_pack_client.go github.com/buildpacks/pack/internal/commands PackClient
 type PackClient interface {
 	InspectBuilder(string, bool) (*pack.BuilderInfo, error)
+	InspectBuildpack(context.Context, string, bool) (*pack.BuildpackInfo, error)
 	InspectImage(string, bool) (*pack.ImageInfo, error)
+	ImageDigest(string, bool) (string, error)
 	Rebase(context.Context, pack.RebaseOptions) error
+	PlanRebase(context.Context, pack.RebaseOptions) (pack.RebasePlan, error)
 	CreateBuilder(context.Context, pack.CreateBuilderOptions) error
 	PackageBuildpack(ctx context.Context, opts pack.PackageBuildpackOptions) error
 	Build(context.Context, pack.BuildOptions) error
+	BuildWithResult(context.Context, pack.BuildOptions) (*pack.BuildResult, error)
+	Prune(context.Context, pack.PruneOptions) ([]string, error)
+	Bundle(context.Context, pack.BundleOptions) error
+	LoadBundle(context.Context, pack.LoadBundleOptions) error
 }
 
 func AddHelpFlag(cmd *cobra.Command, commandName string) {
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/suggest"
+	"github.com/buildpacks/pack/logging"
+)
+
+func Config(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Interact with pack config",
+	}
+	cmd.AddCommand(configGet(logger, cfg))
+	cmd.AddCommand(configSet(logger, cfg))
+	cmd.AddCommand(configList(logger, cfg))
+	cmd.AddCommand(configTrustedBuilders(logger, cfg))
+	AddHelpFlag(cmd, "config")
+	return cmd
+}
+
+func configGet(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Get a config value",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			value, err := config.Get(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			logger.Info(value)
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "get")
+	return cmd
+}
+
+func configSet(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Set a config value",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			updated, err := config.Set(cfg, key, value)
+			if err != nil {
+				return err
+			}
+
+			configPath, err := config.DefaultConfigPath()
+			if err != nil {
+				return errors.Wrap(err, "getting config path")
+			}
+			if err := config.Update(updated, configPath); err != nil {
+				return err
+			}
+
+			logger.Infof("Set %s to %s", style.Symbol(key), style.Symbol(value))
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "set")
+	return cmd
+}
+
+func configList(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List all config values",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			entries := config.List(cfg)
+			if len(entries) == 0 {
+				logger.Info("(no config values set)")
+				return nil
+			}
+			for _, entry := range entries {
+				logger.Info(entry)
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
+
+func configTrustedBuilders(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trusted-builders",
+		Short: "Manage the trusted builders list",
+	}
+	cmd.AddCommand(configTrustedBuildersAdd(logger, cfg))
+	cmd.AddCommand(configTrustedBuildersRemove(logger, cfg))
+	cmd.AddCommand(configTrustedBuildersList(logger, cfg))
+	AddHelpFlag(cmd, "trusted-builders")
+	return cmd
+}
+
+func configTrustedBuildersAdd(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <builder>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Trust a builder",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			builderName := args[0]
+			if _, err := name.ParseReference(builderName, name.WeakValidation); err != nil {
+				return errors.Wrapf(err, "invalid builder name %s", style.Symbol(builderName))
+			}
+
+			if IsTrustedBuilder(cfg, builderName) {
+				logger.Infof("Builder %s is already trusted", style.Symbol(builderName))
+				return nil
+			}
+
+			cfg.TrustedBuilders = append(cfg.TrustedBuilders, config.TrustedBuilder{Name: builderName})
+			if err := updateConfig(cfg); err != nil {
+				return err
+			}
+
+			logger.Infof("Builder %s is now trusted", style.Symbol(builderName))
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "add")
+	return cmd
+}
+
+func configTrustedBuildersRemove(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <builder>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Stop trusting a builder",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			builderName := args[0]
+			if suggest.IsSuggested(cfg, builderName) {
+				return errors.Errorf("builder %s is a suggested builder and is always trusted", style.Symbol(builderName))
+			}
+
+			var remaining []config.TrustedBuilder
+			for _, trusted := range cfg.TrustedBuilders {
+				if trusted.Name != builderName {
+					remaining = append(remaining, trusted)
+				}
+			}
+			if len(remaining) == len(cfg.TrustedBuilders) {
+				logger.Infof("Builder %s is not trusted", style.Symbol(builderName))
+				return nil
+			}
+
+			cfg.TrustedBuilders = remaining
+			if err := updateConfig(cfg); err != nil {
+				return err
+			}
+
+			logger.Infof("Builder %s is no longer trusted", style.Symbol(builderName))
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "remove")
+	return cmd
+}
+
+func configTrustedBuildersList(logger logging.Logger, cfg config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List the trusted builders",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			var builders []string
+			for _, builder := range suggest.Builders(cfg) {
+				builders = append(builders, builder.Image+" (suggested)")
+			}
+			for _, trusted := range cfg.TrustedBuilders {
+				if !suggest.IsSuggested(cfg, trusted.Name) {
+					builders = append(builders, trusted.Name)
+				}
+			}
+			sort.Strings(builders)
+
+			for _, builder := range builders {
+				logger.Info(builder)
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
+
+// updateConfig persists cfg to the default config path, preserving unmanaged
+// keys in the file.
+func updateConfig(cfg config.Config) error {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return errors.Wrap(err, "getting config path")
+	}
+	return config.Update(cfg, configPath)
+}
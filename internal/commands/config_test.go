@@ -0,0 +1,172 @@
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestConfigCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Commands", testConfigCommand, spec.Report(report.Terminal{}))
+}
+
+func testConfigCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command      *cobra.Command
+		outBuf       bytes.Buffer
+		tmpDir       string
+		origPackHome string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pack.config.command.test.")
+		h.AssertNil(t, err)
+		origPackHome = os.Getenv("PACK_HOME")
+		h.AssertNil(t, os.Setenv("PACK_HOME", tmpDir))
+
+		command = commands.Config(logging.NewLogWithWriters(&outBuf, &outBuf), config.Config{
+			DefaultBuilder: "some/builder",
+			RunImages: []config.RunImage{
+				{Image: "some/run", Mirrors: []string{"example.com/some/run"}},
+			},
+		})
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.Setenv("PACK_HOME", origPackHome))
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Config", func() {
+		when("get", func() {
+			it("prints the value", func() {
+				command.SetArgs([]string{"get", "default-builder"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "some/builder")
+			})
+
+			it("errors for an unknown key", func() {
+				command.SetArgs([]string{"get", "some-key"})
+				err := command.Execute()
+				h.AssertError(t, err, "unknown config key 'some-key'")
+			})
+		})
+
+		when("set", func() {
+			it("writes the value to the config file", func() {
+				command.SetArgs([]string{"set", "default-builder", "other/builder"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Set 'default-builder' to 'other/builder'")
+
+				b, err := ioutil.ReadFile(filepath.Join(tmpDir, "config.toml"))
+				h.AssertNil(t, err)
+				h.AssertContains(t, string(b), `default-builder-image = "other/builder"`)
+			})
+
+			it("errors for an unknown key", func() {
+				command.SetArgs([]string{"set", "some-key", "some-value"})
+				err := command.Execute()
+				h.AssertError(t, err, "unknown config key 'some-key'")
+			})
+		})
+
+		when("list", func() {
+			it("prints every set key", func() {
+				command.SetArgs([]string{"list"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "default-builder = some/builder")
+				h.AssertContains(t, outBuf.String(), "run-image-mirrors.some/run = example.com/some/run")
+			})
+		})
+
+		when("trusted-builders", func() {
+			when("add", func() {
+				it("persists the builder to the config file", func() {
+					command.SetArgs([]string{"trusted-builders", "add", "some/trusted-builder"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Builder 'some/trusted-builder' is now trusted")
+
+					cfg, err := config.Read(filepath.Join(tmpDir, "config.toml"))
+					h.AssertNil(t, err)
+					h.AssertEq(t, cfg.TrustedBuilders, []config.TrustedBuilder{{Name: "some/trusted-builder"}})
+				})
+
+				it("does not duplicate an already trusted builder", func() {
+					command = commands.Config(logging.NewLogWithWriters(&outBuf, &outBuf), config.Config{
+						TrustedBuilders: []config.TrustedBuilder{{Name: "some/trusted-builder"}},
+					})
+					command.SetArgs([]string{"trusted-builders", "add", "some/trusted-builder"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Builder 'some/trusted-builder' is already trusted")
+
+					_, err := os.Stat(filepath.Join(tmpDir, "config.toml"))
+					h.AssertTrue(t, os.IsNotExist(err))
+				})
+
+				it("errors for an invalid builder name", func() {
+					command.SetArgs([]string{"trusted-builders", "add", "in valid"})
+					err := command.Execute()
+					h.AssertError(t, err, "invalid builder name 'in valid'")
+				})
+			})
+
+			when("remove", func() {
+				it.Before(func() {
+					command = commands.Config(logging.NewLogWithWriters(&outBuf, &outBuf), config.Config{
+						TrustedBuilders: []config.TrustedBuilder{{Name: "some/trusted-builder"}},
+					})
+				})
+
+				it("removes the builder from the config file", func() {
+					command.SetArgs([]string{"trusted-builders", "remove", "some/trusted-builder"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Builder 'some/trusted-builder' is no longer trusted")
+
+					cfg, err := config.Read(filepath.Join(tmpDir, "config.toml"))
+					h.AssertNil(t, err)
+					h.AssertEq(t, len(cfg.TrustedBuilders), 0)
+				})
+
+				it("reports builders that were not trusted", func() {
+					command.SetArgs([]string{"trusted-builders", "remove", "other/builder"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "Builder 'other/builder' is not trusted")
+				})
+
+				it("refuses to remove a suggested builder", func() {
+					command.SetArgs([]string{"trusted-builders", "remove", "cloudfoundry/cnb:bionic"})
+					err := command.Execute()
+					h.AssertError(t, err, "builder 'cloudfoundry/cnb:bionic' is a suggested builder and is always trusted")
+				})
+			})
+
+			when("list", func() {
+				it("includes suggested builders along with configured ones", func() {
+					command = commands.Config(logging.NewLogWithWriters(&outBuf, &outBuf), config.Config{
+						TrustedBuilders: []config.TrustedBuilder{{Name: "some/trusted-builder"}},
+					})
+					command.SetArgs([]string{"trusted-builders", "list"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), "some/trusted-builder")
+					h.AssertContains(t, outBuf.String(), "cloudfoundry/cnb:bionic (suggested)")
+					h.AssertContains(t, outBuf.String(), "heroku/buildpacks:18 (suggested)")
+				})
+			})
+		})
+	})
+}
@@ -16,6 +16,7 @@ type CreateBuilderFlags struct {
 	BuilderTomlPath string
 	Publish         bool
 	NoPull          bool
+	Flatten         int
 }
 
 func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
@@ -26,6 +27,10 @@ func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		Short: "Create builder image",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Publish && flags.NoPull {
+				logger.Warn("Flag --no-pull ignored in combination with --publish")
+			}
+
 			builderConfig, warns, err := builder.ReadConfig(flags.BuilderTomlPath)
 			if err != nil {
 				return errors.Wrap(err, "invalid builder toml")
@@ -40,10 +45,15 @@ func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
 				Config:      builderConfig,
 				Publish:     flags.Publish,
 				NoPull:      flags.NoPull,
+				Flatten:     flags.Flatten,
 			}); err != nil {
 				return err
 			}
-			logger.Infof("Successfully created builder image %s", style.Symbol(imageName))
+			action := "created"
+			if flags.Publish {
+				action = "published"
+			}
+			logger.Infof("Successfully %s builder image %s", action, style.Symbol(imageName))
 			logging.Tip(logger, "Run %s to use this builder", style.Symbol(fmt.Sprintf("pack build <image-name> --builder %s", imageName)))
 			return nil
 		}),
@@ -52,6 +62,7 @@ func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
 	cmd.Flags().StringVarP(&flags.BuilderTomlPath, "builder-config", "b", "", "Path to builder TOML file (required)")
 	cmd.MarkFlagRequired("builder-config")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
+	cmd.Flags().IntVar(&flags.Flatten, "flatten", 0, "Squash the buildpacks into at most this many image layers, to speed up builder pulls\n  (defaults to one layer per buildpack)")
 	AddHelpFlag(cmd, "create-builder")
 	return cmd
 }
@@ -53,6 +53,46 @@ func testCreateBuilderCommand(t *testing.T, when spec.G, it spec.S) {
 	})
 
 	when("#CreateBuilder", func() {
+		when("--publish is used", func() {
+			it.Before(func() {
+				h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+[[buildpacks]]
+  id = "some.buildpack"
+
+[[order]]
+  [[order.group]]
+    id = "some.buildpack"
+`), 0666))
+			})
+
+			it("logs the published success message", func() {
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), gomock.Any()).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--builder-config", builderConfigPath,
+					"--publish",
+				})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Successfully published builder image 'some/builder'")
+			})
+
+			it("warns that --no-pull is ignored", func() {
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), gomock.Any()).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--builder-config", builderConfigPath,
+					"--publish",
+					"--no-pull",
+				})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Warning: Flag --no-pull ignored in combination with --publish")
+			})
+		})
+
 		when("warnings encountered in builder.toml", func() {
 			it.Before(func() {
 				h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -19,13 +20,16 @@ import (
 )
 
 func InspectBuilder(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	var output string
+	var detectAgainst string
+
 	cmd := &cobra.Command{
 		Use:   "inspect-builder <builder-image-name>",
 		Short: "Show information about a builder",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			if cfg.DefaultBuilder == "" && len(args) == 0 {
-				suggestSettingBuilder(logger, client)
+				suggestSettingBuilder(logger, cfg, client)
 				return MakeSoftError()
 			}
 
@@ -34,6 +38,18 @@ func InspectBuilder(logger logging.Logger, cfg config.Config, client PackClient)
 				imageName = args[0]
 			}
 
+			if detectAgainst != "" {
+				return detectPreview(logger, client, imageName, detectAgainst)
+			}
+
+			switch output {
+			case "text":
+			case "json":
+				return inspectBuilderJSON(logger, client, imageName)
+			default:
+				return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(output))
+			}
+
 			presentRemote, remoteOutput, remoteWarnings, remoteErr := inspectBuilderOutput(client, cfg, imageName, false)
 			presentLocal, localOutput, localWarnings, localErr := inspectBuilderOutput(client, cfg, imageName, true)
 
@@ -68,10 +84,53 @@ func InspectBuilder(logger logging.Logger, cfg config.Config, client PackClient)
 			return nil
 		}),
 	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	cmd.Flags().StringVar(&detectAgainst, "detect-against", "", "Run only the lifecycle's detect phase against this app path and report the buildpack group\n  that would be used, without building an image")
 	AddHelpFlag(cmd, "inspect-builder")
 	return cmd
 }
 
+// detectPreview runs a detect-only build of the given app path, so the group
+// the builder would use can be inspected without building anything.
+func detectPreview(logger logging.Logger, client PackClient, imageName, appPath string) error {
+	logger.Infof("Detecting buildpacks for %s with builder %s\n", style.Symbol(appPath), style.Symbol(imageName))
+	return client.Build(createCancellableContext(), pack.BuildOptions{
+		// No image is exported; the name only seeds the build cache volumes.
+		Image:      "pack.local/detect-preview",
+		Builder:    imageName,
+		AppPath:    appPath,
+		DetectOnly: true,
+	})
+}
+
+func inspectBuilderJSON(logger logging.Logger, client PackClient, imageName string) error {
+	remoteInfo, err := client.InspectBuilder(imageName, false)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting remote image '%s'", imageName)
+	}
+
+	localInfo, err := client.InspectBuilder(imageName, true)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting local image '%s'", imageName)
+	}
+
+	if remoteInfo == nil && localInfo == nil {
+		return errors.Errorf("Unable to find builder '%s' locally or remotely.", imageName)
+	}
+
+	out, err := json.MarshalIndent(struct {
+		BuilderName string            `json:"builder-name"`
+		Remote      *pack.BuilderInfo `json:"remote"`
+		Local       *pack.BuilderInfo `json:"local"`
+	}{imageName, remoteInfo, localInfo}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logger.Info(string(out))
+	return nil
+}
+
 func inspectBuilderOutput(client PackClient, cfg config.Config, imageName string, local bool) (present bool, output string, warning []string, err error) {
 	source := "remote"
 	if local {
@@ -156,7 +215,11 @@ Detection Order:
 		warnings = append(warnings, "Users must supply buildpacks from the host machine")
 	}
 
-	order, err := detectionOrderOutput(info.Order)
+	orderTree := info.OrderTree
+	if len(orderTree) == 0 {
+		orderTree = flatDetectionOrder(info.Order)
+	}
+	order, err := detectionOrderOutput(orderTree)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +252,25 @@ Detection Order:
 		warnings = append(warnings, fmt.Sprintf("%s does not specify lifecycle platform api version", style.Symbol(imageName)))
 	}
 
+	for _, entry := range info.MixinReport {
+		if len(entry.Missing) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"buildpack %s requires mixin(s) not provided by the stack: %s",
+				style.Symbol(entry.Buildpack.FullName()),
+				strings.Join(entry.Missing, ", "),
+			))
+		}
+	}
+
+	for _, entry := range info.APIReport {
+		warnings = append(warnings, fmt.Sprintf(
+			"buildpack %s (Buildpack API version %s) is incompatible with lifecycle Buildpack API version %s",
+			style.Symbol(entry.Buildpack.FullName()),
+			entry.API,
+			lcDescriptor.API.BuildpackVersion.String(),
+		))
+	}
+
 	return warnings, tpl.Execute(writer, &struct {
 		Info       pack.BuilderInfo
 		Buildpacks string
@@ -202,7 +284,6 @@ Detection Order:
 	})
 }
 
-// TODO: present buildpack order (inc. nested) [https://github.com/buildpacks/pack/issues/253].
 func buildpacksOutput(bps []dist.BuildpackInfo) (string, error) {
 	buf := &bytes.Buffer{}
 	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 8, ' ', 0)
@@ -252,33 +333,66 @@ func runImagesOutput(runImage string, mirrors []string, cfg config.Config) (stri
 	return strings.TrimSuffix(buf.String(), "\n"), nil
 }
 
-func detectionOrderOutput(order dist.Order) (string, error) {
+// flatDetectionOrder converts a flat order into a detection order tree with no
+// nested groups, for sources that do not resolve meta buildpacks.
+func flatDetectionOrder(order dist.Order) []pack.DetectionOrderGroup {
+	var tree []pack.DetectionOrderGroup
+	for _, entry := range order {
+		group := pack.DetectionOrderGroup{}
+		for _, ref := range entry.Group {
+			group.Group = append(group.Group, pack.DetectionOrderEntry{BuildpackRef: ref})
+		}
+		tree = append(tree, group)
+	}
+	return tree
+}
+
+// detectionOrderOutput renders the detection order tree, indenting the nested
+// groups of meta (order) buildpacks.
+func detectionOrderOutput(tree []pack.DetectionOrderGroup) (string, error) {
 	buf := strings.Builder{}
-	for i, group := range order {
+	for i, group := range tree {
 		buf.WriteString(fmt.Sprintf("  Group #%d:\n", i+1))
+		if err := writeDetectionGroup(&buf, group, "    "); err != nil {
+			return "", err
+		}
+	}
 
-		tabWriter := new(tabwriter.Writer).Init(&buf, 0, 0, 4, ' ', 0)
-		for _, bp := range group.Group {
-			var optional string
-			if bp.Optional {
-				optional = "(optional)"
-			}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
 
-			bpRef := bp.ID
-			if bp.Version != "" {
-				bpRef += "@" + bp.Version
-			}
+func writeDetectionGroup(buf *strings.Builder, group pack.DetectionOrderGroup, indent string) error {
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 4, ' ', 0)
+	for _, bp := range group.Group {
+		var optional string
+		if bp.Optional {
+			optional = "(optional)"
+		}
 
-			if _, err := fmt.Fprintf(tabWriter, "    %s\t%s\n", bpRef, optional); err != nil {
-				return "", err
-			}
+		bpRef := bp.ID
+		if bp.Version != "" {
+			bpRef += "@" + bp.Version
 		}
-		if err := tabWriter.Flush(); err != nil {
-			return "", err
+
+		if _, err := fmt.Fprintf(tabWriter, "%s%s\t%s\n", indent, bpRef, optional); err != nil {
+			return err
 		}
-	}
 
-	return strings.TrimSuffix(buf.String(), "\n"), nil
+		// nested groups interrupt the column alignment, so flush before them
+		if len(bp.Groups) > 0 {
+			if err := tabWriter.Flush(); err != nil {
+				return err
+			}
+			for i, nested := range bp.Groups {
+				buf.WriteString(fmt.Sprintf("%s  Group #%d:\n", indent, i+1))
+				if err := writeDetectionGroup(buf, nested, indent+"    "); err != nil {
+					return err
+				}
+			}
+			tabWriter = new(tabwriter.Writer).Init(buf, 0, 0, 4, ' ', 0)
+		}
+	}
+	return tabWriter.Flush()
 }
 
 func getLocalMirrors(runImage string, cfg config.Config) []string {
@@ -246,6 +246,39 @@ Detection Order:
 			})
 		})
 
+		when("the detection order contains a meta buildpack", func() {
+			it("renders the nested groups indented under the meta buildpack", func() {
+				info := &pack.BuilderInfo{
+					Stack: "test.stack.id",
+					Order: dist.Order{
+						{Group: []dist.BuildpackRef{{BuildpackInfo: dist.BuildpackInfo{ID: "meta.bp", Version: "1.0.0"}}}},
+					},
+					OrderTree: []pack.DetectionOrderGroup{
+						{Group: []pack.DetectionOrderEntry{
+							{
+								BuildpackRef: dist.BuildpackRef{BuildpackInfo: dist.BuildpackInfo{ID: "meta.bp", Version: "1.0.0"}},
+								Groups: []pack.DetectionOrderGroup{
+									{Group: []pack.DetectionOrderEntry{
+										{BuildpackRef: dist.BuildpackRef{BuildpackInfo: dist.BuildpackInfo{ID: "child.bp.one", Version: "1.1.1"}}},
+										{BuildpackRef: dist.BuildpackRef{BuildpackInfo: dist.BuildpackInfo{ID: "child.bp.two"}, Optional: true}},
+									}},
+								},
+							},
+						}},
+					},
+				}
+				mockClient.EXPECT().InspectBuilder("some/image", false).Return(nil, nil)
+				mockClient.EXPECT().InspectBuilder("some/image", true).Return(info, nil)
+
+				command.SetArgs([]string{"some/image"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "  Group #1:\n    meta.bp@1.0.0")
+				h.AssertContains(t, outBuf.String(), "      Group #1:\n        child.bp.one@1.1.1")
+				h.AssertContains(t, outBuf.String(), "child.bp.two          (optional)")
+			})
+		})
+
 		when("the image has empty fields in info", func() {
 			it.Before(func() {
 				mockClient.EXPECT().InspectBuilder("some/image", false).Return(&pack.BuilderInfo{
@@ -336,6 +369,20 @@ Detection Order:
 			})
 		})
 
+		when("--detect-against is passed", func() {
+			it("runs a detect-only build instead of inspecting", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDetectOnly("some/image", "some/app/dir")).
+					Return(nil)
+
+				command.SetArgs([]string{"some/image", "--detect-against", "some/app/dir"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Detecting buildpacks for 'some/app/dir' with builder 'some/image'")
+				h.AssertNotContains(t, outBuf.String(), "Inspecting builder")
+			})
+		})
+
 		when("default builder is not set", func() {
 			when("no builder arg is passed", func() {
 				it.Before(func() {
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+func InspectBuildpack(logger logging.Logger, client PackClient) *cobra.Command {
+	var output string
+	ctx := createCancellableContext()
+
+	cmd := &cobra.Command{
+		Use:   "inspect-buildpack <image-or-archive>",
+		Short: "Show information about a buildpack package",
+		Args:  cobra.ExactArgs(1),
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			locator := args[0]
+
+			switch output {
+			case "text":
+			case "json":
+				return inspectBuildpackJSON(logger, client, ctx, locator)
+			default:
+				return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(output))
+			}
+
+			// Archives yield the same information regardless of daemon, so a single
+			// section is printed for them.
+			if paths.IsURI(locator) || isLocalFile(locator) {
+				info, err := client.InspectBuildpack(ctx, locator, true)
+				if err != nil {
+					return errors.Wrapf(err, "inspecting buildpack '%s'", locator)
+				}
+
+				logger.Infof("Inspecting buildpack: %s\n", style.Symbol(locator))
+				buf := bytes.Buffer{}
+				if err := generateBuildpackOutput(&buf, *info); err != nil {
+					return errors.Wrapf(err, "writing output for buildpack '%s'", locator)
+				}
+				logger.Info(buf.String())
+				return nil
+			}
+
+			presentRemote, remoteOutput, remoteErr := inspectBuildpackOutput(client, ctx, locator, false)
+			presentLocal, localOutput, localErr := inspectBuildpackOutput(client, ctx, locator, true)
+
+			if !presentRemote && !presentLocal {
+				return errors.New(fmt.Sprintf("Unable to find buildpack '%s' locally or remotely.\n", locator))
+			}
+
+			logger.Infof("Inspecting buildpack: %s\n", style.Symbol(locator))
+
+			if remoteErr != nil {
+				logger.Error(remoteErr.Error())
+			} else {
+				logger.Infof("\nREMOTE:\n%s\n", remoteOutput)
+			}
+
+			if localErr != nil {
+				logger.Error(localErr.Error())
+			} else {
+				logger.Infof("\nLOCAL:\n%s\n", localOutput)
+			}
+
+			return nil
+		}),
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	AddHelpFlag(cmd, "inspect-buildpack")
+	return cmd
+}
+
+func isLocalFile(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func inspectBuildpackJSON(logger logging.Logger, client PackClient, ctx context.Context, locator string) error {
+	remoteInfo, err := client.InspectBuildpack(ctx, locator, false)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting remote buildpack '%s'", locator)
+	}
+
+	localInfo, err := client.InspectBuildpack(ctx, locator, true)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting local buildpack '%s'", locator)
+	}
+
+	if remoteInfo == nil && localInfo == nil {
+		return errors.Errorf("Unable to find buildpack '%s' locally or remotely.", locator)
+	}
+
+	out, err := json.MarshalIndent(struct {
+		BuildpackName string              `json:"buildpack-name"`
+		Remote        *pack.BuildpackInfo `json:"remote"`
+		Local         *pack.BuildpackInfo `json:"local"`
+	}{locator, remoteInfo, localInfo}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logger.Info(string(out))
+	return nil
+}
+
+func inspectBuildpackOutput(client PackClient, ctx context.Context, locator string, local bool) (present bool, output string, err error) {
+	source := "remote"
+	if local {
+		source = "local"
+	}
+
+	info, err := client.InspectBuildpack(ctx, locator, local)
+	if err != nil {
+		return true, "", errors.Wrapf(err, "inspecting %s buildpack '%s'", source, locator)
+	}
+
+	if info == nil {
+		return false, "(not present)", nil
+	}
+
+	var buf bytes.Buffer
+	if err := generateBuildpackOutput(&buf, *info); err != nil {
+		return true, "", errors.Wrapf(err, "writing output for %s buildpack '%s'", source, locator)
+	}
+
+	return true, buf.String(), nil
+}
+
+func generateBuildpackOutput(writer io.Writer, info pack.BuildpackInfo) error {
+	tpl := template.Must(template.New("").Parse(`
+Buildpack:
+  ID: {{ .Info.Buildpack.Info.ID }}
+  Version: {{ .Info.Buildpack.Info.Version }}
+  Buildpack API: {{- if .Info.Buildpack.API }} {{ .Info.Buildpack.API }}{{- else }} (none){{- end }}
+
+Stacks:
+{{- if .Info.Buildpack.Stacks }}
+{{- range $_, $stack := .Info.Buildpack.Stacks }}
+  {{ $stack.ID }}
+{{- end }}
+{{- else }}
+  (none)
+{{- end }}
+
+Detection Order:
+{{- if ne .Order "" }}
+{{ .Order }}
+{{- else }}
+  (none)
+{{- end }}
+
+Packaged Buildpacks:
+{{ .Buildpacks }}`,
+	))
+
+	var infos []dist.BuildpackInfo
+	for _, bp := range info.Buildpacks {
+		infos = append(infos, bp.Info)
+	}
+	bps, err := buildpacksOutput(infos)
+	if err != nil {
+		return err
+	}
+
+	order, err := detectionOrderOutput(flatDetectionOrder(info.Buildpack.Order))
+	if err != nil {
+		return err
+	}
+
+	return tpl.Execute(writer, &struct {
+		Info       pack.BuildpackInfo
+		Buildpacks string
+		Order      string
+	}{
+		info,
+		bps,
+		order,
+	})
+}
@@ -0,0 +1,131 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/dist"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestInspectBuildpackCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Commands", testInspectBuildpackCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectBuildpackCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		packageInfo    = &pack.BuildpackInfo{
+			Buildpack: dist.BuildpackDescriptor{
+				API:  api.MustParse("0.2"),
+				Info: dist.BuildpackInfo{ID: "meta.bp", Version: "0.0.1"},
+				Order: dist.Order{
+					{Group: []dist.BuildpackRef{
+						{BuildpackInfo: dist.BuildpackInfo{ID: "child.bp", Version: "1.2.3"}},
+					}},
+				},
+			},
+			Buildpacks: []dist.BuildpackDescriptor{
+				{
+					API:    api.MustParse("0.2"),
+					Info:   dist.BuildpackInfo{ID: "child.bp", Version: "1.2.3"},
+					Stacks: []dist.Stack{{ID: "some.stack.id"}},
+				},
+				{
+					API:  api.MustParse("0.2"),
+					Info: dist.BuildpackInfo{ID: "meta.bp", Version: "0.0.1"},
+				},
+			},
+		}
+		expectedOutput = `
+Buildpack:
+  ID: meta.bp
+  Version: 0.0.1
+  Buildpack API: 0.2
+
+Stacks:
+  (none)
+
+Detection Order:
+  Group #1:
+    child.bp@1.2.3    
+
+Packaged Buildpacks:
+  ID              VERSION
+  child.bp        1.2.3
+  meta.bp         0.0.1
+`
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.InspectBuildpack(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("the buildpack package exists", func() {
+		it("prints the remote and local sections", func() {
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "some/package", false).Return(packageInfo, nil)
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "some/package", true).Return(nil, nil)
+
+			command.SetArgs([]string{"some/package"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Inspecting buildpack: 'some/package'")
+			h.AssertContains(t, outBuf.String(), "REMOTE:\n"+expectedOutput)
+			h.AssertContains(t, outBuf.String(), "LOCAL:\n(not present)")
+		})
+
+		it("prints JSON with -o json", func() {
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "some/package", false).Return(packageInfo, nil)
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "some/package", true).Return(nil, nil)
+
+			command.SetArgs([]string{"some/package", "-o", "json"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), `"buildpack-name": "some/package"`)
+			h.AssertContains(t, outBuf.String(), `"id": "meta.bp"`)
+			h.AssertContains(t, outBuf.String(), `"local": null`)
+		})
+	})
+
+	when("the buildpack package is not found", func() {
+		it("errors", func() {
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "missing/package", false).Return(nil, nil)
+			mockClient.EXPECT().InspectBuildpack(gomock.Any(), "missing/package", true).Return(nil, nil)
+
+			command.SetArgs([]string{"missing/package"})
+			h.AssertError(t, command.Execute(), "Unable to find buildpack 'missing/package' locally or remotely.")
+		})
+	})
+
+	when("the output format is invalid", func() {
+		it("errors", func() {
+			command.SetArgs([]string{"some/package", "-o", "yaml"})
+			h.AssertError(t, command.Execute(), "invalid output format 'yaml': must be 'text' or 'json'")
+		})
+	})
+}
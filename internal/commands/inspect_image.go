@@ -21,7 +21,8 @@ import (
 )
 
 type InspectImageFlags struct {
-	BOM bool
+	BOM    bool
+	Output string
 }
 
 func InspectImage(logger logging.Logger, cfg *config.Config, client PackClient) *cobra.Command {
@@ -43,6 +44,15 @@ func InspectImage(logger logging.Logger, cfg *config.Config, client PackClient)
 			if flags.BOM {
 				return logBOM(remote, local, logger)
 			}
+
+			switch flags.Output {
+			case "text":
+			case "json":
+				return logImageJSON(img, remote, local, *cfg, logger)
+			default:
+				return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(flags.Output))
+			}
+
 			logger.Infof("Inspecting image: %s\n", style.Symbol(img))
 			logDetails(remote, local, *cfg, logger)
 			return nil
@@ -50,6 +60,7 @@ func InspectImage(logger logging.Logger, cfg *config.Config, client PackClient)
 	}
 	AddHelpFlag(cmd, "inspect-image")
 	cmd.Flags().BoolVar(&flags.BOM, "bom", false, "print bill of materials")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "text", "Output format (text, json)")
 	return cmd
 }
 
@@ -77,6 +88,98 @@ func logBOM(remote *pack.ImageInfo, local *pack.ImageInfo, logger logging.Logger
 	return nil
 }
 
+type jsonRunImage struct {
+	Name           string `json:"name"`
+	UserConfigured bool   `json:"user-configured,omitempty"`
+}
+
+type jsonProcess struct {
+	Type    string   `json:"type"`
+	Shell   string   `json:"shell,omitempty"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Default bool     `json:"default"`
+}
+
+type jsonLifecycleMetadata struct {
+	RunImage lifecycle.RunImageMetadata `json:"runImage"`
+	Stack    lifecycle.StackMetadata    `json:"stack"`
+}
+
+type jsonImageInfo struct {
+	StackID           string                     `json:"stack"`
+	Base              lifecycle.RunImageMetadata `json:"base-image"`
+	RunImages         []jsonRunImage             `json:"run-images"`
+	Buildpacks        []lifecycle.Buildpack      `json:"buildpacks"`
+	Processes         []jsonProcess              `json:"processes"`
+	LifecycleMetadata jsonLifecycleMetadata      `json:"lifecycle-metadata"`
+}
+
+func logImageJSON(imageName string, remote *pack.ImageInfo, local *pack.ImageInfo, cfg config.Config, logger logging.Logger) error {
+	out, err := json.MarshalIndent(struct {
+		ImageName string         `json:"image-name"`
+		Remote    *jsonImageInfo `json:"remote"`
+		Local     *jsonImageInfo `json:"local"`
+	}{imageName, newJSONImageInfo(remote, cfg), newJSONImageInfo(local, cfg)}, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "writing image output")
+	}
+	logger.Info(string(out))
+	return nil
+}
+
+func newJSONImageInfo(info *pack.ImageInfo, cfg config.Config) *jsonImageInfo {
+	if info == nil {
+		return nil
+	}
+
+	runImages := []jsonRunImage{}
+	for _, m := range getLocalMirrors(info.Stack.RunImage.Image, cfg) {
+		runImages = append(runImages, jsonRunImage{Name: m, UserConfigured: true})
+	}
+	if info.Stack.RunImage.Image != "" {
+		runImages = append(runImages, jsonRunImage{Name: info.Stack.RunImage.Image})
+	}
+	for _, m := range info.Stack.RunImage.Mirrors {
+		runImages = append(runImages, jsonRunImage{Name: m})
+	}
+
+	processes := []jsonProcess{}
+	if p := info.Processes.DefaultProcess; p != nil {
+		processes = append(processes, newJSONProcess(*p, true))
+	}
+	for _, p := range info.Processes.OtherProcesses {
+		processes = append(processes, newJSONProcess(p, false))
+	}
+
+	return &jsonImageInfo{
+		StackID:    info.StackID,
+		Base:       info.Base,
+		RunImages:  runImages,
+		Buildpacks: info.Buildpacks,
+		Processes:  processes,
+		LifecycleMetadata: jsonLifecycleMetadata{
+			RunImage: info.Base,
+			Stack:    info.Stack,
+		},
+	}
+}
+
+func newJSONProcess(p lifecycle.Process, d bool) jsonProcess {
+	shell := ""
+	if !p.Direct {
+		shell = "bash"
+	}
+
+	return jsonProcess{
+		Type:    p.Type,
+		Shell:   shell,
+		Command: p.Command,
+		Args:    p.Args,
+		Default: d,
+	}
+}
+
 func logDetails(remote *pack.ImageInfo, local *pack.ImageInfo, cfg config.Config, logger logging.Logger) {
 	imgTpl := template.Must(template.New("runImages").Parse(runImagesTemplate))
 	imgTpl = template.Must(imgTpl.New("buildpacks").Parse(buildpacksTemplate))
@@ -2,6 +2,7 @@ package commands_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"path/filepath"
@@ -76,6 +77,23 @@ func testInspectImageCommand(t *testing.T, when spec.G, it spec.S) {
 						`{"remote":null,"local":null}`+"\n")
 				})
 			})
+
+			when("--output json", func() {
+				it("adds nulls for missing images", func() {
+					command.SetArgs([]string{"some/image", "--output", "json"})
+					h.AssertNil(t, command.Execute())
+
+					var out struct {
+						ImageName string      `json:"image-name"`
+						Remote    interface{} `json:"remote"`
+						Local     interface{} `json:"local"`
+					}
+					h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &out))
+					h.AssertEq(t, out.ImageName, "some/image")
+					h.AssertNil(t, out.Remote)
+					h.AssertNil(t, out.Local)
+				})
+			})
 		})
 
 		when("inspector returns an error", func() {
@@ -276,6 +294,111 @@ func testInspectImageCommand(t *testing.T, when spec.G, it spec.S) {
 					})
 				})
 
+				when("--output json", func() {
+					it("prints image metadata as JSON", func() {
+						command.SetArgs([]string{"some/image", "--output", "json"})
+						h.AssertNil(t, command.Execute())
+
+						type imageInfo struct {
+							Stack string `json:"stack"`
+							Base  struct {
+								Reference string `json:"reference"`
+								TopLayer  string `json:"topLayer"`
+							} `json:"base-image"`
+							RunImages []struct {
+								Name           string `json:"name"`
+								UserConfigured bool   `json:"user-configured"`
+							} `json:"run-images"`
+							Buildpacks []struct {
+								ID      string `json:"id"`
+								Version string `json:"version"`
+							} `json:"buildpacks"`
+							Processes []struct {
+								Type    string   `json:"type"`
+								Shell   string   `json:"shell"`
+								Command string   `json:"command"`
+								Args    []string `json:"args"`
+								Default bool     `json:"default"`
+							} `json:"processes"`
+							LifecycleMetadata struct {
+								RunImage struct {
+									Reference string `json:"reference"`
+								} `json:"runImage"`
+								Stack struct {
+									RunImage struct {
+										Image string `json:"image"`
+									} `json:"runImage"`
+								} `json:"stack"`
+							} `json:"lifecycle-metadata"`
+						}
+						var out struct {
+							ImageName string     `json:"image-name"`
+							Remote    *imageInfo `json:"remote"`
+							Local     *imageInfo `json:"local"`
+						}
+						h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &out))
+						h.AssertEq(t, out.ImageName, "some/image")
+
+						h.AssertNotNil(t, out.Remote)
+						h.AssertEq(t, out.Remote.Stack, "test.stack.id.remote")
+						h.AssertEq(t, out.Remote.Base.Reference, "some-remote-run-image-reference")
+						h.AssertEq(t, out.Remote.Base.TopLayer, "some-remote-top-layer")
+						h.AssertEq(t, len(out.Remote.RunImages), 3)
+						h.AssertEq(t, out.Remote.RunImages[0].Name, "some-remote-run-image")
+						h.AssertEq(t, len(out.Remote.Buildpacks), 2)
+						h.AssertEq(t, out.Remote.Buildpacks[0].ID, "test.bp.one.remote")
+						h.AssertEq(t, out.Remote.Buildpacks[0].Version, "1.0.0")
+						h.AssertEq(t, len(out.Remote.Processes), 2)
+						h.AssertEq(t, out.Remote.Processes[0].Type, "some-remote-type")
+						h.AssertEq(t, out.Remote.Processes[0].Shell, "bash")
+						h.AssertEq(t, out.Remote.Processes[0].Default, true)
+						h.AssertEq(t, out.Remote.Processes[1].Type, "other-remote-type")
+						h.AssertEq(t, out.Remote.Processes[1].Shell, "")
+						h.AssertEq(t, out.Remote.Processes[1].Default, false)
+						h.AssertEq(t, out.Remote.LifecycleMetadata.RunImage.Reference, "some-remote-run-image-reference")
+						h.AssertEq(t, out.Remote.LifecycleMetadata.Stack.RunImage.Image, "some-remote-run-image")
+
+						h.AssertNotNil(t, out.Local)
+						h.AssertEq(t, out.Local.Stack, "test.stack.id.local")
+						h.AssertEq(t, out.Local.Base.Reference, "some-local-run-image-reference")
+					})
+
+					when("there are locally configured mirrors", func() {
+						it.Before(func() {
+							cfg.RunImages = []config.RunImage{
+								{Image: "some-remote-run-image", Mirrors: []string{"first-remote-user-mirror"}},
+							}
+						})
+
+						it("flags them as user-configured", func() {
+							command.SetArgs([]string{"some/image", "--output", "json"})
+							h.AssertNil(t, command.Execute())
+
+							var out struct {
+								Remote struct {
+									RunImages []struct {
+										Name           string `json:"name"`
+										UserConfigured bool   `json:"user-configured"`
+									} `json:"run-images"`
+								} `json:"remote"`
+							}
+							h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &out))
+							h.AssertEq(t, len(out.Remote.RunImages), 4)
+							h.AssertEq(t, out.Remote.RunImages[0].Name, "first-remote-user-mirror")
+							h.AssertEq(t, out.Remote.RunImages[0].UserConfigured, true)
+							h.AssertEq(t, out.Remote.RunImages[1].UserConfigured, false)
+						})
+					})
+				})
+
+				when("an invalid output format is requested", func() {
+					it("fails with an error", func() {
+						command.SetArgs([]string{"some/image", "--output", "yaml"})
+						err := command.Execute()
+						h.AssertError(t, err, "invalid output format 'yaml'")
+					})
+				})
+
 				when("--bom", func() {
 					it("prints the bom as JSON", func() {
 						command.SetArgs([]string{"some/image", "--bom"})
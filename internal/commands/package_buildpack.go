@@ -16,6 +16,9 @@ type PackageBuildpackFlags struct {
 	PackageTomlPath string
 	Publish         bool
 	NoPull          bool
+	Format          string
+	OutputDir       string
+	AdditionalTags  []string
 }
 
 type PackageConfigReader interface {
@@ -34,6 +37,16 @@ func PackageBuildpack(logger logging.Logger, client BuildpackPackager, packageCo
 		Args:  cobra.ExactArgs(1),
 		Short: "Package buildpack",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Format == pack.FormatOCI && flags.Publish {
+				return errors.New("--publish cannot be used with --format 'oci'")
+			}
+			if flags.OutputDir != "" && flags.Format != pack.FormatOCI {
+				return errors.New("--output requires --format 'oci'")
+			}
+			if len(flags.AdditionalTags) > 0 && flags.Format == pack.FormatOCI {
+				return errors.New("--tag cannot be used with --format 'oci'")
+			}
+
 			config, err := packageConfigReader.Read(flags.PackageTomlPath)
 			if err != nil {
 				return errors.Wrap(err, "reading config")
@@ -41,13 +54,22 @@ func PackageBuildpack(logger logging.Logger, client BuildpackPackager, packageCo
 
 			imageName := args[0]
 			if err := client.PackageBuildpack(ctx, pack.PackageBuildpackOptions{
-				Name:    imageName,
-				Config:  config,
-				Publish: flags.Publish,
-				NoPull:  flags.NoPull,
+				Name:           imageName,
+				Config:         config,
+				Publish:        flags.Publish,
+				NoPull:         flags.NoPull,
+				Format:         flags.Format,
+				OutputDir:      flags.OutputDir,
+				AdditionalTags: flags.AdditionalTags,
 			}); err != nil {
 				return err
 			}
+
+			if flags.Format == pack.FormatOCI {
+				logger.Infof("Successfully created OCI layout for package %s at %s", style.Symbol(imageName), style.Symbol(flags.OutputDir))
+				return nil
+			}
+
 			action := "created"
 			if flags.Publish {
 				action = "published"
@@ -60,6 +82,9 @@ func PackageBuildpack(logger logging.Logger, client BuildpackPackager, packageCo
 	cmd.MarkFlagRequired("package-config")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().BoolVar(&flags.NoPull, "no-pull", false, "Skip pulling packages before use")
+	cmd.Flags().StringVar(&flags.Format, "format", pack.FormatImage, "Output format ('image' to save to the daemon or registry, 'oci' to write an OCI image layout directory)")
+	cmd.Flags().StringVarP(&flags.OutputDir, "output", "o", "", "Directory to write the OCI image layout to (required with --format 'oci');\n  these layouts are for transfer only and cannot be used directly by a builder")
+	cmd.Flags().StringSliceVarP(&flags.AdditionalTags, "tag", "t", nil, "Additional tag to apply to the package image, in the form '<image-name>:<tag>',\n  pushed along with the image name when --publish is set"+multiValueHelp("tag"))
 	AddHelpFlag(cmd, "package-buildpack")
 
 	return cmd
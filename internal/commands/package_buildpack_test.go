@@ -94,6 +94,22 @@ func testPackageBuildpackCommand(t *testing.T, when spec.G, it spec.S) {
 
 			h.AssertEq(t, receivedOptions.Config, myConfig)
 		})
+
+		it("creates package with additional tags", func() {
+			fakeBuildpackPackager := &fakes.FakeBuildpackPackager{}
+
+			packageBuildpackCommand := packageBuildpackCommand(
+				withBuildpackPackager(fakeBuildpackPackager),
+				withTags("some/package:latest", "some/package:v1"),
+			)
+
+			err := packageBuildpackCommand.Execute()
+			h.AssertNil(t, err)
+
+			receivedOptions := fakeBuildpackPackager.CreateCalledWithOptions
+
+			h.AssertEq(t, receivedOptions.AdditionalTags, []string{"some/package:latest", "some/package:v1"})
+		})
 	})
 }
 
@@ -104,6 +120,7 @@ type packageCommandConfig struct {
 
 	imageName  string
 	configPath string
+	tags       []string
 }
 
 type packageCommandOption func(config *packageCommandConfig)
@@ -123,7 +140,11 @@ func packageBuildpackCommand(ops ...packageCommandOption) *cobra.Command {
 	}
 
 	cmd := commands.PackageBuildpack(config.logger, config.buildpackPackager, config.configReader)
-	cmd.SetArgs([]string{config.imageName, "--package-config", config.configPath})
+	args := []string{config.imageName, "--package-config", config.configPath}
+	for _, tag := range config.tags {
+		args = append(args, "--tag", tag)
+	}
+	cmd.SetArgs(args)
 
 	return cmd
 }
@@ -158,6 +179,12 @@ func withConfigPath(path string) packageCommandOption {
 	}
 }
 
+func withTags(tags ...string) packageCommandOption {
+	return func(config *packageCommandConfig) {
+		config.tags = tags
+	}
+}
+
 func whereReadReturns(config pubbldpkg.Config, err error) func(*fakes.FakePackageConfigReader) {
 	return func(r *fakes.FakePackageConfigReader) {
 		r.ReadReturnConfig = config
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type PruneFlags struct {
+	DryRun bool
+	Yes    bool
+}
+
+func Prune(logger logging.Logger, packClient PackClient) *cobra.Command {
+	var flags PruneFlags
+	ctx := createCancellableContext()
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cobra.NoArgs,
+		Short: "Remove cache volumes whose app image no longer exists",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			orphans, err := packClient.Prune(ctx, pack.PruneOptions{DryRun: true})
+			if err != nil {
+				return err
+			}
+			if len(orphans) == 0 {
+				logger.Info("No orphaned pack caches found")
+				return nil
+			}
+
+			logger.Infof("Found %d orphaned cache volume(s):", len(orphans))
+			for _, volume := range orphans {
+				logger.Infof("  %s", style.Symbol(volume))
+			}
+			if flags.DryRun {
+				return nil
+			}
+
+			if !flags.Yes {
+				fmt.Fprintf(logger.Writer(), "Remove %d cache volume(s)? (y/N) ", len(orphans))
+				answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+					logger.Info("Aborting")
+					return nil
+				}
+			}
+
+			removed, err := packClient.Prune(ctx, pack.PruneOptions{})
+			if err != nil {
+				return err
+			}
+			logger.Infof("Successfully removed %d cache volume(s)", len(removed))
+			return nil
+		}),
+	}
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "List orphaned caches without removing them")
+	cmd.Flags().BoolVar(&flags.Yes, "yes", false, "Skip the confirmation prompt")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
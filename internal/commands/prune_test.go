@@ -0,0 +1,116 @@
+package commands_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Commands", testPruneCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.Prune(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Prune", func() {
+		when("no orphaned caches exist", func() {
+			it("reports that there is nothing to remove", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return(nil, nil)
+
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No orphaned pack caches found")
+			})
+		})
+
+		when("--dry-run is passed", func() {
+			it("lists the candidates without removing them", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+
+				command.SetArgs([]string{"--dry-run"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Found 1 orphaned cache volume(s)")
+				h.AssertContains(t, outBuf.String(), "'pack-cache-aaaaaaaaaaaa.build'")
+				h.AssertNotContains(t, outBuf.String(), "Successfully removed")
+			})
+		})
+
+		when("--yes is passed", func() {
+			it("removes the candidates without prompting", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+
+				command.SetArgs([]string{"--yes"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully removed 1 cache volume(s)")
+			})
+		})
+
+		when("the prompt is confirmed", func() {
+			it("removes the candidates", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+
+				command.SetIn(strings.NewReader("y\n"))
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully removed 1 cache volume(s)")
+			})
+		})
+
+		when("the prompt is declined", func() {
+			it("aborts without removing anything", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return([]string{"pack-cache-aaaaaaaaaaaa.build"}, nil)
+
+				command.SetIn(strings.NewReader("n\n"))
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Aborting")
+			})
+		})
+
+		when("the client fails", func() {
+			it("returns the error", func() {
+				mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{DryRun: true}).Return(nil, errors.New("some error"))
+
+				command.SetArgs([]string{})
+				err := command.Execute()
+				h.AssertError(t, err, "some error")
+			})
+		})
+	})
+}
@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpacks/pack"
@@ -11,6 +14,8 @@ import (
 
 func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
 	var opts pack.RebaseOptions
+	var dryRun bool
+	var output string
 	ctx := createCancellableContext()
 
 	cmd := &cobra.Command{
@@ -20,6 +25,19 @@ func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			opts.RepoName = args[0]
 			opts.AdditionalMirrors = getMirrors(cfg)
+
+			if dryRun {
+				plan, err := client.PlanRebase(ctx, opts)
+				if err != nil {
+					return err
+				}
+				return reportRebasePlan(logger, plan, output)
+			}
+
+			if cmd.Flags().Changed("output") {
+				return errors.New("--output requires --dry-run")
+			}
+
 			if err := client.Rebase(ctx, opts); err != nil {
 				return err
 			}
@@ -29,7 +47,37 @@ func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.
 	}
 	cmd.Flags().BoolVar(&opts.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().BoolVar(&opts.SkipPull, "no-pull", false, "Skip pulling app and run images before use")
-	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing")
+	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing.\nA comma-separated list is tried in order, using the first image that is\n  pullable and on the app image's stack")
+	cmd.Flags().StringSliceVarP(&opts.AdditionalTags, "tag", "t", nil, "Additional tag to apply to the rebased image, in the form '<image-name>:<tag>',\n  pushed along with the image name when --publish is set"+multiValueHelp("tag"))
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report which run image would be selected and what rebasing would change,\n  without mutating or pushing anything")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format for --dry-run (text, json)")
 	AddHelpFlag(cmd, "rebase")
 	return cmd
 }
+
+func reportRebasePlan(logger logging.Logger, plan pack.RebasePlan, output string) error {
+	switch output {
+	case "text":
+		logger.Infof("Selected run image %s", style.Symbol(plan.RunImage))
+		logger.Infof("Current base image: %s", style.Symbol(plan.CurrentBase))
+		logger.Infof("Target base image: %s", style.Symbol(plan.TargetBase))
+		if plan.BaseUpToDate {
+			logger.Info("Base image is up to date; rebasing would not change the image")
+		}
+		if plan.AppLayersPreserved {
+			logger.Info("App layers would be preserved")
+		} else {
+			logger.Warn("App layers would NOT be preserved; rebasing onto this base image would fail")
+		}
+		return nil
+	case "json":
+		buf, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		logger.Info(string(buf))
+		return nil
+	default:
+		return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(output))
+	}
+}
@@ -20,7 +20,7 @@ func SetDefaultBuilder(logger logging.Logger, cfg config.Config, client PackClie
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 || args[0] == "" {
 				logger.Infof("Usage:\n\t%s\n", cmd.UseLine())
-				suggestBuilders(logger, client)
+				suggestBuilders(logger, cfg, client)
 				return nil
 			}
 
@@ -1,98 +1,198 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
-	"sort"
 	"sync"
 	"text/tabwriter"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/suggest"
 	"github.com/buildpacks/pack/logging"
 )
 
-type suggestedBuilder struct {
-	Name               string
-	Image              string
-	DefaultDescription string
-}
+const (
+	// checkWorkers bounds how many builders are checked for reachability at
+	// once when --check is set.
+	checkWorkers = 4
+	// checkTimeout is how long a single builder's reachability check may take
+	// before it is reported unavailable.
+	checkTimeout = 5 * time.Second
+)
 
-var suggestedBuilders = []suggestedBuilder{
-	{
-		Name:               "Cloud Foundry",
-		Image:              "cloudfoundry/cnb:bionic",
-		DefaultDescription: "Small base image with Java & Node.js",
-	},
-	{
-		Name:               "Cloud Foundry",
-		Image:              "cloudfoundry/cnb:cflinuxfs3",
-		DefaultDescription: "Larger base image with Java, Node.js & Python",
-	},
-	{
-		Name:               "Cloud Foundry",
-		Image:              "cloudfoundry/cnb:tiny",
-		DefaultDescription: "Tiny base image (bionic build image, distroless run image) with buildpacks for Golang",
-	},
-	{
-		Name:               "Heroku",
-		Image:              "heroku/buildpacks:18",
-		DefaultDescription: "heroku-18 base image with buildpacks for Ruby, Java, Node.js, Python, Golang, & PHP",
-	},
-}
+func SuggestBuilders(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	var output string
+	var check bool
 
-func SuggestBuilders(logger logging.Logger, client PackClient) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "suggest-builders",
 		Short: "Display list of recommended builders",
 		Args:  cobra.NoArgs,
-		Run: func(*cobra.Command, []string) {
-			suggestBuilders(logger, client)
-		},
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			switch output {
+			case "text":
+				suggestBuildersWithCheck(logger, cfg, client, check)
+				return nil
+			case "json":
+				return suggestBuildersJSON(logger, cfg, client, check)
+			default:
+				return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(output))
+			}
+		}),
 	}
 
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	cmd.Flags().BoolVar(&check, "check", false, "Check whether each builder is reachable with the current registry configuration")
 	AddHelpFlag(cmd, "suggest-builders")
 	return cmd
 }
 
-func suggestSettingBuilder(logger logging.Logger, client PackClient) {
+func suggestSettingBuilder(logger logging.Logger, cfg config.Config, client PackClient) {
 	logger.Info("Please select a default builder with:")
 	logger.Info("")
 	logger.Info("\tpack set-default-builder <builder image>")
 	logger.Info("")
-	suggestBuilders(logger, client)
+	suggestBuilders(logger, cfg, client)
 }
 
-func suggestBuilders(logger logging.Logger, client PackClient) {
-	sort.Slice(suggestedBuilders, func(i, j int) bool { return suggestedBuilders[i].Image < suggestedBuilders[j].Image })
+func suggestBuilders(logger logging.Logger, cfg config.Config, client PackClient) {
+	suggestBuildersWithCheck(logger, cfg, client, false)
+}
+
+func suggestBuildersWithCheck(logger logging.Logger, cfg config.Config, client PackClient, check bool) {
+	builders := suggest.Builders(cfg)
+	descriptions := builderDescriptions(builders, client)
+
+	var available []bool
+	if check {
+		available = builderAvailability(builders, client)
+	}
 
 	logger.Info("Suggested builders:")
 
-	// Fetch descriptions concurrently.
-	descriptions := make([]string, len(suggestedBuilders))
+	tw := tabwriter.NewWriter(logger.Writer(), 10, 10, 5, ' ', tabwriter.TabIndent)
+	for i, builder := range builders {
+		if check {
+			fmt.Fprintf(tw, "\t%s:\t%s\t%s\t%s\t\n", builder.Name, style.Symbol(builder.Image), availabilityLabel(available[i]), descriptions[i])
+		} else {
+			fmt.Fprintf(tw, "\t%s:\t%s\t%s\t\n", builder.Name, style.Symbol(builder.Image), descriptions[i])
+		}
+	}
+	fmt.Fprintln(tw)
+
+	logging.Tip(logger, "Learn more about a specific builder with:\n")
+	logger.Info("\tpack inspect-builder [builder image]")
+}
+
+func suggestBuildersJSON(logger logging.Logger, cfg config.Config, client PackClient, check bool) error {
+	builders := suggest.Builders(cfg)
+	descriptions := builderDescriptions(builders, client)
+
+	var available []bool
+	if check {
+		available = builderAvailability(builders, client)
+	}
+
+	entries := make([]struct {
+		Name        string `json:"name"`
+		Image       string `json:"image"`
+		Description string `json:"description"`
+		Available   *bool  `json:"available,omitempty"`
+	}, len(builders))
+	for i, builder := range builders {
+		entries[i].Name = builder.Name
+		entries[i].Image = builder.Image
+		entries[i].Description = descriptions[i]
+		if check {
+			entries[i].Available = &available[i]
+		}
+	}
+
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logger.Info(string(out))
+	return nil
+}
+
+func availabilityLabel(available bool) string {
+	if available {
+		return "(available)"
+	}
+	return "(unavailable)"
+}
+
+// builderDescriptions fetches each builder's description concurrently,
+// falling back to the curated default when the image cannot be inspected.
+func builderDescriptions(builders []suggest.Builder, client PackClient) []string {
+	descriptions := make([]string, len(builders))
 
 	var wg sync.WaitGroup
-	for i, builder := range suggestedBuilders {
+	for i, builder := range builders {
 		wg.Add(1)
 
-		go func(i int, builder suggestedBuilder) {
+		go func(i int, builder suggest.Builder) {
 			descriptions[i] = getBuilderDescription(builder, client)
 			wg.Done()
 		}(i, builder)
 	}
 	wg.Wait()
 
-	tw := tabwriter.NewWriter(logger.Writer(), 10, 10, 5, ' ', tabwriter.TabIndent)
-	for i, builder := range suggestedBuilders {
-		fmt.Fprintf(tw, "\t%s:\t%s\t%s\t\n", builder.Name, style.Symbol(builder.Image), descriptions[i])
+	return descriptions
+}
+
+// builderAvailability reports whether each builder's image is reachable with
+// the current registry configuration. Checks run on a bounded worker pool and
+// any single builder that does not answer within checkTimeout is reported
+// unavailable.
+func builderAvailability(builders []suggest.Builder, client PackClient) []bool {
+	available := make([]bool, len(builders))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < checkWorkers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				available[i] = builderReachable(builders[i], client)
+			}
+		}()
 	}
-	fmt.Fprintln(tw)
 
-	logging.Tip(logger, "Learn more about a specific builder with:\n")
-	logger.Info("\tpack inspect-builder [builder image]")
+	for i := range builders {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return available
+}
+
+func builderReachable(builder suggest.Builder, client PackClient) bool {
+	result := make(chan bool, 1)
+	go func() {
+		info, err := client.InspectBuilder(builder.Image, false)
+		result <- err == nil && info != nil
+	}()
+
+	select {
+	case reachable := <-result:
+		return reachable
+	case <-time.After(checkTimeout):
+		return false
+	}
 }
 
-func getBuilderDescription(builder suggestedBuilder, client PackClient) string {
+func getBuilderDescription(builder suggest.Builder, client PackClient) string {
 	info, err := client.InspectBuilder(builder.Image, false)
 	if err == nil && info.Description != "" {
 		return info.Description
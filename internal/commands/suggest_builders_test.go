@@ -14,6 +14,7 @@ import (
 	"github.com/buildpacks/pack"
 	"github.com/buildpacks/pack/internal/commands"
 	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
 	ilogging "github.com/buildpacks/pack/internal/logging"
 	"github.com/buildpacks/pack/logging"
 	h "github.com/buildpacks/pack/testhelpers"
@@ -38,7 +39,7 @@ func testSuggestBuildersCommand(t *testing.T, when spec.G, it spec.S) {
 		mockController = gomock.NewController(t)
 		mockClient = testmocks.NewMockPackClient(mockController)
 		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
-		command = commands.SuggestBuilders(logger, mockClient)
+		command = commands.SuggestBuilders(logger, config.Config{}, mockClient)
 	})
 
 	when("#SuggestBuilders", func() {
@@ -96,6 +97,66 @@ func testSuggestBuildersCommand(t *testing.T, when spec.G, it spec.S) {
 				assertDefaultDescriptions(t, outBuf)
 			})
 		})
+
+		when("config adds suggested builders", func() {
+			it.Before(func() {
+				mockClient.EXPECT().InspectBuilder(gomock.Any(), false).Return(nil, errors.New("some error")).AnyTimes()
+				command = commands.SuggestBuilders(logger, config.Config{
+					SuggestedBuilders: []config.SuggestedBuilder{
+						{Name: "Acme", Image: "acme/builder:latest", Description: "Acme's internal builder"},
+					},
+				}, mockClient)
+			})
+
+			it("displays them alongside the curated list", func() {
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+				h.AssertContainsMatch(t, outBuf.String(), `Acme:\s+'acme/builder:latest'\s+Acme's internal builder`)
+				assertDefaultDescriptions(t, outBuf)
+			})
+		})
+
+		when("--output json", func() {
+			it.Before(func() {
+				mockClient.EXPECT().InspectBuilder(gomock.Any(), false).Return(nil, errors.New("some error")).AnyTimes()
+			})
+
+			it("renders the builders as JSON", func() {
+				command.SetArgs([]string{"--output", "json"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), `"image": "heroku/buildpacks:18"`)
+				h.AssertContains(t, outBuf.String(), `"description": "Tiny base image (bionic build image, distroless run image) with buildpacks for Golang"`)
+			})
+		})
+
+		when("--check", func() {
+			it.Before(func() {
+				mockClient.EXPECT().InspectBuilder("heroku/buildpacks:18", false).Return(nil, errors.New("some error")).AnyTimes()
+				mockClient.EXPECT().InspectBuilder(gomock.Any(), false).Return(&pack.BuilderInfo{}, nil).AnyTimes()
+			})
+
+			it("annotates each builder with its reachability", func() {
+				command.SetArgs([]string{"--check"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContainsMatch(t, outBuf.String(), `'cloudfoundry/cnb:bionic'\s+\(available\)`)
+				h.AssertContainsMatch(t, outBuf.String(), `'heroku/buildpacks:18'\s+\(unavailable\)`)
+			})
+
+			it("includes availability in JSON output", func() {
+				command.SetArgs([]string{"--check", "--output", "json"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), `"available": true`)
+				h.AssertContains(t, outBuf.String(), `"available": false`)
+			})
+		})
+
+		when("--output is unknown", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"--output", "yaml"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid output format 'yaml': must be 'text' or 'json'")
+			})
+		})
 	})
 }
 
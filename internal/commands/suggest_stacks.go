@@ -1,69 +1,53 @@
 package commands
 
 import (
+	"encoding/json"
 	"html/template"
-	"sort"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/suggest"
 	"github.com/buildpacks/pack/logging"
 )
 
-type suggestedStack struct {
-	ID          string
-	Description string
-	Maintainer  string
-	BuildImage  string
-	RunImage    string
-}
-
-var suggestedStacks = []suggestedStack{
-	{
-		ID:          "heroku-18",
-		Description: "The official Heroku stack based on Ubuntu 18.04",
-		Maintainer:  "Heroku",
-		BuildImage:  "heroku/pack:18-build",
-		RunImage:    "heroku/pack:18",
-	},
-	{
-		ID:          "io.buildpacks.stacks.bionic",
-		Description: "A minimal Cloud Foundry stack based on Ubuntu 18.04",
-		Maintainer:  "Cloud Foundry",
-		BuildImage:  "cloudfoundry/build:base-cnb",
-		RunImage:    "cloudfoundry/run:base-cnb",
-	},
-	{
-		ID:          "org.cloudfoundry.stacks.cflinuxfs3",
-		Description: "A large Cloud Foundry stack based on Ubuntu 18.04",
-		Maintainer:  "Cloud Foundry",
-		BuildImage:  "cloudfoundry/build:full-cnb",
-		RunImage:    "cloudfoundry/run:full-cnb",
-	},
-	{
-		ID:          "org.cloudfoundry.stacks.tiny",
-		Description: "A tiny Cloud Foundry stack based on Ubuntu 18.04, similar to distroless",
-		Maintainer:  "Cloud Foundry",
-		BuildImage:  "cloudfoundry/build:tiny-cnb",
-		RunImage:    "cloudfoundry/run:tiny-cnb",
-	},
-}
-
 func SuggestStacks(logger logging.Logger) *cobra.Command {
+	var output string
+	var osFilter string
+	var archFilter string
+
 	cmd := &cobra.Command{
 		Use:   "suggest-stacks",
 		Short: "Display list of recommended stacks",
 		Args:  cobra.NoArgs,
-		Run: func(*cobra.Command, []string) {
-			suggestStacks(logger)
-		},
+		RunE: logError(logger, func(*cobra.Command, []string) error {
+			stacks := suggest.Stacks(osFilter, archFilter)
+
+			switch output {
+			case "text":
+				suggestStacks(logger, stacks)
+				return nil
+			case "json":
+				return suggestStacksJSON(logger, stacks)
+			default:
+				return errors.Errorf("invalid output format %s: must be 'text' or 'json'", style.Symbol(output))
+			}
+		}),
 	}
 
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	cmd.Flags().StringVar(&osFilter, "os", "", "Only show stacks for this operating system, e.g. 'linux'")
+	cmd.Flags().StringVar(&archFilter, "arch", "", "Only show stacks for this architecture, e.g. 'amd64'")
 	AddHelpFlag(cmd, "suggest-stacks")
 	return cmd
 }
 
-func suggestStacks(log logging.Logger) {
-	sort.Slice(suggestedStacks, func(i, j int) bool { return suggestedStacks[i].ID < suggestedStacks[j].ID })
+func suggestStacks(log logging.Logger, stacks []suggest.Stack) {
+	if len(stacks) == 0 {
+		log.Info("No suggested stacks match the requested platform.")
+		return
+	}
 
 	tmpl := template.Must(template.New("").Parse(`
 Stacks maintained by the community:
@@ -76,5 +60,15 @@ Stacks maintained by the community:
     Run Image: {{ .RunImage }}
 {{- end }}
 `))
-	tmpl.Execute(log.Writer(), suggestedStacks)
+	tmpl.Execute(log.Writer(), stacks)
+}
+
+func suggestStacksJSON(log logging.Logger, stacks []suggest.Stack) error {
+	buf, err := json.MarshalIndent(stacks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	log.Info(string(buf))
+	return nil
 }
@@ -2,6 +2,7 @@ package commands_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/sclevine/spec"
@@ -59,5 +60,54 @@ Stacks maintained by the community:
     Run Image: cloudfoundry/run:tiny-cnb
 `)
 		})
+
+		when("json output is requested", func() {
+			it("displays stack information as a sorted JSON array", func() {
+				command.SetArgs([]string{"--output", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var stacks []struct {
+					ID          string `json:"id"`
+					Description string `json:"description"`
+					Maintainer  string `json:"maintainer"`
+					BuildImage  string `json:"build-image"`
+					RunImage    string `json:"run-image"`
+				}
+				h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &stacks))
+				h.AssertEq(t, len(stacks), 4)
+				h.AssertEq(t, stacks[0].ID, "heroku-18")
+				h.AssertEq(t, stacks[0].Maintainer, "Heroku")
+				h.AssertEq(t, stacks[0].BuildImage, "heroku/pack:18-build")
+				h.AssertEq(t, stacks[0].RunImage, "heroku/pack:18")
+			})
+		})
+
+		when("a platform filter is given", func() {
+			it("reports when no stacks match", func() {
+				command.SetArgs([]string{"--os", "windows"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No suggested stacks match the requested platform.")
+			})
+
+			it("returns an empty JSON array when no stacks match", func() {
+				command.SetArgs([]string{"--os", "windows", "--output", "json"})
+				h.AssertNil(t, command.Execute())
+				h.AssertEq(t, outBuf.String(), "[]\n")
+			})
+
+			it("keeps matching stacks", func() {
+				command.SetArgs([]string{"--os", "linux", "--arch", "amd64"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Stack ID: heroku-18")
+			})
+		})
+
+		when("an invalid output format is requested", func() {
+			it("fails with an error", func() {
+				command.SetArgs([]string{"--output", "yaml"})
+				err := command.Execute()
+				h.AssertError(t, err, "invalid output format")
+			})
+		})
 	})
 }
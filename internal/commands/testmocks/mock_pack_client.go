@@ -6,11 +6,9 @@ package testmocks
 
 import (
 	context "context"
-	reflect "reflect"
-
-	gomock "github.com/golang/mock/gomock"
-
 	pack "github.com/buildpacks/pack"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
 )
 
 // MockPackClient is a mock of PackClient interface
@@ -50,6 +48,35 @@ func (mr *MockPackClientMockRecorder) Build(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockPackClient)(nil).Build), arg0, arg1)
 }
 
+// BuildWithResult mocks base method
+func (m *MockPackClient) BuildWithResult(arg0 context.Context, arg1 pack.BuildOptions) (*pack.BuildResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildWithResult", arg0, arg1)
+	ret0, _ := ret[0].(*pack.BuildResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildWithResult indicates an expected call of BuildWithResult
+func (mr *MockPackClientMockRecorder) BuildWithResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildWithResult", reflect.TypeOf((*MockPackClient)(nil).BuildWithResult), arg0, arg1)
+}
+
+// Bundle mocks base method
+func (m *MockPackClient) Bundle(arg0 context.Context, arg1 pack.BundleOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bundle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bundle indicates an expected call of Bundle
+func (mr *MockPackClientMockRecorder) Bundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bundle", reflect.TypeOf((*MockPackClient)(nil).Bundle), arg0, arg1)
+}
+
 // CreateBuilder mocks base method
 func (m *MockPackClient) CreateBuilder(arg0 context.Context, arg1 pack.CreateBuilderOptions) error {
 	m.ctrl.T.Helper()
@@ -64,6 +91,21 @@ func (mr *MockPackClientMockRecorder) CreateBuilder(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBuilder", reflect.TypeOf((*MockPackClient)(nil).CreateBuilder), arg0, arg1)
 }
 
+// ImageDigest mocks base method
+func (m *MockPackClient) ImageDigest(arg0 string, arg1 bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageDigest", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageDigest indicates an expected call of ImageDigest
+func (mr *MockPackClientMockRecorder) ImageDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageDigest", reflect.TypeOf((*MockPackClient)(nil).ImageDigest), arg0, arg1)
+}
+
 // InspectBuilder mocks base method
 func (m *MockPackClient) InspectBuilder(arg0 string, arg1 bool) (*pack.BuilderInfo, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +121,21 @@ func (mr *MockPackClientMockRecorder) InspectBuilder(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectBuilder", reflect.TypeOf((*MockPackClient)(nil).InspectBuilder), arg0, arg1)
 }
 
+// InspectBuildpack mocks base method
+func (m *MockPackClient) InspectBuildpack(arg0 context.Context, arg1 string, arg2 bool) (*pack.BuildpackInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectBuildpack", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pack.BuildpackInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectBuildpack indicates an expected call of InspectBuildpack
+func (mr *MockPackClientMockRecorder) InspectBuildpack(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectBuildpack", reflect.TypeOf((*MockPackClient)(nil).InspectBuildpack), arg0, arg1, arg2)
+}
+
 // InspectImage mocks base method
 func (m *MockPackClient) InspectImage(arg0 string, arg1 bool) (*pack.ImageInfo, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +151,20 @@ func (mr *MockPackClientMockRecorder) InspectImage(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockPackClient)(nil).InspectImage), arg0, arg1)
 }
 
+// LoadBundle mocks base method
+func (m *MockPackClient) LoadBundle(arg0 context.Context, arg1 pack.LoadBundleOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBundle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBundle indicates an expected call of LoadBundle
+func (mr *MockPackClientMockRecorder) LoadBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBundle", reflect.TypeOf((*MockPackClient)(nil).LoadBundle), arg0, arg1)
+}
+
 // PackageBuildpack mocks base method
 func (m *MockPackClient) PackageBuildpack(arg0 context.Context, arg1 pack.PackageBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -108,6 +179,36 @@ func (mr *MockPackClientMockRecorder) PackageBuildpack(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageBuildpack", reflect.TypeOf((*MockPackClient)(nil).PackageBuildpack), arg0, arg1)
 }
 
+// PlanRebase mocks base method
+func (m *MockPackClient) PlanRebase(arg0 context.Context, arg1 pack.RebaseOptions) (pack.RebasePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanRebase", arg0, arg1)
+	ret0, _ := ret[0].(pack.RebasePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanRebase indicates an expected call of PlanRebase
+func (mr *MockPackClientMockRecorder) PlanRebase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanRebase", reflect.TypeOf((*MockPackClient)(nil).PlanRebase), arg0, arg1)
+}
+
+// Prune mocks base method
+func (m *MockPackClient) Prune(arg0 context.Context, arg1 pack.PruneOptions) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune
+func (mr *MockPackClientMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockPackClient)(nil).Prune), arg0, arg1)
+}
+
 // Rebase mocks base method
 func (m *MockPackClient) Rebase(arg0 context.Context, arg1 pack.RebaseOptions) error {
 	m.ctrl.T.Helper()
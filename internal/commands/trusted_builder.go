@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/suggest"
+)
+
+// IsTrustedBuilder reports whether the builder may run privileged lifecycle
+// phases, such as the creator, in a single container. Suggested builders are
+// trusted by default; others must be listed in the config's trusted builders.
+func IsTrustedBuilder(cfg config.Config, builderName string) bool {
+	for _, trusted := range cfg.TrustedBuilders {
+		if trusted.Name == builderName {
+			return true
+		}
+	}
+	return suggest.IsSuggested(cfg, builderName)
+}
@@ -0,0 +1,44 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/config"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTrustedBuilder(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "TrustedBuilder", testTrustedBuilder, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testTrustedBuilder(t *testing.T, when spec.G, it spec.S) {
+	when("#IsTrustedBuilder", func() {
+		it("trusts suggested builders by default", func() {
+			h.AssertTrue(t, commands.IsTrustedBuilder(config.Config{}, "cloudfoundry/cnb:bionic"))
+			h.AssertTrue(t, commands.IsTrustedBuilder(config.Config{}, "heroku/buildpacks:18"))
+		})
+
+		it("trusts builders listed in the config", func() {
+			cfg := config.Config{
+				TrustedBuilders: []config.TrustedBuilder{{Name: "some/builder:tag"}},
+			}
+
+			h.AssertTrue(t, commands.IsTrustedBuilder(cfg, "some/builder:tag"))
+		})
+
+		it("does not trust other builders", func() {
+			cfg := config.Config{
+				TrustedBuilders: []config.TrustedBuilder{{Name: "some/builder:tag"}},
+			}
+
+			h.AssertFalse(t, commands.IsTrustedBuilder(cfg, "other/builder:tag"))
+		})
+	})
+}
@@ -9,8 +9,14 @@ import (
 )
 
 type Config struct {
-	RunImages      []RunImage `toml:"run-images"`
-	DefaultBuilder string     `toml:"default-builder-image,omitempty"`
+	RunImages          []RunImage         `toml:"run-images"`
+	DefaultBuilder     string             `toml:"default-builder-image,omitempty"`
+	DefaultPlatform    string             `toml:"default-platform,omitempty"`
+	RegistryMirrors    map[string]string  `toml:"registry-mirrors,omitempty"`
+	InsecureRegistries []string           `toml:"insecure-registries,omitempty"`
+	DownloadTimeout    string             `toml:"download-timeout,omitempty"`
+	TrustedBuilders    []TrustedBuilder   `toml:"trusted-builders,omitempty"`
+	SuggestedBuilders  []SuggestedBuilder `toml:"suggested-builders,omitempty"`
 }
 
 type RunImage struct {
@@ -18,6 +24,16 @@ type RunImage struct {
 	Mirrors []string `toml:"mirrors"`
 }
 
+type TrustedBuilder struct {
+	Name string `toml:"name"`
+}
+
+type SuggestedBuilder struct {
+	Name        string `toml:"name"`
+	Image       string `toml:"image"`
+	Description string `toml:"description"`
+}
+
 func DefaultConfigPath() (string, error) {
 	home, err := PackHome()
 	if err != nil {
@@ -132,6 +132,149 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#Get", func() {
+		var cfg config.Config
+
+		it.Before(func() {
+			cfg = config.Config{
+				DefaultBuilder: "some/builder",
+				RunImages: []config.RunImage{
+					{Image: "some/run", Mirrors: []string{"example.com/some/run", "example.com/some/mirror"}},
+				},
+			}
+		})
+
+		it("returns the default builder", func() {
+			value, err := config.Get(cfg, "default-builder")
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, "some/builder")
+		})
+
+		it("returns run image mirrors as a comma-separated list", func() {
+			value, err := config.Get(cfg, "run-image-mirrors.some/run")
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, "example.com/some/run,example.com/some/mirror")
+		})
+
+		it("returns an empty value for an unset run image", func() {
+			value, err := config.Get(cfg, "run-image-mirrors.other/run")
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, "")
+		})
+
+		it("errors for an unknown key", func() {
+			_, err := config.Get(cfg, "some-key")
+			h.AssertError(t, err, "unknown config key 'some-key'")
+		})
+	})
+
+	when("#Set", func() {
+		it("sets the default builder", func() {
+			cfg, err := config.Set(config.Config{}, "default-builder", "some/builder")
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.DefaultBuilder, "some/builder")
+		})
+
+		it("sets run image mirrors from a comma-separated list", func() {
+			cfg, err := config.Set(config.Config{}, "run-image-mirrors.some/run", "example.com/some/run,example.com/some/mirror")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(cfg.RunImages), 1)
+			h.AssertEq(t, cfg.RunImages[0].Image, "some/run")
+			h.AssertEq(t, cfg.RunImages[0].Mirrors, []string{"example.com/some/run", "example.com/some/mirror"})
+		})
+
+		it("removes mirrors when the value is empty", func() {
+			cfg := config.Config{RunImages: []config.RunImage{
+				{Image: "some/run", Mirrors: []string{"example.com/some/run"}},
+			}}
+			cfg, err := config.Set(cfg, "run-image-mirrors.some/run", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(cfg.RunImages[0].Mirrors), 0)
+		})
+
+		it("sets a registry mirror", func() {
+			cfg, err := config.Set(config.Config{}, "registry-mirrors.docker.io", "registry.internal")
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.RegistryMirrors["docker.io"], "registry.internal")
+
+			value, err := config.Get(cfg, "registry-mirrors.docker.io")
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, "registry.internal")
+		})
+
+		it("removes a registry mirror when the value is empty", func() {
+			cfg := config.Config{RegistryMirrors: map[string]string{"docker.io": "registry.internal"}}
+			cfg, err := config.Set(cfg, "registry-mirrors.docker.io", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(cfg.RegistryMirrors), 0)
+		})
+
+		it("sets insecure registries from a comma-separated list", func() {
+			cfg, err := config.Set(config.Config{}, "insecure-registries", "registry.internal:5000,other.internal")
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.InsecureRegistries, []string{"registry.internal:5000", "other.internal"})
+
+			value, err := config.Get(cfg, "insecure-registries")
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, "registry.internal:5000,other.internal")
+		})
+
+		it("removes insecure registries when the value is empty", func() {
+			cfg := config.Config{InsecureRegistries: []string{"registry.internal:5000"}}
+			cfg, err := config.Set(cfg, "insecure-registries", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(cfg.InsecureRegistries), 0)
+		})
+
+		it("errors for an unknown key", func() {
+			_, err := config.Set(config.Config{}, "some-key", "some-value")
+			h.AssertError(t, err, "unknown config key 'some-key'")
+		})
+	})
+
+	when("#List", func() {
+		it("renders every set key", func() {
+			entries := config.List(config.Config{
+				DefaultBuilder: "some/builder",
+				RunImages: []config.RunImage{
+					{Image: "some/run", Mirrors: []string{"example.com/some/run"}},
+				},
+			})
+			h.AssertEq(t, entries, []string{
+				"default-builder = some/builder",
+				"run-image-mirrors.some/run = example.com/some/run",
+			})
+		})
+
+		it("returns nothing for an empty config", func() {
+			h.AssertEq(t, len(config.List(config.Config{})), 0)
+		})
+	})
+
+	when("#Update", func() {
+		it("preserves top-level keys pack does not manage", func() {
+			h.AssertNil(t, ioutil.WriteFile(configPath, []byte(`
+default-builder-image = "old/builder"
+some-future-key = "some-value"
+`), 0666))
+
+			h.AssertNil(t, config.Update(config.Config{DefaultBuilder: "some/builder"}, configPath))
+
+			b, err := ioutil.ReadFile(configPath)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(b), `default-builder-image = "some/builder"`)
+			h.AssertContains(t, string(b), `some-future-key = "some-value"`)
+		})
+
+		it("writes a new file when none exists", func() {
+			h.AssertNil(t, config.Update(config.Config{DefaultBuilder: "some/builder"}, configPath))
+
+			b, err := ioutil.ReadFile(configPath)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(b), `default-builder-image = "some/builder"`)
+		})
+	})
+
 	when("#SetRunImageMirrors", func() {
 		when("run image exists in config", func() {
 			it("replaces the mirrors", func() {
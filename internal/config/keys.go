@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DefaultBuilderKey is the config key holding the default builder image.
+const DefaultBuilderKey = "default-builder"
+
+// runImageMirrorsPrefix prefixes per-run-image mirror keys, as in
+// 'run-image-mirrors.some/run'.
+const runImageMirrorsPrefix = "run-image-mirrors."
+
+// registryMirrorsPrefix prefixes per-registry pull-through mirror keys, as in
+// 'registry-mirrors.docker.io'.
+const registryMirrorsPrefix = "registry-mirrors."
+
+// InsecureRegistriesKey is the config key holding the registry hosts pack
+// talks to over plain HTTP, as a comma-separated list.
+const InsecureRegistriesKey = "insecure-registries"
+
+// managedKeys are the top-level TOML keys owned by this version of pack; any
+// other keys in the config file are preserved as-is by Update.
+var managedKeys = []string{"run-images", "default-builder-image", "registry-mirrors", "insecure-registries", "trusted-builders", "suggested-builders"}
+
+// Get returns the value of a well-known config key. Mirror lists are rendered
+// as a comma-separated string.
+func Get(cfg Config, key string) (string, error) {
+	switch {
+	case key == DefaultBuilderKey:
+		return cfg.DefaultBuilder, nil
+	case strings.HasPrefix(key, runImageMirrorsPrefix):
+		image := strings.TrimPrefix(key, runImageMirrorsPrefix)
+		for _, ri := range cfg.RunImages {
+			if ri.Image == image {
+				return strings.Join(ri.Mirrors, ","), nil
+			}
+		}
+		return "", nil
+	case strings.HasPrefix(key, registryMirrorsPrefix):
+		return cfg.RegistryMirrors[strings.TrimPrefix(key, registryMirrorsPrefix)], nil
+	case key == InsecureRegistriesKey:
+		return strings.Join(cfg.InsecureRegistries, ","), nil
+	}
+	return "", unknownKeyError(key)
+}
+
+// Set updates a well-known config key and returns the updated config. Setting
+// a mirror key to the empty string removes the mirrors for that run image.
+func Set(cfg Config, key, value string) (Config, error) {
+	switch {
+	case key == DefaultBuilderKey:
+		cfg.DefaultBuilder = value
+		return cfg, nil
+	case strings.HasPrefix(key, runImageMirrorsPrefix):
+		image := strings.TrimPrefix(key, runImageMirrorsPrefix)
+		var mirrors []string
+		if value != "" {
+			mirrors = strings.Split(value, ",")
+		}
+		return SetRunImageMirrors(cfg, image, mirrors), nil
+	case strings.HasPrefix(key, registryMirrorsPrefix):
+		registry := strings.TrimPrefix(key, registryMirrorsPrefix)
+		if value == "" {
+			delete(cfg.RegistryMirrors, registry)
+			return cfg, nil
+		}
+		if cfg.RegistryMirrors == nil {
+			cfg.RegistryMirrors = map[string]string{}
+		}
+		cfg.RegistryMirrors[registry] = value
+		return cfg, nil
+	case key == InsecureRegistriesKey:
+		cfg.InsecureRegistries = nil
+		if value != "" {
+			cfg.InsecureRegistries = strings.Split(value, ",")
+		}
+		return cfg, nil
+	}
+	return cfg, unknownKeyError(key)
+}
+
+// List renders every set key in 'key = value' form, in the order Get and Set
+// accept them.
+func List(cfg Config) []string {
+	var entries []string
+	if cfg.DefaultBuilder != "" {
+		entries = append(entries, fmt.Sprintf("%s = %s", DefaultBuilderKey, cfg.DefaultBuilder))
+	}
+	for _, ri := range cfg.RunImages {
+		entries = append(entries, fmt.Sprintf("%s%s = %s", runImageMirrorsPrefix, ri.Image, strings.Join(ri.Mirrors, ",")))
+	}
+	var registries []string
+	for registry := range cfg.RegistryMirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	for _, registry := range registries {
+		entries = append(entries, fmt.Sprintf("%s%s = %s", registryMirrorsPrefix, registry, cfg.RegistryMirrors[registry]))
+	}
+	if len(cfg.InsecureRegistries) > 0 {
+		entries = append(entries, fmt.Sprintf("%s = %s", InsecureRegistriesKey, strings.Join(cfg.InsecureRegistries, ",")))
+	}
+	return entries
+}
+
+// Update rewrites the config at path, preserving any top-level keys that this
+// version of pack does not manage so other tools and newer pack versions can
+// keep state in the same file.
+func Update(cfg Config, path string) error {
+	existing := map[string]interface{}{}
+	if _, err := toml.DecodeFile(path, &existing); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to read config file at path %s", path)
+	}
+	for _, key := range managedKeys {
+		delete(existing, key)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+	if _, err := toml.Decode(buf.String(), &existing); err != nil {
+		return err
+	}
+
+	if err := MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	w, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	return toml.NewEncoder(w).Encode(existing)
+}
+
+func unknownKeyError(key string) error {
+	return errors.Errorf("unknown config key %s: known keys are '%s', '%s', '%s<run-image>' and '%s<registry>'", style.Symbol(key), DefaultBuilderKey, InsecureRegistriesKey, runImageMirrorsPrefix, registryMirrorsPrefix)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
@@ -12,6 +13,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// stopTimeout is how long a container of a cancelled run is given to exit
+// before the daemon kills it.
+const stopTimeout = 10 * time.Second
+
 func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out, errOut io.Writer) error {
 	bodyChan, errChan := docker.ContainerWait(ctx, ctrID, dcontainer.WaitConditionNextExit)
 
@@ -39,6 +44,13 @@ func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out,
 			return fmt.Errorf("failed with status code: %d", body.StatusCode)
 		}
 	case err := <-errChan:
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// the daemon keeps the container running after a cancelled wait;
+			// stop it so the cancelled run does not leave it behind
+			timeout := stopTimeout
+			_ = docker.ContainerStop(context.Background(), ctrID, &timeout)
+			return ctxErr
+		}
 		return err
 	}
 	return <-copyErr
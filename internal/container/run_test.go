@@ -0,0 +1,109 @@
+package container_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/container"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRun(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "container_run", testRun, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+// fakeDockerClient stubs the container calls Run makes; a non-nil exit channel
+// supplies the wait result, otherwise waiting only ends with the context.
+type fakeDockerClient struct {
+	client.CommonAPIClient
+	exit    chan dcontainer.ContainerWaitOKBody
+	stopped chan string
+}
+
+func (f *fakeDockerClient) ContainerWait(ctx context.Context, ctr string, condition dcontainer.WaitCondition) (<-chan dcontainer.ContainerWaitOKBody, <-chan error) {
+	errChan := make(chan error, 1)
+	if f.exit != nil {
+		return f.exit, errChan
+	}
+
+	bodyChan := make(chan dcontainer.ContainerWaitOKBody, 1)
+	go func() {
+		<-ctx.Done()
+		errChan <- ctx.Err()
+	}()
+	return bodyChan, errChan
+}
+
+func (f *fakeDockerClient) ContainerStart(ctx context.Context, ctr string, opts types.ContainerStartOptions) error {
+	return nil
+}
+
+func (f *fakeDockerClient) ContainerLogs(ctx context.Context, ctr string, opts types.ContainerLogsOptions) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeDockerClient) ContainerStop(ctx context.Context, ctr string, timeout *time.Duration) error {
+	f.stopped <- ctr
+	return nil
+}
+
+func testRun(t *testing.T, when spec.G, it spec.S) {
+	when("#Run", func() {
+		when("the context is cancelled", func() {
+			it("stops the container and reports the cancellation", func() {
+				fake := &fakeDockerClient{stopped: make(chan string, 1)}
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := container.Run(ctx, fake, "some-container", ioutil.Discard, ioutil.Discard)
+				h.AssertSameInstance(t, err, context.Canceled)
+
+				select {
+				case id := <-fake.stopped:
+					h.AssertEq(t, id, "some-container")
+				default:
+					t.Fatal("expected the container to be stopped")
+				}
+			})
+		})
+
+		when("the container exits non-zero", func() {
+			it("returns the status code", func() {
+				fake := &fakeDockerClient{
+					exit:    make(chan dcontainer.ContainerWaitOKBody, 1),
+					stopped: make(chan string, 1),
+				}
+				fake.exit <- dcontainer.ContainerWaitOKBody{StatusCode: 7}
+
+				err := container.Run(context.Background(), fake, "some-container", ioutil.Discard, ioutil.Discard)
+				h.AssertError(t, err, "failed with status code: 7")
+			})
+		})
+
+		when("the container exits zero", func() {
+			it("returns nil", func() {
+				fake := &fakeDockerClient{
+					exit:    make(chan dcontainer.ContainerWaitOKBody, 1),
+					stopped: make(chan string, 1),
+				}
+				fake.exit <- dcontainer.ContainerWaitOKBody{StatusCode: 0}
+
+				err := container.Run(context.Background(), fake, "some-container", ioutil.Discard, ioutil.Discard)
+				h.AssertNil(t, err)
+			})
+		})
+	})
+}
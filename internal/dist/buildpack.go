@@ -50,6 +50,10 @@ func (b BuildpackInfo) FullName() string {
 	return b.ID
 }
 
+// AnyStackID is the wildcard stack ID; a buildpack declaring it supports
+// every stack.
+const AnyStackID = "*"
+
 type Stack struct {
 	ID     string   `json:"id"`
 	Mixins []string `json:"mixins,omitempty"`
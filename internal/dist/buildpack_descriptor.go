@@ -49,11 +49,48 @@ func (b *BuildpackDescriptor) EnsureStackSupport(stackID string, providedMixins
 	return nil
 }
 
+// SupportsStack reports whether the buildpack declares an entry for the given
+// stack. Buildpacks without stacks (order buildpacks) support any stack, as
+// do buildpacks declaring the wildcard stack AnyStackID.
+func (b *BuildpackDescriptor) SupportsStack(stackID string) bool {
+	if len(b.Stacks) == 0 {
+		return true
+	}
+	for _, s := range b.Stacks {
+		if s.ID == stackID || s.ID == AnyStackID {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *BuildpackDescriptor) findMixinsForStack(stackID string) ([]string, error) {
 	for _, s := range b.Stacks {
 		if s.ID == stackID {
 			return s.Mixins, nil
 		}
 	}
-	return nil, fmt.Errorf("buildpack %s does not support stack %s", style.Symbol(b.Info.FullName()), style.Symbol(stackID))
+	// a wildcard entry's mixins apply to any stack without its own entry
+	for _, s := range b.Stacks {
+		if s.ID == AnyStackID {
+			return s.Mixins, nil
+		}
+	}
+	return nil, BuildpackStackUnsupportedError{Buildpack: b.Info.FullName(), Stack: stackID}
+}
+
+// BuildpackStackUnsupportedError reports a buildpack used with a stack it does
+// not declare; callers can match it with errors.Cause.
+type BuildpackStackUnsupportedError struct {
+	Buildpack       string
+	Stack           string
+	SupportedStacks []string
+}
+
+func (e BuildpackStackUnsupportedError) Error() string {
+	msg := fmt.Sprintf("buildpack %s does not support stack %s", style.Symbol(e.Buildpack), style.Symbol(e.Stack))
+	if len(e.SupportedStacks) > 0 {
+		msg = fmt.Sprintf("%s (supported stack(s): %s)", msg, strings.Join(e.SupportedStacks, ", "))
+	}
+	return msg
 }
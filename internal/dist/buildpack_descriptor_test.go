@@ -107,6 +107,11 @@ func testBuildpackDescriptor(t *testing.T, when spec.G, it spec.S) {
 			err := bp.EnsureStackSupport("some.nonexistent.stack.id", []string{"mixinA"}, true)
 
 			h.AssertError(t, err, "buildpack 'some.buildpack.id@some.buildpack.version' does not support stack 'some.nonexistent.stack.id")
+
+			stackErr, ok := err.(BuildpackStackUnsupportedError)
+			h.AssertEq(t, ok, true)
+			h.AssertEq(t, stackErr.Buildpack, "some.buildpack.id@some.buildpack.version")
+			h.AssertEq(t, stackErr.Stack, "some.nonexistent.stack.id")
 		})
 
 		it("skips validating order buildpack", func() {
@@ -120,5 +125,52 @@ func testBuildpackDescriptor(t *testing.T, when spec.G, it spec.S) {
 
 			h.AssertNil(t, bp.EnsureStackSupport("some.stack.id", []string{"mixinA"}, true))
 		})
+
+		it("supports any stack when the buildpack declares the wildcard stack", func() {
+			bp := BuildpackDescriptor{
+				Info: BuildpackInfo{
+					ID:      "some.buildpack.id",
+					Version: "some.buildpack.version",
+				},
+				Stacks: []Stack{{
+					ID:     "*",
+					Mixins: []string{"mixinX"},
+				}},
+			}
+
+			h.AssertNil(t, bp.EnsureStackSupport("some.nonexistent.stack.id", []string{"mixinX"}, true))
+
+			err := bp.EnsureStackSupport("some.nonexistent.stack.id", []string{"mixinA"}, true)
+			h.AssertError(t, err, "buildpack 'some.buildpack.id@some.buildpack.version' requires missing mixin(s): mixinX")
+		})
+	})
+
+	when("#SupportsStack", func() {
+		it("matches a declared stack exactly", func() {
+			bp := BuildpackDescriptor{
+				Stacks: []Stack{{ID: "some.stack.id"}},
+			}
+
+			h.AssertTrue(t, bp.SupportsStack("some.stack.id"))
+			h.AssertFalse(t, bp.SupportsStack("other.stack.id"))
+		})
+
+		it("matches any declared stack of a multi-stack buildpack", func() {
+			bp := BuildpackDescriptor{
+				Stacks: []Stack{{ID: "some.stack.id"}, {ID: "other.stack.id"}},
+			}
+
+			h.AssertTrue(t, bp.SupportsStack("some.stack.id"))
+			h.AssertTrue(t, bp.SupportsStack("other.stack.id"))
+			h.AssertFalse(t, bp.SupportsStack("some.nonexistent.stack.id"))
+		})
+
+		it("matches every stack when the wildcard stack is declared", func() {
+			bp := BuildpackDescriptor{
+				Stacks: []Stack{{ID: "*"}},
+			}
+
+			h.AssertTrue(t, bp.SupportsStack("some.stack.id"))
+		})
 	})
 }
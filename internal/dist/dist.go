@@ -37,6 +37,22 @@ type BuildpackLayerInfo struct {
 	LayerDiffID string       `json:"layerDiffID"`
 }
 
+// BuildpackLayersForImage reads the image's BuildpackLayersLabel into a typed
+// mapping. Images without the label yield an empty mapping.
+func BuildpackLayersForImage(image Labeled) (BuildpackLayers, error) {
+	layers := BuildpackLayers{}
+	if _, err := GetLabel(image, BuildpackLayersLabel, &layers); err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
+// Get looks up the layer info for the buildpack with the given id and version.
+func (l BuildpackLayers) Get(id, version string) (BuildpackLayerInfo, bool) {
+	info, ok := l[id][version]
+	return info, ok
+}
+
 func AddBuildpackToLayersMD(layerMD BuildpackLayers, descriptor BuildpackDescriptor, diffID string) {
 	bpInfo := descriptor.Info
 	if _, ok := layerMD[bpInfo.ID]; !ok {
@@ -0,0 +1,103 @@
+package dist_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDist(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "testDist", testDist, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDist(t *testing.T, when spec.G, it spec.S) {
+	when("#BuildpackLayersForImage", func() {
+		var fakeImage *fakes.Image
+
+		it.Before(func() {
+			fakeImage = fakes.NewImage("some/image", "", nil)
+		})
+
+		it("parses the buildpack layers label", func() {
+			h.AssertNil(t, fakeImage.SetLabel(
+				"io.buildpacks.buildpack.layers",
+				`{
+  "some-buildpack": {
+    "0.0.1": {
+      "api": "0.2",
+      "stacks": [{"id": "some.stack.id", "mixins": ["mixinA"]}],
+      "layerDiffID": "sha256:some-diff-id"
+    }
+  },
+  "order-buildpack": {
+    "0.0.2": {
+      "api": "0.2",
+      "order": [{"group": [{"id": "some-buildpack", "version": "0.0.1", "optional": true}]}],
+      "layerDiffID": "sha256:order-diff-id"
+    }
+  }
+}`,
+			))
+
+			layers, err := dist.BuildpackLayersForImage(fakeImage)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(layers), 2)
+
+			info, ok := layers.Get("some-buildpack", "0.0.1")
+			h.AssertEq(t, ok, true)
+			h.AssertEq(t, info.API.String(), "0.2")
+			h.AssertEq(t, info.LayerDiffID, "sha256:some-diff-id")
+			h.AssertEq(t, len(info.Stacks), 1)
+			h.AssertEq(t, info.Stacks[0].ID, "some.stack.id")
+			h.AssertEq(t, len(info.Order), 0)
+
+			info, ok = layers.Get("order-buildpack", "0.0.2")
+			h.AssertEq(t, ok, true)
+			h.AssertEq(t, len(info.Stacks), 0)
+			h.AssertEq(t, len(info.Order), 1)
+			h.AssertEq(t, info.Order[0].Group[0].ID, "some-buildpack")
+			h.AssertEq(t, info.Order[0].Group[0].Version, "0.0.1")
+			h.AssertEq(t, info.Order[0].Group[0].Optional, true)
+		})
+
+		it("returns an empty mapping when the label is missing", func() {
+			layers, err := dist.BuildpackLayersForImage(fakeImage)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(layers), 0)
+
+			_, ok := layers.Get("some-buildpack", "0.0.1")
+			h.AssertEq(t, ok, false)
+		})
+
+		it("errors when the label is malformed", func() {
+			h.AssertNil(t, fakeImage.SetLabel("io.buildpacks.buildpack.layers", "{"))
+
+			_, err := dist.BuildpackLayersForImage(fakeImage)
+			h.AssertError(t, err, "unmarshalling label 'io.buildpacks.buildpack.layers'")
+		})
+	})
+
+	when("BuildpackLayers#Get", func() {
+		it("misses versions that are not present", func() {
+			layers := dist.BuildpackLayers{
+				"some-buildpack": {
+					"0.0.1": dist.BuildpackLayerInfo{LayerDiffID: "sha256:some-diff-id"},
+				},
+			}
+
+			_, ok := layers.Get("some-buildpack", "0.0.2")
+			h.AssertEq(t, ok, false)
+
+			_, ok = layers.Get("other-buildpack", "0.0.1")
+			h.AssertEq(t, ok, false)
+		})
+	})
+}
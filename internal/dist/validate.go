@@ -0,0 +1,107 @@
+package dist
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ValidateRootBlob checks that a buildpack blob is packageable before any image work
+// happens: buildpack.toml declares api, buildpack.id and buildpack.version, stacks or
+// an order is present, and stack buildpacks carry executable bin/detect and bin/build
+// entries. Every problem found is reported in a single error.
+func ValidateRootBlob(blob Blob) error {
+	rc, err := blob.Open()
+	if err != nil {
+		return errors.Wrap(err, "open buildpack")
+	}
+	defer rc.Close()
+
+	var descriptor struct {
+		API    string        `toml:"api"`
+		Info   BuildpackInfo `toml:"buildpack"`
+		Stacks []Stack       `toml:"stacks"`
+		Order  Order         `toml:"order"`
+	}
+
+	foundDescriptor := false
+	binaries := map[string]bool{}
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to get next tar entry")
+		}
+
+		switch path.Clean(strings.TrimPrefix(header.Name, "./")) {
+		case "buildpack.toml":
+			foundDescriptor = true
+			buf, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return errors.Wrap(err, "reading buildpack.toml")
+			}
+			if _, err := toml.Decode(string(buf), &descriptor); err != nil {
+				return errors.Wrap(err, "decoding buildpack.toml")
+			}
+		case "bin/detect":
+			binaries["detect"] = anyExecBit(header.Mode)
+		case "bin/build":
+			binaries["build"] = anyExecBit(header.Mode)
+		}
+	}
+
+	if !foundDescriptor {
+		return errors.Errorf("%s is missing", style.Symbol("buildpack.toml"))
+	}
+
+	var problems []string
+
+	if descriptor.API == "" {
+		problems = append(problems, fmt.Sprintf("%s is required", style.Symbol("api")))
+	}
+
+	if descriptor.Info.ID == "" {
+		problems = append(problems, fmt.Sprintf("%s is required", style.Symbol("buildpack.id")))
+	}
+
+	if descriptor.Info.Version == "" {
+		problems = append(problems, fmt.Sprintf("%s is required", style.Symbol("buildpack.version")))
+	}
+
+	if len(descriptor.Order) == 0 && len(descriptor.Stacks) == 0 {
+		problems = append(problems, fmt.Sprintf(
+			"must have either %s or an %s defined",
+			style.Symbol("stacks"),
+			style.Symbol("order"),
+		))
+	}
+
+	if len(descriptor.Order) == 0 {
+		for _, bin := range []string{"detect", "build"} {
+			executable, found := binaries[bin]
+			if !found {
+				problems = append(problems, fmt.Sprintf("%s is missing", style.Symbol(path.Join("bin", bin))))
+			} else if !executable {
+				problems = append(problems, fmt.Sprintf("%s is not executable", style.Symbol(path.Join("bin", bin))))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package dist_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestValidate(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "validate", testValidate, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testValidate(t *testing.T, when spec.G, it spec.S) {
+	when("#ValidateRootBlob", func() {
+		stackToml := `
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`
+
+		orderToml := `
+api = "0.3"
+
+[buildpack]
+id = "bp.meta"
+version = "1.2.3"
+
+[[order]]
+[[order.group]]
+id = "bp.one"
+version = "1.2.3"
+`
+
+		for _, tc := range []struct {
+			desc     string
+			toml     string
+			omitToml bool
+			omitBins bool
+			binMode  int64
+			expected string
+		}{
+			{
+				desc:    "valid stack buildpack",
+				toml:    stackToml,
+				binMode: 0755,
+			},
+			{
+				desc:     "valid order buildpack without binaries",
+				toml:     orderToml,
+				omitBins: true,
+			},
+			{
+				desc:     "missing buildpack.toml",
+				omitToml: true,
+				omitBins: true,
+				expected: "'buildpack.toml' is missing",
+			},
+			{
+				desc: "missing api",
+				toml: `
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`,
+				binMode:  0755,
+				expected: "'api' is required",
+			},
+			{
+				desc: "missing id and version",
+				toml: `
+api = "0.3"
+
+[[stacks]]
+id = "some.stack.id"
+`,
+				binMode:  0755,
+				expected: "'buildpack.id' is required; 'buildpack.version' is required",
+			},
+			{
+				desc: "missing stacks and order",
+				toml: `
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+`,
+				binMode:  0755,
+				expected: "must have either 'stacks' or an 'order' defined",
+			},
+			{
+				desc:     "missing binaries",
+				toml:     stackToml,
+				omitBins: true,
+				expected: "'bin/detect' is missing; 'bin/build' is missing",
+			},
+			{
+				desc:     "non-executable binaries",
+				toml:     stackToml,
+				binMode:  0644,
+				expected: "'bin/detect' is not executable; 'bin/build' is not executable",
+			},
+		} {
+			tc := tc
+			it(tc.desc, func() {
+				blob := &readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						if !tc.omitToml {
+							tarBuilder.AddFile("buildpack.toml", 0644, time.Now(), []byte(tc.toml))
+						}
+						if !tc.omitBins {
+							tarBuilder.AddDir("bin", 0755, time.Now())
+							tarBuilder.AddFile("bin/detect", tc.binMode, time.Now(), []byte("detect-contents"))
+							tarBuilder.AddFile("bin/build", tc.binMode, time.Now(), []byte("build-contents"))
+						}
+						return tarBuilder.Reader()
+					},
+				}
+
+				err := dist.ValidateRootBlob(blob)
+
+				if tc.expected == "" {
+					h.AssertNil(t, err)
+				} else {
+					h.AssertError(t, err, tc.expected)
+				}
+			})
+		}
+	})
+}
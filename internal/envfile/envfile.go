@@ -0,0 +1,76 @@
+// Package envfile parses `.env`-style files of 'VAR=VALUE' pairs.
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Parse reads the file at path and returns its variables. See ParseLines for
+// the supported syntax.
+func Parse(path string) (map[string]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open %s", path)
+	}
+	return ParseLines(strings.Split(string(contents), "\n"))
+}
+
+// ParseLines parses `.env`-style lines. Each line holds one 'VAR=VALUE' pair,
+// with an optional 'export ' prefix. Values may be single- or double-quoted;
+// unquoted values end at the first ' #' comment marker. Blank lines and lines
+// starting with '#' are skipped. A bare 'VAR' without '=' takes its value from
+// the current environment.
+func ParseLines(lines []string) (map[string]string, error) {
+	env := map[string]string{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		arr := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(arr[0])
+		if key == "" {
+			return nil, errors.Errorf("invalid env file entry %s: missing variable name", style.Symbol(line))
+		}
+		if len(arr) == 1 {
+			env[key] = os.Getenv(key)
+			continue
+		}
+
+		value, err := parseValue(strings.TrimSpace(arr[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid env file entry %s", style.Symbol(line))
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// parseValue resolves a raw value to its literal contents, unwrapping quotes
+// and stripping trailing comments from unquoted values.
+func parseValue(raw string) (string, error) {
+	if strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`) {
+		quote := raw[:1]
+		end := strings.Index(raw[1:], quote)
+		if end < 0 {
+			return "", errors.New("unterminated quoted value")
+		}
+		rest := strings.TrimSpace(raw[end+2:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", errors.Errorf("unexpected characters after quoted value: %s", rest)
+		}
+		return raw[1 : end+1], nil
+	}
+	if idx := strings.Index(raw, " #"); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	return raw, nil
+}
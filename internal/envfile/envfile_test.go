@@ -0,0 +1,156 @@
+package envfile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/envfile"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestEnvFile(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "testEnvFile", testEnvFile, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testEnvFile(t *testing.T, when spec.G, it spec.S) {
+	when("#ParseLines", func() {
+		for _, tc := range []struct {
+			name     string
+			lines    []string
+			expected map[string]string
+		}{
+			{
+				name:     "plain pairs",
+				lines:    []string{"KEY=VALUE", "OTHER=1"},
+				expected: map[string]string{"KEY": "VALUE", "OTHER": "1"},
+			},
+			{
+				name:     "double-quoted value with spaces",
+				lines:    []string{`KEY="value with spaces"`},
+				expected: map[string]string{"KEY": "value with spaces"},
+			},
+			{
+				name:     "single-quoted value keeps hash",
+				lines:    []string{`KEY='value # not a comment'`},
+				expected: map[string]string{"KEY": "value # not a comment"},
+			},
+			{
+				name:     "trailing comment on unquoted value",
+				lines:    []string{"KEY=value # comment"},
+				expected: map[string]string{"KEY": "value"},
+			},
+			{
+				name:     "trailing comment after quoted value",
+				lines:    []string{`KEY="value" # comment`},
+				expected: map[string]string{"KEY": "value"},
+			},
+			{
+				name:     "blank lines and comment lines",
+				lines:    []string{"", "  ", "# a comment", "KEY=VALUE"},
+				expected: map[string]string{"KEY": "VALUE"},
+			},
+			{
+				name:     "export prefix",
+				lines:    []string{"export KEY=VALUE"},
+				expected: map[string]string{"KEY": "VALUE"},
+			},
+			{
+				name:     "hash inside unquoted value without space",
+				lines:    []string{"KEY=value#fragment"},
+				expected: map[string]string{"KEY": "value#fragment"},
+			},
+			{
+				name:     "empty value",
+				lines:    []string{"KEY="},
+				expected: map[string]string{"KEY": ""},
+			},
+		} {
+			tc := tc
+			it("parses "+tc.name, func() {
+				env, err := envfile.ParseLines(tc.lines)
+				h.AssertNil(t, err)
+				h.AssertEq(t, env, tc.expected)
+			})
+		}
+
+		when("a variable has no value", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.Setenv("ENVFILE_TEST_HOST_VAR", "from-host"))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Unsetenv("ENVFILE_TEST_HOST_VAR"))
+			})
+
+			it("takes the value from the current environment", func() {
+				env, err := envfile.ParseLines([]string{"ENVFILE_TEST_HOST_VAR"})
+				h.AssertNil(t, err)
+				h.AssertEq(t, env, map[string]string{"ENVFILE_TEST_HOST_VAR": "from-host"})
+			})
+		})
+
+		for _, tc := range []struct {
+			name     string
+			lines    []string
+			expected string
+		}{
+			{
+				name:     "an unterminated quote",
+				lines:    []string{`KEY="unterminated`},
+				expected: "unterminated quoted value",
+			},
+			{
+				name:     "trailing garbage after a quoted value",
+				lines:    []string{`KEY="value" garbage`},
+				expected: "unexpected characters after quoted value",
+			},
+			{
+				name:     "a missing variable name",
+				lines:    []string{"=VALUE"},
+				expected: "missing variable name",
+			},
+		} {
+			tc := tc
+			it("errors on "+tc.name, func() {
+				_, err := envfile.ParseLines(tc.lines)
+				h.AssertError(t, err, tc.expected)
+			})
+		}
+	})
+
+	when("#Parse", func() {
+		var tmpDir string
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "envfile")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("parses the file's variables", func() {
+			path := filepath.Join(tmpDir, "some.env")
+			h.AssertNil(t, ioutil.WriteFile(path, []byte("KEY=VALUE\nexport OTHER=\"quoted value\"\n"), 0666))
+
+			env, err := envfile.Parse(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, env, map[string]string{"KEY": "VALUE", "OTHER": "quoted value"})
+		})
+
+		it("errors when the file does not exist", func() {
+			_, err := envfile.Parse(filepath.Join(tmpDir, "missing.env"))
+			h.AssertError(t, err, "open")
+		})
+	})
+}
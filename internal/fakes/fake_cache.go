@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/buildpacks/pack/internal/cache"
+)
+
+type FakeCache struct {
+	ReturnForName string
+	ReturnForType cache.Type
+	ReturnForSize int64
+	ErrorForClear error
+
+	ClearCallCount int
+}
+
+func (f *FakeCache) Name() string {
+	return f.ReturnForName
+}
+
+func (f *FakeCache) Type() cache.Type {
+	return f.ReturnForType
+}
+
+func (f *FakeCache) Clear(ctx context.Context) error {
+	f.ClearCallCount++
+	return f.ErrorForClear
+}
+
+func (f *FakeCache) Size(ctx context.Context) (int64, error) {
+	return f.ReturnForSize, nil
+}
@@ -10,8 +10,8 @@ import (
 )
 
 type FetchArgs struct {
-	Daemon bool
-	Pull   bool
+	Daemon     bool
+	PullPolicy image.PullPolicy
 }
 
 type FakeImageFetcher struct {
@@ -28,16 +28,17 @@ func NewFakeImageFetcher() *FakeImageFetcher {
 	}
 }
 
-func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, daemon, pull bool) (imgutil.Image, error) {
-	f.FetchCalls[name] = &FetchArgs{Daemon: daemon, Pull: pull}
+func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error) {
+	f.FetchCalls[name] = &FetchArgs{Daemon: daemon, PullPolicy: pullPolicy}
 
 	ri, remoteFound := f.RemoteImages[name]
 
 	if daemon {
-		if remoteFound && pull {
+		li, localFound := f.LocalImages[name]
+		if remoteFound && (pullPolicy == image.PullAlways || (pullPolicy == image.PullIfNotPresent && !localFound)) {
 			f.LocalImages[name] = ri
+			li, localFound = ri, true
 		}
-		li, localFound := f.LocalImages[name]
 		if !localFound {
 			return nil, errors.Wrapf(image.ErrNotFound, "image '%s' does not exist on the daemon", name)
 		}
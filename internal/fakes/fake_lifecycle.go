@@ -7,10 +7,14 @@ import (
 )
 
 type FakeLifecycle struct {
-	Opts build.LifecycleOptions
+	Opts      build.LifecycleOptions
+	ExecuteFn func(opts build.LifecycleOptions) error // optional hook invoked with the received options
 }
 
 func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
 	f.Opts = opts
+	if f.ExecuteFn != nil {
+		return f.ExecuteFn(opts)
+	}
 	return nil
 }
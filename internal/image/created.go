@@ -0,0 +1,14 @@
+package image
+
+import (
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+)
+
+// SetCreated returns a copy of the image whose config records the given OCI
+// 'created' timestamp; the layers and their timestamps are left untouched.
+func SetCreated(img v1.Image, created time.Time) (v1.Image, error) {
+	return mutate.CreatedAt(img, v1.Time{Time: created})
+}
@@ -0,0 +1,45 @@
+package image_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCreated(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Created", testCreated, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCreated(t *testing.T, when spec.G, it spec.S) {
+	when("#SetCreated", func() {
+		it("records the given time in the image config", func() {
+			created := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+			img, err := image.SetCreated(empty.Image, created)
+			h.AssertNil(t, err)
+
+			config, err := img.ConfigFile()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Created.Time, created)
+		})
+
+		it("leaves the layers untouched", func() {
+			img, err := image.SetCreated(empty.Image, time.Now())
+			h.AssertNil(t, err)
+
+			layers, err := img.Layers()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(layers), 0)
+		})
+	})
+}
@@ -1,23 +1,114 @@
 package image
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/imgutil/remote"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+// Defaults for how transient registry errors are retried, see WithRetry.
+const (
+	DefaultRetryAttempts = 2
+	DefaultRetryBackoff  = 500 * time.Millisecond
 )
 
 type DefaultImageFactory struct {
-	dockerClient client.CommonAPIClient
-	keychain     authn.Keychain
+	dockerClient       client.CommonAPIClient
+	keychain           authn.Keychain
+	retryAttempts      int
+	retryBackoff       time.Duration
+	registryMirrors    map[string]string
+	insecureRegistries []string
+	transport          http.RoundTripper
+	logger             logging.Logger
+}
+
+type FactoryOption func(*DefaultImageFactory)
+
+// WithRetry overrides how many additional attempts remote image operations
+// make after a transient registry error, and the backoff before the first
+// retry, which doubles after each failure.
+func WithRetry(attempts int, backoff time.Duration) FactoryOption {
+	return func(f *DefaultImageFactory) {
+		f.retryAttempts = attempts
+		f.retryBackoff = backoff
+	}
+}
+
+// WithRegistryMirrors rewrites remote references through the given registry
+// mirror map (e.g. 'docker.io' -> 'registry.internal') before resolving them.
+func WithRegistryMirrors(mirrors map[string]string) FactoryOption {
+	return func(f *DefaultImageFactory) {
+		f.registryMirrors = mirrors
+	}
+}
+
+// WithInsecureRegistries treats the given registry hosts (in '<host>[:<port>]'
+// form) as insecure, talking to them over plain HTTP.
+func WithInsecureRegistries(registries []string) FactoryOption {
+	return func(f *DefaultImageFactory) {
+		f.insecureRegistries = registries
+	}
+}
+
+// WithProgressReporting reports per-layer progress for remote pulls and
+// pushes through the given logger. Quiet loggers suppress the output.
+func WithProgressReporting(logger logging.Logger) FactoryOption {
+	return func(f *DefaultImageFactory) {
+		f.logger = logger
+	}
+}
+
+// WithTransport overrides the http.RoundTripper remote registry operations
+// are made over, e.g. to inject tracing headers or a corporate CA bundle.
+// A nil transport keeps the current behavior.
+func WithTransport(transport http.RoundTripper) FactoryOption {
+	return func(f *DefaultImageFactory) {
+		f.transport = transport
+	}
+}
+
+func NewFactory(dockerClient client.CommonAPIClient, keychain authn.Keychain, opts ...FactoryOption) *DefaultImageFactory {
+	f := &DefaultImageFactory{
+		dockerClient:  dockerClient,
+		keychain:      keychain,
+		retryAttempts: DefaultRetryAttempts,
+		retryBackoff:  DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	// The wrappers compose over the supplied transport, or the process-wide
+	// default, which is read but never modified; everything stays scoped to
+	// this factory, so repeated factories cannot stack wrappers.
+	if f.logger != nil {
+		f.transport = NewProgressTransport(defaultedTransport(f.transport), f.logger)
+	}
+	if len(f.insecureRegistries) > 0 {
+		f.transport = NewInsecureTransport(defaultedTransport(f.transport), f.insecureRegistries)
+	}
+	return f
+}
+
+// Transport returns the transport remote registry operations are made over,
+// with the factory's progress and insecure-registry wrapping applied; nil
+// when nothing was configured and the process default applies.
+func (f *DefaultImageFactory) Transport() http.RoundTripper {
+	return f.transport
 }
 
-func NewFactory(dockerClient client.CommonAPIClient, keychain authn.Keychain) *DefaultImageFactory {
-	return &DefaultImageFactory{
-		dockerClient: dockerClient,
-		keychain:     keychain,
+func defaultedTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport != nil {
+		return transport
 	}
+	return http.DefaultTransport
 }
 
 func (f *DefaultImageFactory) NewImage(repoName string, daemon bool) (imgutil.Image, error) {
@@ -25,5 +116,37 @@ func (f *DefaultImageFactory) NewImage(repoName string, daemon bool) (imgutil.Im
 		return local.NewImage(repoName, f.dockerClient)
 	}
 
-	return remote.NewImage(repoName, f.keychain)
+	repoName, err := RewriteReference(repoName, f.registryMirrors)
+	if err != nil {
+		return nil, err
+	}
+
+	var img imgutil.Image
+	if err := RetryTransient(f.retryAttempts, f.retryBackoff, func() error {
+		var err error
+		img, err = remote.NewImage(repoName, f.keychain, remote.WithTransport(f.transport))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return &retryingImage{Image: img, attempts: f.retryAttempts, backoff: f.retryBackoff}, nil
+}
+
+// retryingImage wraps a remote image so pushes get the same retry treatment
+// as the initial manifest fetch.
+type retryingImage struct {
+	imgutil.Image
+	attempts int
+	backoff  time.Duration
+}
+
+// Save pushes the image, retrying transient registry failures. A retried push
+// resumes rather than restarts: the registry's blob existence checks skip the
+// layers that already uploaded, and layers living in another repository on
+// the same registry are cross-repo mounted instead of re-sent.
+func (r *retryingImage) Save(additionalNames ...string) error {
+	return RetryTransient(r.attempts, r.backoff, func() error {
+		return r.Image.Save(additionalNames...)
+	})
 }
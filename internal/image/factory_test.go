@@ -0,0 +1,397 @@
+package image_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestFactory(t *testing.T) {
+	spec.Run(t, "Factory", testFactory, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+type stubTransport struct {
+	calls int
+	last  *http.Request
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.calls++
+	s.last = req
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+	}, nil
+}
+
+func testFactory(t *testing.T, when spec.G, it spec.S) {
+	when("#WithTransport", func() {
+		it("routes the factory's registry traffic through the configured transport", func() {
+			transport := &stubTransport{}
+			factory := image.NewFactory(nil, authn.DefaultKeychain, image.WithTransport(transport))
+
+			img, err := factory.NewImage("registry.example.com/some/image:latest", false)
+			h.AssertNil(t, err)
+			h.AssertNil(t, img.Save())
+
+			h.AssertEq(t, transport.calls > 0, true)
+			h.AssertEq(t, transport.last.URL.Host, "registry.example.com")
+		})
+
+		it("composes with insecure registries", func() {
+			transport := &stubTransport{}
+			factory := image.NewFactory(nil, authn.DefaultKeychain,
+				image.WithTransport(transport),
+				image.WithInsecureRegistries([]string{"registry.internal:5000"}),
+			)
+
+			img, err := factory.NewImage("registry.internal:5000/some/image:latest", false)
+			h.AssertNil(t, err)
+			h.AssertNil(t, img.Save())
+
+			h.AssertEq(t, transport.calls > 0, true)
+			h.AssertEq(t, transport.last.URL.Scheme, "http")
+		})
+
+		it("never replaces the process-wide default transport", func() {
+			originalTransport := http.DefaultTransport
+			image.NewFactory(nil, authn.DefaultKeychain,
+				image.WithTransport(&stubTransport{}),
+				image.WithInsecureRegistries([]string{"registry.internal:5000"}),
+				image.WithProgressReporting(logging.NewLogWithWriters(ioutil.Discard, ioutil.Discard)),
+			)
+
+			h.AssertEq(t, http.DefaultTransport == originalTransport, true)
+		})
+	})
+
+	when("#Save", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			tmpDir   string
+		)
+
+		it.Before(func() {
+			registry = newFakeRegistry()
+			server = httptest.NewServer(registry)
+
+			var err error
+			tmpDir, err = ioutil.TempDir("", "pack.image.factory.test.")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("resumes an interrupted push, only uploading the missing layers", func() {
+			layer1 := filepath.Join(tmpDir, "layer-1.tar")
+			h.AssertNil(t, archive.CreateSingleFileTar(layer1, "/some-file", "some-contents"))
+			layer2 := filepath.Join(tmpDir, "layer-2.tar")
+			h.AssertNil(t, archive.CreateSingleFileTar(layer2, "/other-file", "other-contents"))
+
+			factory := image.NewFactory(nil, authn.DefaultKeychain, image.WithRetry(1, 0))
+			repoName := strings.TrimPrefix(server.URL, "http://") + "/some/image:latest"
+			img, err := factory.NewImage(repoName, false)
+			h.AssertNil(t, err)
+			h.AssertNil(t, img.AddLayer(layer1))
+			h.AssertNil(t, img.AddLayer(layer2))
+
+			registry.failNextCommit()
+
+			h.AssertNil(t, img.Save())
+
+			// two layers plus the config blob
+			h.AssertEq(t, len(registry.blobs), 3)
+			h.AssertEq(t, registry.manifestPuts, 1)
+			for digest, commits := range registry.commits {
+				if digest == registry.failedDigest {
+					h.AssertEq(t, commits, 2)
+				} else {
+					// uploaded by the first push and skipped on the retry
+					// via the registry's existence check
+					h.AssertEq(t, commits, 1)
+				}
+			}
+		})
+	})
+
+	when("#WithProgressReporting", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			tmpDir   string
+		)
+
+		it.Before(func() {
+			registry = newFakeRegistry()
+			server = httptest.NewServer(registry)
+
+			var err error
+			tmpDir, err = ioutil.TempDir("", "pack.image.factory.test.")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("reports each pushed layer once, even after repeated factories", func() {
+			var outBuf bytes.Buffer
+			logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+
+			// an earlier client's factory must not leak its reporter into this one
+			image.NewFactory(nil, authn.DefaultKeychain, image.WithProgressReporting(logger))
+			factory := image.NewFactory(nil, authn.DefaultKeychain, image.WithProgressReporting(logger))
+
+			layerTar := filepath.Join(tmpDir, "layer.tar")
+			h.AssertNil(t, archive.CreateSingleFileTar(layerTar, "/some-file", "some-contents"))
+
+			repoName := strings.TrimPrefix(server.URL, "http://") + "/some/image:latest"
+			img, err := factory.NewImage(repoName, false)
+			h.AssertNil(t, err)
+			h.AssertNil(t, img.AddLayer(layerTar))
+			h.AssertNil(t, img.Save())
+
+			// the layer and the config blob
+			h.AssertEq(t, strings.Count(outBuf.String(), "Pushed layer"), 2)
+		})
+	})
+
+	when("publishing an image that shares layers with another repository", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			tmpDir   string
+			host     string
+		)
+
+		it.Before(func() {
+			registry = newFakeRegistry()
+			server = httptest.NewServer(registry)
+			host = strings.TrimPrefix(server.URL, "http://")
+
+			var err error
+			tmpDir, err = ioutil.TempDir("", "pack.image.factory.test.")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		pushBaseImage := func() {
+			layerTar := filepath.Join(tmpDir, "base-layer.tar")
+			h.AssertNil(t, archive.CreateSingleFileTar(layerTar, "/some-file", "some-contents"))
+
+			factory := image.NewFactory(nil, authn.DefaultKeychain)
+			img, err := factory.NewImage(host+"/some/base:latest", false)
+			h.AssertNil(t, err)
+			h.AssertNil(t, img.AddLayer(layerTar))
+			h.AssertNil(t, img.Save())
+		}
+
+		baseLayerDigest := func() string {
+			var manifest struct {
+				Layers []struct {
+					Digest string `json:"digest"`
+				} `json:"layers"`
+			}
+			h.AssertNil(t, json.Unmarshal(registry.manifests["/v2/some/base/manifests/latest"].content, &manifest))
+			h.AssertEq(t, len(manifest.Layers), 1)
+			return manifest.Layers[0].Digest
+		}
+
+		publishBaseAs := func(newName string) {
+			fetcher := image.NewFetcher(logging.NewLogWithWriters(ioutil.Discard, ioutil.Discard), nil)
+			img, err := fetcher.Fetch(context.TODO(), host+"/some/base:latest", false, image.PullAlways)
+			h.AssertNil(t, err)
+
+			img.Rename(newName)
+			h.AssertNil(t, img.Save())
+		}
+
+		it("mounts the shared layers instead of re-uploading them", func() {
+			pushBaseImage()
+
+			publishBaseAs(host + "/some/app:latest")
+
+			layerDigest := baseLayerDigest()
+			h.AssertSliceContains(t, registry.mounts, "some/base@"+layerDigest)
+			// committed once, by the base push; the app push mounted it
+			h.AssertEq(t, registry.commits[layerDigest], 1)
+			_, ok := registry.manifests["/v2/some/app/manifests/latest"]
+			h.AssertTrue(t, ok)
+		})
+
+		it("falls back to uploading when the registry will not mount", func() {
+			pushBaseImage()
+			registry.refuseMountRequests()
+
+			publishBaseAs(host + "/some/app:latest")
+
+			layerDigest := baseLayerDigest()
+			h.AssertSliceContains(t, registry.mounts, "some/base@"+layerDigest)
+			// the mount attempt was declined, so the layer uploaded again
+			h.AssertEq(t, registry.commits[layerDigest], 2)
+		})
+	})
+}
+
+// fakeRegistry implements just enough of the registry protocol for
+// remote.Write and remote.Image: blob existence checks, monolithic uploads,
+// cross-repo mounts and manifest reads and writes.
+type fakeRegistry struct {
+	sync.Mutex
+	blobs        map[string][]byte        // keyed '<repo>@<digest>'
+	uploads      map[string][]byte        // in-flight content by upload location
+	uploadRepos  map[string]string        // upload location -> target repo
+	commits      map[string]int           // commit attempts by digest
+	manifests    map[string]manifestEntry // by request path
+	mounts       []string                 // requested mounts, as '<from>@<digest>'
+	manifestPuts int
+	nextUpload   int
+	failCommit   bool
+	refuseMounts bool
+	failedDigest string
+}
+
+type manifestEntry struct {
+	content     []byte
+	contentType string
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		blobs:       map[string][]byte{},
+		uploads:     map[string][]byte{},
+		uploadRepos: map[string]string{},
+		commits:     map[string]int{},
+		manifests:   map[string]manifestEntry{},
+	}
+}
+
+// failNextCommit makes the next blob commit fail with a 503, simulating a
+// push interrupted partway through.
+func (r *fakeRegistry) failNextCommit() {
+	r.Lock()
+	defer r.Unlock()
+	r.failCommit = true
+}
+
+// refuseMountRequests makes the registry ignore mount parameters, like
+// registries that don't support cross-repo mounting, so uploads must proceed.
+func (r *fakeRegistry) refuseMountRequests() {
+	r.Lock()
+	defer r.Unlock()
+	r.refuseMounts = true
+}
+
+// blobKey turns a '/v2/<repo>/blobs/<digest>' path into the '<repo>@<digest>'
+// key blobs are stored under.
+func blobKey(urlPath string) string {
+	digest := path.Base(urlPath)
+	repo := strings.TrimPrefix(strings.TrimSuffix(urlPath, "/blobs/"+digest), "/v2/")
+	return repo + "@" + digest
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	switch {
+	case req.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case req.Method == http.MethodHead && strings.Contains(req.URL.Path, "/blobs/"):
+		if _, ok := r.blobs[blobKey(req.URL.Path)]; ok {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/blobs/"):
+		content, ok := r.blobs[blobKey(req.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/blobs/uploads/"):
+		repo := strings.TrimPrefix(strings.TrimSuffix(req.URL.Path, "/blobs/uploads/"), "/v2/")
+		from, mount := req.URL.Query().Get("from"), req.URL.Query().Get("mount")
+		if from != "" && mount != "" {
+			r.mounts = append(r.mounts, from+"@"+mount)
+			if content, ok := r.blobs[from+"@"+mount]; ok && !r.refuseMounts {
+				r.blobs[repo+"@"+mount] = content
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+		r.nextUpload++
+		location := fmt.Sprintf("/v2/%s/blobs/uploads/%d", repo, r.nextUpload)
+		r.uploadRepos[location] = repo
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPatch && strings.Contains(req.URL.Path, "/blobs/uploads/"):
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		r.uploads[req.URL.Path] = content
+		w.Header().Set("Location", req.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/blobs/uploads/"):
+		digest := req.URL.Query().Get("digest")
+		r.commits[digest]++
+		if r.failCommit {
+			r.failCommit = false
+			r.failedDigest = digest
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		r.blobs[r.uploadRepos[req.URL.Path]+"@"+digest] = r.uploads[req.URL.Path]
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/manifests/"):
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		r.manifests[req.URL.Path] = manifestEntry{content: content, contentType: req.Header.Get("Content-Type")}
+		r.manifestPuts++
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/manifests/"):
+		manifest, ok := r.manifests[req.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", manifest.contentType)
+		w.Write(manifest.content)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/buildpacks/imgutil"
@@ -23,25 +24,73 @@ import (
 )
 
 type Fetcher struct {
-	docker client.CommonAPIClient
-	logger logging.Logger
+	docker          client.CommonAPIClient
+	logger          logging.Logger
+	registryMirrors map[string]string
+	keychain        authn.Keychain
+	transport       http.RoundTripper
 }
 
-func NewFetcher(logger logging.Logger, docker client.CommonAPIClient) *Fetcher {
-	return &Fetcher{
-		logger: logger,
-		docker: docker,
+type FetcherOption func(*Fetcher)
+
+// WithMirrors routes remote fetches through the same registry mirror map the
+// image factory accepts, see WithRegistryMirrors.
+func WithMirrors(mirrors map[string]string) FetcherOption {
+	return func(f *Fetcher) {
+		f.registryMirrors = mirrors
+	}
+}
+
+// WithKeychain resolves registry credentials through the given keychain
+// instead of the default docker config file.
+func WithKeychain(keychain authn.Keychain) FetcherOption {
+	return func(f *Fetcher) {
+		f.keychain = keychain
+	}
+}
+
+// WithFetchTransport routes the fetcher's registry requests over the given
+// http.RoundTripper, e.g. a factory's Transport so its insecure-registry
+// handling applies to fetches as well. A nil transport keeps the process
+// default.
+func WithFetchTransport(transport http.RoundTripper) FetcherOption {
+	return func(f *Fetcher) {
+		f.transport = transport
+	}
+}
+
+func NewFetcher(logger logging.Logger, docker client.CommonAPIClient, opts ...FetcherOption) *Fetcher {
+	f := &Fetcher{
+		logger:   logger,
+		docker:   docker,
+		keychain: authn.DefaultKeychain,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 var ErrNotFound = errors.New("not found")
 
-func (f *Fetcher) Fetch(ctx context.Context, name string, daemon, pull bool) (image imgutil.Image, err error) {
-	if daemon && !pull {
+func (f *Fetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy PullPolicy) (image imgutil.Image, err error) {
+	if daemon && pullPolicy == PullNever {
 		return f.fetchDaemonImage(name)
 	}
 
-	image, err = remote.NewImage(name, authn.DefaultKeychain, remote.FromBaseImage(name))
+	if daemon && pullPolicy == PullIfNotPresent {
+		img, err := f.fetchDaemonImage(name)
+		if err == nil || errors.Cause(err) != ErrNotFound {
+			return img, err
+		}
+	}
+
+	remoteName, err := RewriteReference(name, f.registryMirrors)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err = remote.NewImage(remoteName, f.keychain, remote.WithTransport(f.transport), remote.FromBaseImage(remoteName))
 	if err != nil {
 		return nil, err
 	}
@@ -50,19 +99,29 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, daemon, pull bool) (im
 
 	if daemon {
 		if remoteFound {
-			f.logger.Debugf("Pulling image %s", style.Symbol(name))
-			if err := f.pullImage(ctx, name); err != nil {
+			f.logger.Debugf("Pulling image %s", style.Symbol(remoteName))
+			if err := f.pullImage(ctx, remoteName); err != nil {
 				return nil, err
 			}
+			if remoteName != name {
+				// retag the mirrored pull so the rest of the build sees the original name
+				if err := f.docker.ImageTag(ctx, remoteName, name); err != nil {
+					return nil, err
+				}
+			}
 		}
 		return f.fetchDaemonImage(name)
 	}
 
 	if !remoteFound {
-		return nil, errors.Wrapf(ErrNotFound, "image %s does not exist in registry", style.Symbol(name))
+		return nil, errors.Wrapf(ErrNotFound, "image %s does not exist in registry", style.Symbol(remoteName))
 	}
 
-	return image, nil
+	// Publishing an image built on a fetched base gets the same retry and
+	// resume treatment as factory-created images; base layers that already
+	// exist on the target registry are cross-repo mounted rather than
+	// re-uploaded.
+	return &retryingImage{Image: image, attempts: DefaultRetryAttempts, backoff: DefaultRetryBackoff}, nil
 }
 
 func (f *Fetcher) fetchDaemonImage(name string) (imgutil.Image, error) {
@@ -78,7 +137,7 @@ func (f *Fetcher) fetchDaemonImage(name string) (imgutil.Image, error) {
 }
 
 func (f *Fetcher) pullImage(ctx context.Context, imageID string) error {
-	regAuth, err := registryAuth(imageID)
+	regAuth, err := registryAuth(f.keychain, imageID)
 	if err != nil {
 		return err
 	}
@@ -114,8 +173,8 @@ func isTerminal(w io.Writer) (uintptr, bool) {
 	return 0, false
 }
 
-func registryAuth(ref string) (string, error) {
-	_, a, err := auth.ReferenceForRepoName(authn.DefaultKeychain, ref)
+func registryAuth(keychain authn.Keychain, ref string) (string, error) {
+	_, a, err := auth.ReferenceForRepoName(keychain, ref)
 	if err != nil {
 		return "", errors.Wrapf(err, "resolve auth for ref %s", ref)
 	}
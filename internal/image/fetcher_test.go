@@ -69,7 +69,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("returns the remote image", func() {
-					img, err := fetcher.Fetch(context.TODO(), repoName, false, false)
+					img, err := fetcher.Fetch(context.TODO(), repoName, false, image.PullAlways)
 					h.AssertNil(t, err)
 
 					label, err := img.Label("repo_name")
@@ -80,14 +80,14 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 			when("there is no remote image", func() {
 				it("returns an error", func() {
-					_, err := fetcher.Fetch(context.TODO(), repoName, false, false)
+					_, err := fetcher.Fetch(context.TODO(), repoName, false, image.PullAlways)
 					h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist in registry", repoName))
 				})
 			})
 		})
 
 		when("daemon is true", func() {
-			when("pull is false", func() {
+			when("pull policy is never", func() {
 				when("there is a local image", func() {
 					it.Before(func() {
 						// Make sure the repoName is not a valid remote repo.
@@ -108,7 +108,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("returns the local image", func() {
-						img, err := fetcher.Fetch(context.TODO(), repoName, true, false)
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullNever)
 						h.AssertNil(t, err)
 
 						label, err := img.Label("repo_name")
@@ -119,13 +119,66 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 				when("there is no local image", func() {
 					it("returns an error", func() {
-						_, err := fetcher.Fetch(context.TODO(), repoName, true, false)
+						_, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullNever)
 						h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist on the daemon", repoName))
 					})
 				})
 			})
 
-			when("pull is true", func() {
+			when("pull policy is if-not-present", func() {
+				when("there is a local image", func() {
+					it.Before(func() {
+						h.CreateImage(
+							t,
+							docker,
+							repoName,
+							"FROM scratch\nLABEL repo_name="+repoName,
+						)
+					})
+
+					it.After(func() {
+						h.DockerRmi(docker, repoName)
+					})
+
+					it("returns the local image without pulling", func() {
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullIfNotPresent)
+						h.AssertNil(t, err)
+
+						label, err := img.Label("repo_name")
+						h.AssertNil(t, err)
+						h.AssertEq(t, label, repoName)
+					})
+				})
+
+				when("there is no local image", func() {
+					when("there is a remote image", func() {
+						it.Before(func() {
+							h.CreateImageOnRemote(
+								t,
+								docker,
+								registryConfig,
+								repo,
+								"FROM scratch\nLABEL repo_name="+repoName,
+							)
+						})
+
+						it.After(func() {
+							h.DockerRmi(docker, repoName)
+						})
+
+						it("pulls the image and returns the local copy", func() {
+							img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullIfNotPresent)
+							h.AssertNil(t, err)
+
+							label, err := img.Label("repo_name")
+							h.AssertNil(t, err)
+							h.AssertEq(t, label, repoName)
+						})
+					})
+				})
+			})
+
+			when("pull policy is always", func() {
 				when("there is a remote image", func() {
 					it.Before(func() {
 						h.CreateImageOnRemote(
@@ -142,7 +195,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("pull the image and return the local copy", func() {
-						img, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 						h.AssertNil(t, err)
 
 						label, err := img.Label("repo_name")
@@ -167,7 +220,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 						})
 
 						it("returns the local image", func() {
-							img, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+							img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 							h.AssertNil(t, err)
 
 							label, err := img.Label("repo_name")
@@ -178,7 +231,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 					when("there is no local image", func() {
 						it("returns an error", func() {
-							_, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+							_, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 							h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist on the daemon", repoName))
 						})
 					})
@@ -187,3 +240,30 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 }
+
+// The transport tests need no daemon or local registry, so they run as their
+// own suite.
+func TestFetcherTransport(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "FetcherTransport", testFetcherTransport, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testFetcherTransport(t *testing.T, when spec.G, it spec.S) {
+	when("#WithFetchTransport", func() {
+		it("sends insecure-registry fetches over plain HTTP", func() {
+			transport := &stubTransport{}
+			fetcher := image.NewFetcher(
+				logging.NewLogWithWriters(ioutil.Discard, ioutil.Discard),
+				nil,
+				image.WithFetchTransport(image.NewInsecureTransport(transport, []string{"registry.internal:5000"})),
+			)
+
+			_, err := fetcher.Fetch(context.TODO(), "registry.internal:5000/some/image:latest", false, image.PullAlways)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, transport.calls > 0, true)
+			h.AssertEq(t, transport.last.URL.Scheme, "http")
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package image
+
+import (
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+)
+
+// IndexEntry pairs a platform-specific image with the platform it was built
+// for, for inclusion in a manifest list.
+type IndexEntry struct {
+	Image    mutate.Appendable
+	Platform Platform
+}
+
+// NewManifestList composes an image index referencing each entry's image,
+// annotated with its platform, suitable for pushing under a single tag.
+func NewManifestList(entries []IndexEntry) v1.ImageIndex {
+	adds := make([]mutate.IndexAddendum, 0, len(entries))
+	for _, entry := range entries {
+		adds = append(adds, mutate.IndexAddendum{
+			Add: entry.Image,
+			Descriptor: v1.Descriptor{
+				Platform: &v1.Platform{
+					OS:           entry.Platform.OS,
+					Architecture: entry.Platform.Arch,
+					Variant:      entry.Platform.Variant,
+				},
+			},
+		})
+	}
+	return mutate.AppendManifests(empty.Index, adds...)
+}
@@ -0,0 +1,74 @@
+package image_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestIndex(t *testing.T) {
+	spec.Run(t, "Index", testIndex, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testIndex(t *testing.T, when spec.G, it spec.S) {
+	when("#NewManifestList", func() {
+		it("references one image per requested platform", func() {
+			index := image.NewManifestList([]image.IndexEntry{
+				{
+					Image:    fakeAppendable{digest: v1.Hash{Algorithm: "sha256", Hex: "aaaa"}},
+					Platform: image.Platform{OS: "linux", Arch: "amd64"},
+				},
+				{
+					Image:    fakeAppendable{digest: v1.Hash{Algorithm: "sha256", Hex: "bbbb"}},
+					Platform: image.Platform{OS: "linux", Arch: "arm", Variant: "v7"},
+				},
+			})
+
+			manifest, err := index.IndexManifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Manifests), 2)
+
+			h.AssertEq(t, manifest.Manifests[0].Digest.Hex, "aaaa")
+			h.AssertEq(t, *manifest.Manifests[0].Platform, v1.Platform{OS: "linux", Architecture: "amd64"})
+
+			h.AssertEq(t, manifest.Manifests[1].Digest.Hex, "bbbb")
+			h.AssertEq(t, *manifest.Manifests[1].Platform, v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+		})
+
+		it("keeps each image's own media type and size", func() {
+			index := image.NewManifestList([]image.IndexEntry{
+				{
+					Image:    fakeAppendable{digest: v1.Hash{Algorithm: "sha256", Hex: "aaaa"}},
+					Platform: image.Platform{OS: "linux", Arch: "amd64"},
+				},
+			})
+
+			manifest, err := index.IndexManifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, manifest.Manifests[0].MediaType, types.DockerManifestSchema2)
+			h.AssertEq(t, manifest.Manifests[0].Size, int64(100))
+		})
+	})
+}
+
+type fakeAppendable struct {
+	digest v1.Hash
+}
+
+func (f fakeAppendable) MediaType() (types.MediaType, error) {
+	return types.DockerManifestSchema2, nil
+}
+
+func (f fakeAppendable) Digest() (v1.Hash, error) {
+	return f.digest, nil
+}
+
+func (f fakeAppendable) Size() (int64, error) {
+	return 100, nil
+}
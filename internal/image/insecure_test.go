@@ -0,0 +1,76 @@
+package image_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestInsecure(t *testing.T) {
+	spec.Run(t, "Insecure", testInsecure, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+type recordingRoundTripper struct {
+	req *http.Request
+}
+
+func (r *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.req = req
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func testInsecure(t *testing.T, when spec.G, it spec.S) {
+	when("#NewInsecureTransport", func() {
+		var (
+			inner     *recordingRoundTripper
+			transport http.RoundTripper
+		)
+
+		it.Before(func() {
+			inner = &recordingRoundTripper{}
+			transport = image.NewInsecureTransport(inner, []string{"registry.internal:5000"})
+		})
+
+		it("downgrades requests to listed hosts to HTTP", func() {
+			req, err := http.NewRequest("GET", "https://registry.internal:5000/v2/", nil)
+			h.AssertNil(t, err)
+
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, inner.req.URL.Scheme, "http")
+			h.AssertEq(t, inner.req.URL.Host, "registry.internal:5000")
+		})
+
+		it("does not mutate the original request", func() {
+			req, err := http.NewRequest("GET", "https://registry.internal:5000/v2/", nil)
+			h.AssertNil(t, err)
+
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, req.URL.Scheme, "https")
+		})
+
+		it("leaves other hosts on HTTPS", func() {
+			req, err := http.NewRequest("GET", "https://index.docker.io/v2/", nil)
+			h.AssertNil(t, err)
+
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, inner.req.URL.Scheme, "https")
+		})
+
+		it("leaves requests already on HTTP alone", func() {
+			req, err := http.NewRequest("GET", "http://registry.internal:5000/v2/", nil)
+			h.AssertNil(t, err)
+
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, inner.req.URL.Scheme, "http")
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package image
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"regexp"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/credentials"
+	dockertypes "github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// staticKeychain resolves credentials for a fixed set of registries, deferring
+// every other registry to a fallback keychain.
+type staticKeychain struct {
+	auths    map[string]*authn.AuthConfig
+	fallback authn.Keychain
+}
+
+// NewStaticKeychain parses encodedAuth, a base64-encoded JSON map of
+// 'registry' to 'authorization header' (e.g. {"gcr.io": "Bearer asdf="}), and
+// returns a keychain answering for those registries while deferring every
+// other registry to fallback. It mirrors the CNB_REGISTRY_AUTH format the
+// lifecycle accepts, for environments without a docker config file.
+func NewStaticKeychain(encodedAuth string, fallback authn.Keychain) (authn.Keychain, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encodedAuth)
+	if err != nil {
+		return nil, errors.Wrap(err, "registry auth must be base64 encoded")
+	}
+
+	headers := map[string]string{}
+	if err := json.Unmarshal(decoded, &headers); err != nil {
+		return nil, errors.Wrap(err, "parsing registry auth as a JSON map of registry to auth header")
+	}
+
+	auths := map[string]*authn.AuthConfig{}
+	for registry, header := range headers {
+		config, err := authHeaderToConfig(header)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing auth header for registry %s", style.Symbol(registry))
+		}
+		auths[registry] = config
+	}
+
+	return &staticKeychain{auths: auths, fallback: fallback}, nil
+}
+
+func (k *staticKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	if config, ok := k.auths[resource.RegistryStr()]; ok {
+		return authn.FromConfig(*config), nil
+	}
+	return k.fallback.Resolve(resource)
+}
+
+// describedKeychain wraps another keychain so resolution failures name the
+// registry and, when one is configured, the credential helper involved,
+// instead of surfacing the bare helper exec error.
+type describedKeychain struct {
+	keychain authn.Keychain
+}
+
+// NewDescribedKeychain wraps keychain so credential resolution failures
+// report which registry, and which credential helper if any, produced them.
+func NewDescribedKeychain(keychain authn.Keychain) authn.Keychain {
+	return &describedKeychain{keychain: keychain}
+}
+
+func (k *describedKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	authenticator, err := k.keychain.Resolve(resource)
+	if err != nil {
+		registry := resource.RegistryStr()
+		if helper := credentialHelperFor(registry); helper != "" {
+			return nil, errors.Wrapf(err,
+				"resolving credentials for registry %s with credential helper %s",
+				style.Symbol(registry), style.Symbol("docker-credential-"+helper),
+			)
+		}
+		return nil, errors.Wrapf(err, "resolving credentials for registry %s", style.Symbol(registry))
+	}
+	return authenticator, nil
+}
+
+// credentialHelperFor reports the credential helper the docker config file
+// configures for the registry, or an empty string when credentials come from
+// the config file itself.
+func credentialHelperFor(registry string) string {
+	cf, err := config.Load(os.Getenv("DOCKER_CONFIG"))
+	if err != nil {
+		return ""
+	}
+	if helper, ok := cf.CredentialHelpers[registry]; ok {
+		return helper
+	}
+	return cf.CredentialsStore
+}
+
+// configFileKeychain resolves credentials from the docker config file only,
+// never invoking configured credential helpers.
+type configFileKeychain struct{}
+
+// NewConfigFileKeychain returns a keychain that reads credentials stored
+// directly in the docker config file, ignoring any configured credential
+// helpers.
+func NewConfigFileKeychain() authn.Keychain {
+	return &configFileKeychain{}
+}
+
+func (k *configFileKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	cf, err := config.Load(os.Getenv("DOCKER_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+
+	key := resource.RegistryStr()
+	if key == name.DefaultRegistry {
+		key = "https://" + name.DefaultRegistry + "/v1/"
+	}
+
+	cfg, err := credentials.NewFileStore(cf).Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg == (dockertypes.AuthConfig{}) {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+		Auth:          cfg.Auth,
+		IdentityToken: cfg.IdentityToken,
+		RegistryToken: cfg.RegistryToken,
+	}), nil
+}
+
+var (
+	basicAuthRegExp  = regexp.MustCompile("(?i)^basic (.*)$")
+	bearerAuthRegExp = regexp.MustCompile("(?i)^bearer (.*)$")
+)
+
+func authHeaderToConfig(header string) (*authn.AuthConfig, error) {
+	if matches := basicAuthRegExp.FindStringSubmatch(header); matches != nil {
+		return &authn.AuthConfig{Auth: matches[1]}, nil
+	}
+
+	if matches := bearerAuthRegExp.FindStringSubmatch(header); matches != nil {
+		return &authn.AuthConfig{RegistryToken: matches[1]}, nil
+	}
+
+	return nil, errors.Errorf("unknown auth type in header %s: must start with 'Basic' or 'Bearer'", style.Symbol(header))
+}
@@ -0,0 +1,157 @@
+package image_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestKeychain(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Keychain", testKeychain, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+type fakeResource struct {
+	registry string
+}
+
+func (r fakeResource) String() string      { return r.registry + "/some/image" }
+func (r fakeResource) RegistryStr() string { return r.registry }
+
+type fakeKeychain struct {
+	auth authn.Authenticator
+	err  error
+}
+
+func (k *fakeKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.auth, k.err
+}
+
+func testKeychain(t *testing.T, when spec.G, it spec.S) {
+	when("#NewStaticKeychain", func() {
+		var fallback *fakeKeychain
+
+		it.Before(func() {
+			fallback = &fakeKeychain{auth: authn.FromConfig(authn.AuthConfig{Auth: "fallback-auth"})}
+		})
+
+		encode := func(raw string) string {
+			return base64.StdEncoding.EncodeToString([]byte(raw))
+		}
+
+		it("resolves registries from the encoded map before the fallback", func() {
+			keychain, err := image.NewStaticKeychain(encode(`{"gcr.io": "Basic asdf=", "docker.io": "Bearer qwerty="}`), fallback)
+			h.AssertNil(t, err)
+
+			auth, err := keychain.Resolve(fakeResource{registry: "gcr.io"})
+			h.AssertNil(t, err)
+			config, err := auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Auth, "asdf=")
+
+			auth, err = keychain.Resolve(fakeResource{registry: "docker.io"})
+			h.AssertNil(t, err)
+			config, err = auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.RegistryToken, "qwerty=")
+		})
+
+		it("falls back for registries absent from the map", func() {
+			keychain, err := image.NewStaticKeychain(encode(`{"gcr.io": "Basic asdf="}`), fallback)
+			h.AssertNil(t, err)
+
+			auth, err := keychain.Resolve(fakeResource{registry: "registry.example.com"})
+			h.AssertNil(t, err)
+			config, err := auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Auth, "fallback-auth")
+		})
+
+		it("errors when the value is not base64", func() {
+			_, err := image.NewStaticKeychain(`{"gcr.io": "Basic asdf="}`, fallback)
+			h.AssertError(t, err, "registry auth must be base64 encoded")
+		})
+
+		it("errors when the decoded value is not a JSON map", func() {
+			_, err := image.NewStaticKeychain(encode(`["gcr.io"]`), fallback)
+			h.AssertError(t, err, "parsing registry auth as a JSON map")
+		})
+
+		it("errors when an auth header has an unknown type", func() {
+			_, err := image.NewStaticKeychain(encode(`{"gcr.io": "token asdf="}`), fallback)
+			h.AssertError(t, err, "parsing auth header for registry 'gcr.io'")
+			h.AssertError(t, err, "must start with 'Basic' or 'Bearer'")
+		})
+	})
+
+	when("#NewDescribedKeychain", func() {
+		it("names the registry when resolution fails", func() {
+			erroring := &fakeKeychain{err: errors.New(`exec: "docker-credential-gcloud": executable file not found in $PATH`)}
+			keychain := image.NewDescribedKeychain(erroring)
+
+			_, err := keychain.Resolve(fakeResource{registry: "gcr.io"})
+			h.AssertError(t, err, "resolving credentials for registry 'gcr.io'")
+			h.AssertError(t, err, "docker-credential-gcloud")
+		})
+
+		it("passes successful resolutions through", func() {
+			inner := &fakeKeychain{auth: authn.FromConfig(authn.AuthConfig{Auth: "some-auth"})}
+			keychain := image.NewDescribedKeychain(inner)
+
+			auth, err := keychain.Resolve(fakeResource{registry: "gcr.io"})
+			h.AssertNil(t, err)
+			config, err := auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Auth, "some-auth")
+		})
+	})
+
+	when("#NewConfigFileKeychain", func() {
+		var configDir string
+
+		it.Before(func() {
+			var err error
+			configDir, err = ioutil.TempDir("", "docker-config")
+			h.AssertNil(t, err)
+			h.AssertNil(t, ioutil.WriteFile(
+				filepath.Join(configDir, "config.json"),
+				[]byte(`{"auths": {"gcr.io": {"auth": "`+base64.StdEncoding.EncodeToString([]byte("some-user:some-pass"))+`"}}, "credsStore": "missing-helper"}`),
+				0666,
+			))
+			os.Setenv("DOCKER_CONFIG", configDir)
+		})
+
+		it.After(func() {
+			os.Unsetenv("DOCKER_CONFIG")
+			h.AssertNil(t, os.RemoveAll(configDir))
+		})
+
+		it("reads the config file without invoking the configured helper", func() {
+			auth, err := image.NewConfigFileKeychain().Resolve(fakeResource{registry: "gcr.io"})
+			h.AssertNil(t, err)
+			config, err := auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Username, "some-user")
+			h.AssertEq(t, config.Password, "some-pass")
+		})
+
+		it("resolves anonymously for registries without a stored entry", func() {
+			auth, err := image.NewConfigFileKeychain().Resolve(fakeResource{registry: "registry.example.com"})
+			h.AssertNil(t, err)
+			h.AssertSameInstance(t, auth, authn.Anonymous)
+		})
+	})
+}
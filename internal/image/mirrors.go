@@ -0,0 +1,40 @@
+package image
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// RewriteReference maps ref's registry through mirrors, so registry traffic
+// can be routed through a pull-through mirror (e.g. 'docker.io' ->
+// 'registry.internal'). References whose registry has no mirror configured
+// are returned unchanged. Docker Hub may be keyed as either 'docker.io' or
+// 'index.docker.io'.
+func RewriteReference(ref string, mirrors map[string]string) (string, error) {
+	if len(mirrors) == 0 {
+		return ref, nil
+	}
+
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	registry := parsed.Context().RegistryStr()
+	mirror, ok := mirrors[registry]
+	if !ok && registry == name.DefaultRegistry {
+		mirror, ok = mirrors["docker.io"]
+	}
+	if !ok {
+		return ref, nil
+	}
+
+	switch r := parsed.(type) {
+	case name.Tag:
+		return fmt.Sprintf("%s/%s:%s", mirror, r.Context().RepositoryStr(), r.TagStr()), nil
+	case name.Digest:
+		return fmt.Sprintf("%s/%s@%s", mirror, r.Context().RepositoryStr(), r.DigestStr()), nil
+	}
+	return ref, nil
+}
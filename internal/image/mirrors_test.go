@@ -0,0 +1,59 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMirrors(t *testing.T) {
+	spec.Run(t, "Mirrors", testMirrors, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMirrors(t *testing.T, when spec.G, it spec.S) {
+	when("#RewriteReference", func() {
+		mirrors := map[string]string{
+			"docker.io":            "registry.internal",
+			"another.registry.com": "other.internal",
+		}
+
+		it("rewrites Docker Hub references", func() {
+			ref, err := image.RewriteReference("docker.io/library/x", mirrors)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref, "registry.internal/library/x:latest")
+		})
+
+		it("rewrites bare references, which default to Docker Hub", func() {
+			ref, err := image.RewriteReference("some/image:some-tag", mirrors)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref, "registry.internal/some/image:some-tag")
+		})
+
+		it("preserves digests", func() {
+			ref, err := image.RewriteReference("another.registry.com/some/image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", mirrors)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref, "other.internal/some/image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+		})
+
+		it("leaves references for unmirrored registries untouched", func() {
+			ref, err := image.RewriteReference("registry.example.com/some/image:some-tag", mirrors)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref, "registry.example.com/some/image:some-tag")
+		})
+
+		it("is a no-op without mirrors", func() {
+			ref, err := image.RewriteReference("docker.io/library/x", nil)
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref, "docker.io/library/x")
+		})
+
+		it("errors for an unparsable reference", func() {
+			_, err := image.RewriteReference("some/image::", mirrors)
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package image
+
+import (
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Platform identifies a single manifest within a multi-arch manifest list.
+type Platform struct {
+	OS      string
+	Arch    string
+	Variant string
+}
+
+// ParsePlatform parses a '<os>/<arch>[/<variant>]' value such as 'linux/arm64'.
+func ParsePlatform(value string) (Platform, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, errors.Errorf("invalid platform %s: must be in the form '<os>/<arch>[/<variant>]'", style.Symbol(value))
+	}
+	platform := Platform{OS: parts[0], Arch: parts[1]}
+	if len(parts) == 3 {
+		platform.Variant = parts[2]
+	}
+	return platform, nil
+}
+
+func (p Platform) String() string {
+	value := p.OS + "/" + p.Arch
+	if p.Variant != "" {
+		value += "/" + p.Variant
+	}
+	return value
+}
+
+// matches returns true if desc describes this platform. A platform without a
+// variant matches any variant of the same OS and architecture.
+func (p Platform) matches(desc *v1.Platform) bool {
+	if desc == nil {
+		return false
+	}
+	if desc.OS != p.OS || desc.Architecture != p.Arch {
+		return false
+	}
+	return p.Variant == "" || desc.Variant == p.Variant
+}
+
+// SelectManifest returns the digest of the manifest in index matching the
+// requested platform. When no manifest matches, the error lists the platforms
+// the manifest list provides.
+func SelectManifest(index *v1.IndexManifest, platform Platform) (v1.Hash, error) {
+	var available []string
+	for _, manifest := range index.Manifests {
+		if platform.matches(manifest.Platform) {
+			return manifest.Digest, nil
+		}
+		if manifest.Platform != nil {
+			desc := Platform{OS: manifest.Platform.OS, Arch: manifest.Platform.Architecture, Variant: manifest.Platform.Variant}
+			available = append(available, style.Symbol(desc.String()))
+		}
+	}
+	return v1.Hash{}, errors.Errorf("manifest list has no manifest for platform %s: available platforms are %s", style.Symbol(platform.String()), strings.Join(available, ", "))
+}
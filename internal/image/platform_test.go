@@ -0,0 +1,88 @@
+package image_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPlatform(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Platform", testPlatform, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPlatform(t *testing.T, when spec.G, it spec.S) {
+	when("#ParsePlatform", func() {
+		it("parses '<os>/<arch>'", func() {
+			platform, err := image.ParsePlatform("linux/arm64")
+			h.AssertNil(t, err)
+			h.AssertEq(t, platform, image.Platform{OS: "linux", Arch: "arm64"})
+		})
+
+		it("parses '<os>/<arch>/<variant>'", func() {
+			platform, err := image.ParsePlatform("linux/arm/v7")
+			h.AssertNil(t, err)
+			h.AssertEq(t, platform, image.Platform{OS: "linux", Arch: "arm", Variant: "v7"})
+		})
+
+		it("rejects malformed values", func() {
+			for _, value := range []string{"linux", "linux/", "/arm64", "linux/arm/v7/extra", ""} {
+				_, err := image.ParsePlatform(value)
+				h.AssertError(t, err, "must be in the form '<os>/<arch>[/<variant>]'")
+			}
+		})
+	})
+
+	when("#SelectManifest", func() {
+		var index *v1.IndexManifest
+
+		it.Before(func() {
+			index = &v1.IndexManifest{
+				Manifests: []v1.Descriptor{
+					{
+						Digest:   v1.Hash{Algorithm: "sha256", Hex: "aaaa"},
+						Platform: &v1.Platform{OS: "linux", Architecture: "amd64"},
+					},
+					{
+						Digest:   v1.Hash{Algorithm: "sha256", Hex: "bbbb"},
+						Platform: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+					},
+					{
+						Digest:   v1.Hash{Algorithm: "sha256", Hex: "cccc"},
+						Platform: &v1.Platform{OS: "windows", Architecture: "amd64"},
+					},
+				},
+			}
+		})
+
+		it("selects the manifest matching the OS and architecture", func() {
+			digest, err := image.SelectManifest(index, image.Platform{OS: "windows", Arch: "amd64"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, digest.Hex, "cccc")
+		})
+
+		it("matches any variant when the requested platform has none", func() {
+			digest, err := image.SelectManifest(index, image.Platform{OS: "linux", Arch: "arm"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, digest.Hex, "bbbb")
+		})
+
+		it("requires the variant to match when one is requested", func() {
+			_, err := image.SelectManifest(index, image.Platform{OS: "linux", Arch: "arm", Variant: "v6"})
+			h.AssertError(t, err, "manifest list has no manifest for platform 'linux/arm/v6'")
+		})
+
+		it("lists the available platforms when nothing matches", func() {
+			_, err := image.SelectManifest(index, image.Platform{OS: "linux", Arch: "arm64"})
+			h.AssertError(t, err, "available platforms are 'linux/amd64', 'linux/arm/v7', 'windows/amd64'")
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+// progressTransport reports per-layer progress for registry blob traffic
+// through the logger, leaving every other request untouched. Quiet loggers
+// suppress the output along with the rest of the informational messages.
+type progressTransport struct {
+	inner   http.RoundTripper
+	logger  logging.Logger
+	uploads sync.Map // upload session path -> *int64 bytes sent
+}
+
+// NewProgressTransport wraps inner so registry blob pulls and pushes report
+// the completion of each layer, with its size, through logger.
+func NewProgressTransport(inner http.RoundTripper, logger logging.Logger) http.RoundTripper {
+	return &progressTransport{inner: inner, logger: logger}
+}
+
+func (t *progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/blobs/sha256:"):
+		return t.pullThrough(req)
+	case strings.Contains(req.URL.Path, "/blobs/uploads/"):
+		return t.pushThrough(req)
+	}
+	return t.inner.RoundTrip(req)
+}
+
+func (t *progressTransport) pullThrough(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	digest := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	resp.Body = &pullProgressBody{
+		ReadCloser: resp.Body,
+		done: func(read int64) {
+			t.logger.Infof("Pulled layer %s (%s)", style.Symbol(shortDigest(digest)), byteCount(read))
+		},
+	}
+	return resp, nil
+}
+
+func (t *progressTransport) pushThrough(req *http.Request) (*http.Response, error) {
+	// chunks of one upload arrive as separate requests to the same session
+	// path; accumulate their sizes so the final PUT can report the layer total
+	counter, _ := t.uploads.LoadOrStore(req.URL.Path, new(int64))
+	sent := counter.(*int64)
+
+	wrapped := req
+	if req.Body != nil {
+		wrapped = req.Clone(req.Context())
+		wrapped.Body = &countingBody{ReadCloser: req.Body, sent: sent}
+	}
+
+	resp, err := t.inner.RoundTrip(wrapped)
+	if err != nil {
+		return resp, err
+	}
+
+	// the request committing an upload names the layer digest
+	if digest := req.URL.Query().Get("digest"); digest != "" && resp.StatusCode < 300 {
+		t.uploads.Delete(req.URL.Path)
+		t.logger.Infof("Pushed layer %s (%s)", style.Symbol(shortDigest(digest)), byteCount(atomic.LoadInt64(sent)))
+	}
+	return resp, nil
+}
+
+// pullProgressBody invokes done with the number of bytes read once the
+// response body has been fully consumed.
+type pullProgressBody struct {
+	io.ReadCloser
+	read     int64
+	done     func(read int64)
+	reported bool
+}
+
+func (b *pullProgressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err == io.EOF && !b.reported {
+		b.reported = true
+		b.done(b.read)
+	}
+	return n, err
+}
+
+type countingBody struct {
+	io.ReadCloser
+	sent *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(b.sent, int64(n))
+	return n, err
+}
+
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return digest
+}
+
+func byteCount(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,134 @@
+package image_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProgress(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Progress", testProgress, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+// registryRoundTripper answers registry blob requests with canned bodies,
+// consuming any request body the way a real transport would.
+type registryRoundTripper struct {
+	blobs map[string][]byte
+}
+
+func (r *registryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		if _, err := ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	if req.Method == http.MethodGet {
+		digest := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(r.blobs[digest])),
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func testProgress(t *testing.T, when spec.G, it spec.S) {
+	when("#NewProgressTransport", func() {
+		var (
+			outBuf    bytes.Buffer
+			transport http.RoundTripper
+			inner     *registryRoundTripper
+		)
+
+		it.Before(func() {
+			outBuf.Reset()
+			inner = &registryRoundTripper{blobs: map[string][]byte{
+				"sha256:aaaa0000aaaa0000": bytes.Repeat([]byte("a"), 100),
+				"sha256:bbbb0000bbbb0000": bytes.Repeat([]byte("b"), 2048),
+			}}
+			transport = image.NewProgressTransport(inner, logging.NewLogWithWriters(&outBuf, &outBuf))
+		})
+
+		pull := func(digest string) {
+			req, err := http.NewRequest("GET", "https://registry.example.com/v2/some/image/blobs/"+digest, nil)
+			h.AssertNil(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			_, err = ioutil.ReadAll(resp.Body)
+			h.AssertNil(t, err)
+			h.AssertNil(t, resp.Body.Close())
+		}
+
+		it("reports each pulled layer with its size", func() {
+			pull("sha256:aaaa0000aaaa0000")
+			pull("sha256:bbbb0000bbbb0000")
+
+			h.AssertContains(t, outBuf.String(), "Pulled layer 'aaaa0000aaaa' (100 B)")
+			h.AssertContains(t, outBuf.String(), "Pulled layer 'bbbb0000bbbb' (2.0 KiB)")
+		})
+
+		it("does not report until the layer is fully read", func() {
+			req, err := http.NewRequest("GET", "https://registry.example.com/v2/some/image/blobs/sha256:aaaa0000aaaa0000", nil)
+			h.AssertNil(t, err)
+
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, outBuf.String(), "")
+		})
+
+		it("reports a chunked upload once it is committed", func() {
+			session := "https://registry.example.com/v2/some/image/blobs/uploads/some-uuid"
+
+			req, err := http.NewRequest("PATCH", session, bytes.NewReader(bytes.Repeat([]byte("c"), 512)))
+			h.AssertNil(t, err)
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertEq(t, outBuf.String(), "")
+
+			req, err = http.NewRequest("PUT", session+"?digest=sha256:cccc0000cccc0000", nil)
+			h.AssertNil(t, err)
+			_, err = transport.RoundTrip(req)
+			h.AssertNil(t, err)
+
+			h.AssertContains(t, outBuf.String(), "Pushed layer 'cccc0000cccc' (512 B)")
+		})
+
+		it("ignores requests that are not blob traffic", func() {
+			req, err := http.NewRequest("GET", "https://registry.example.com/v2/some/image/manifests/latest", nil)
+			h.AssertNil(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			h.AssertNil(t, err)
+			h.AssertNil(t, resp.Body.Close())
+			h.AssertEq(t, outBuf.String(), "")
+		})
+
+		it("stays silent when the logger is quiet", func() {
+			logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+			logger.WantQuiet(true)
+			transport = image.NewProgressTransport(inner, logger)
+
+			pull("sha256:aaaa0000aaaa0000")
+			h.AssertEq(t, outBuf.String(), "")
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package image
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PullPolicy defines how images are refreshed from the registry when fetching for the daemon.
+type PullPolicy int
+
+const (
+	// PullAlways pulls images, even if they are already present locally.
+	PullAlways PullPolicy = iota
+	// PullNever never pulls images; only locally present images are used.
+	PullNever
+	// PullIfNotPresent pulls images only when they are not already present locally.
+	PullIfNotPresent
+)
+
+// ParsePullPolicy converts a string to a PullPolicy. An empty string defaults to PullAlways.
+func ParsePullPolicy(policy string) (PullPolicy, error) {
+	switch policy {
+	case "always", "":
+		return PullAlways, nil
+	case "never":
+		return PullNever, nil
+	case "if-not-present":
+		return PullIfNotPresent, nil
+	}
+	return PullAlways, errors.Errorf("invalid pull policy %s: accepted values are 'always', 'never' and 'if-not-present'", style.Symbol(policy))
+}
+
+func (p PullPolicy) String() string {
+	switch p {
+	case PullNever:
+		return "never"
+	case PullIfNotPresent:
+		return "if-not-present"
+	}
+	return "always"
+}
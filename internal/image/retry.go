@@ -0,0 +1,54 @@
+package image
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+)
+
+// RetryTransient runs op, retrying transient registry failures with
+// exponential backoff. Up to attempts additional calls are made, waiting
+// backoff before the first retry and doubling the wait after each failure.
+// Permanent registry responses such as 401, 403 and 404 are returned
+// immediately.
+func RetryTransient(attempts int, backoff time.Duration, op func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = op()
+		if err == nil || attempt >= attempts || !isTransient(err) {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether the error is worth retrying: registry
+// rate-limiting (429), server-side failures (5xx) and dropped or timed-out
+// connections.
+func isTransient(err error) bool {
+	switch cause := errors.Cause(err).(type) {
+	case imgutil.SaveError:
+		// A partially failed push is worth resuming: the registry keeps the
+		// layers that did upload, so a retried save only sends the rest.
+		for _, diagnostic := range cause.Errors {
+			if isTransient(diagnostic.Cause) {
+				return true
+			}
+		}
+		return false
+	case *transport.Error:
+		return cause.StatusCode == http.StatusTooManyRequests || cause.StatusCode >= 500
+	case *net.OpError:
+		return cause.Err == syscall.ECONNRESET || cause.Temporary() || cause.Timeout()
+	case net.Error:
+		return cause.Temporary() || cause.Timeout()
+	}
+	return false
+}
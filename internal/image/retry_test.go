@@ -0,0 +1,113 @@
+package image_test
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRetry(t *testing.T) {
+	spec.Run(t, "Retry", testRetry, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRetry(t *testing.T, when spec.G, it spec.S) {
+	when("#RetryTransient", func() {
+		// failNTimes fakes a flaky transport: the first n calls fail with err,
+		// later calls succeed.
+		failNTimes := func(n int, err error, calls *int) func() error {
+			return func() error {
+				*calls++
+				if *calls <= n {
+					return err
+				}
+				return nil
+			}
+		}
+
+		it("retries server errors until the operation succeeds", func() {
+			calls := 0
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(2, &transport.Error{StatusCode: 502}, &calls))
+			h.AssertNil(t, err)
+			h.AssertEq(t, calls, 3)
+		})
+
+		it("retries rate-limit responses", func() {
+			calls := 0
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, &transport.Error{StatusCode: 429}, &calls))
+			h.AssertNil(t, err)
+			h.AssertEq(t, calls, 2)
+		})
+
+		it("retries wrapped transient errors", func() {
+			calls := 0
+			wrapped := errors.Wrap(&transport.Error{StatusCode: 500}, "saving image")
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, wrapped, &calls))
+			h.AssertNil(t, err)
+			h.AssertEq(t, calls, 2)
+		})
+
+		it("retries partially failed saves with a transient cause", func() {
+			calls := 0
+			saveErr := imgutil.SaveError{Errors: []imgutil.SaveDiagnostic{
+				{ImageName: "some/image", Cause: &transport.Error{StatusCode: 503}},
+			}}
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, saveErr, &calls))
+			h.AssertNil(t, err)
+			h.AssertEq(t, calls, 2)
+		})
+
+		it("does not retry saves that failed permanently", func() {
+			calls := 0
+			saveErr := imgutil.SaveError{Errors: []imgutil.SaveDiagnostic{
+				{ImageName: "some/image", Cause: &transport.Error{StatusCode: 404}},
+			}}
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, saveErr, &calls))
+			h.AssertNotNil(t, err)
+			h.AssertEq(t, calls, 1)
+		})
+
+		it("retries connection resets", func() {
+			calls := 0
+			reset := &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, reset, &calls))
+			h.AssertNil(t, err)
+			h.AssertEq(t, calls, 2)
+		})
+
+		it("gives up after the configured number of attempts", func() {
+			calls := 0
+			err := image.RetryTransient(2, time.Millisecond, failNTimes(5, &transport.Error{StatusCode: 503}, &calls))
+			h.AssertError(t, err, "unsupported status code 503")
+			h.AssertEq(t, calls, 3)
+		})
+
+		for _, status := range []int{401, 403, 404} {
+			status := status
+
+			it(fmt.Sprintf("does not retry a %d response", status), func() {
+				calls := 0
+				err := image.RetryTransient(3, time.Millisecond, failNTimes(1, &transport.Error{StatusCode: status}, &calls))
+				h.AssertNotNil(t, err)
+				h.AssertEq(t, calls, 1)
+			})
+		}
+
+		it("does not retry arbitrary errors", func() {
+			calls := 0
+			err := image.RetryTransient(3, time.Millisecond, failNTimes(1, errors.New("some error"), &calls))
+			h.AssertError(t, err, "some error")
+			h.AssertEq(t, calls, 1)
+		})
+	})
+}
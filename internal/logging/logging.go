@@ -2,13 +2,16 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/apex/log"
+	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/logging"
@@ -29,10 +32,13 @@ const (
 type LogWithWriters struct {
 	sync.Mutex
 	log.Logger
-	wantTime bool
-	clock    func() time.Time
-	out      io.Writer
-	errOut   io.Writer
+	wantTime   bool
+	wantJSON   bool
+	stampLines bool
+	quiet      bool
+	clock      func() time.Time
+	out        io.Writer
+	errOut     io.Writer
 }
 
 func WithClock(clock func() time.Time) func(writers *LogWithWriters) {
@@ -66,18 +72,31 @@ func (lw *LogWithWriters) WriterForLevel(level logging.Level) io.Writer {
 		return ioutil.Discard
 	}
 
+	out := lw.out
 	if level == logging.ErrorLevel {
-		return lw.errOut
+		out = lw.errOut
 	}
 
-	return lw.out
+	if lw.wantJSON {
+		return &eventWriter{logger: lw, level: level, out: out}
+	}
+
+	if lw.stampLines {
+		return logging.NewTimestampWriter(out, lw.clock)
+	}
+
+	return out
 }
 
 func (lw *LogWithWriters) HandleLog(e *log.Entry) error {
+	writer := lw.WriterForLevel(logging.Level(e.Level))
+	if ew, ok := writer.(*eventWriter); ok {
+		_, err := ew.WriteEvent("", []byte(e.Message))
+		return err
+	}
+
 	lw.Lock()
 	defer lw.Unlock()
-
-	writer := lw.WriterForLevel(logging.Level(e.Level))
 	if lw.wantTime {
 		ts := lw.clock().Format(timeFmt)
 		_, _ = fmt.Fprint(writer, appendMissingLineFeed(fmt.Sprintf("%s %s%s", ts, formatLevel(e.Level), e.Message)))
@@ -97,10 +116,32 @@ func (lw *LogWithWriters) WantTime(f bool) {
 	lw.wantTime = f
 }
 
+// WantLineTimestamps prefixes every output line, including lifecycle phase
+// output, with an RFC3339 timestamp. Unlike WantTime it applies to the writers
+// handed out by WriterForLevel.
+func (lw *LogWithWriters) WantLineTimestamps(f bool) {
+	lw.stampLines = f
+}
+
+// WantFormat selects between the default colored text output and
+// newline-delimited JSON events, see logging.Event.
+func (lw *LogWithWriters) WantFormat(format string) error {
+	switch format {
+	case "", "text":
+		lw.wantJSON = false
+	case "json":
+		lw.wantJSON = true
+	default:
+		return errors.Errorf("invalid log format %s: must be 'text' or 'json'", style.Symbol(format))
+	}
+	return nil
+}
+
 func (lw *LogWithWriters) WantQuiet(f bool) {
 	if f {
 		lw.Level = quietLevel
 	}
+	lw.quiet = f
 }
 
 func (lw *LogWithWriters) WantVerbose(f bool) {
@@ -109,10 +150,32 @@ func (lw *LogWithWriters) WantVerbose(f bool) {
 	}
 }
 
+// WantLevel sets the minimum level that is logged; --verbose and --quiet
+// remain shorthands for the debug and warn levels.
+func (lw *LogWithWriters) WantLevel(level string) error {
+	switch level {
+	case "debug":
+		lw.Level = log.DebugLevel
+	case "info":
+		lw.Level = log.InfoLevel
+	case "warn":
+		lw.Level = log.WarnLevel
+	case "error":
+		lw.Level = log.ErrorLevel
+	default:
+		return errors.Errorf("invalid log level %s: must be 'debug', 'info', 'warn' or 'error'", style.Symbol(level))
+	}
+	return nil
+}
+
 func (lw *LogWithWriters) IsVerbose() bool {
 	return lw.Level == log.DebugLevel
 }
 
+func (lw *LogWithWriters) IsQuiet() bool {
+	return lw.quiet
+}
+
 func formatLevel(ll log.Level) string {
 	switch ll {
 	case log.ErrorLevel:
@@ -124,6 +187,51 @@ func formatLevel(ll log.Level) string {
 	return ""
 }
 
+// eventWriter renders writes as newline-delimited JSON events; phase output
+// arriving through a logging.PrefixWriter keeps its phase name attached.
+type eventWriter struct {
+	logger *LogWithWriters
+	level  logging.Level
+	out    io.Writer
+}
+
+func (w *eventWriter) Write(buf []byte) (int, error) {
+	return w.WriteEvent("", buf)
+}
+
+func (w *eventWriter) WriteEvent(phase string, buf []byte) (int, error) {
+	w.logger.Lock()
+	defer w.logger.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(buf), "\n"), "\n") {
+		data, err := json.Marshal(logging.Event{
+			Timestamp: w.logger.clock(),
+			Level:     levelName(w.level),
+			Phase:     phase,
+			Message:   line,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if _, err := fmt.Fprintf(w.out, "%s\n", data); err != nil {
+			return 0, err
+		}
+	}
+	return len(buf), nil
+}
+
+func levelName(level logging.Level) string {
+	switch level {
+	case logging.DebugLevel:
+		return "debug"
+	case logging.WarnLevel:
+		return "warning"
+	case logging.ErrorLevel:
+		return "error"
+	}
+	return "info"
+}
+
 // preserve behavior of other loggers
 func appendMissingLineFeed(msg string) string {
 	buff := []byte(msg)
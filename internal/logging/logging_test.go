@@ -2,9 +2,11 @@ package logging_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -197,6 +199,121 @@ func TestLogWithWriters(t *testing.T) {
 			})
 		})
 
+		when("line timestamps are enabled", func() {
+			it.Before(func() {
+				logger.WantLineTimestamps(true)
+			})
+
+			it("prefixes logged lines", func() {
+				logger.Info("test")
+				h.AssertEq(t, fOut(), "2019-05-15T01:01:01Z test\n")
+			})
+
+			it("prefixes phase output", func() {
+				writer := logging.GetWriterForLevel(logger, logging.InfoLevel)
+				_, err := writer.Write([]byte("phase output\n"))
+				h.AssertNil(t, err)
+				h.AssertEq(t, fOut(), "2019-05-15T01:01:01Z phase output\n")
+			})
+		})
+
+		when("level is set to warn", func() {
+			it.Before(func() {
+				h.AssertNil(t, logger.WantLevel("warn"))
+			})
+
+			it("drops debug and info messages", func() {
+				logger.Debug("debug_")
+				logger.Info("info_")
+
+				output := fOut()
+				h.AssertNotContains(t, output, "debug_\n")
+				h.AssertNotContains(t, output, "info_\n")
+			})
+
+			it("keeps warnings and errors", func() {
+				logger.Warn("warn_")
+				logger.Error("error_")
+
+				h.AssertContains(t, fOut(), "warn_\n")
+				h.AssertContains(t, fErr(), "error_\n")
+			})
+		})
+
+		when("level is set to debug", func() {
+			it("logs debug messages", func() {
+				h.AssertNil(t, logger.WantLevel("debug"))
+				logger.Debug("debug_")
+
+				h.AssertContains(t, fOut(), "debug_\n")
+				h.AssertEq(t, logger.IsVerbose(), true)
+			})
+		})
+
+		when("level is unknown", func() {
+			it("errors", func() {
+				h.AssertError(t, logger.WantLevel("chatty"), "must be 'debug', 'info', 'warn' or 'error'")
+			})
+		})
+
+		when("format is set to json", func() {
+			it.Before(func() {
+				h.AssertNil(t, logger.WantFormat("json"))
+			})
+
+			it("emits events with timestamp, level and message", func() {
+				logger.Info("some-message")
+
+				var event logging.Event
+				h.AssertNil(t, json.Unmarshal([]byte(fOut()), &event))
+				h.AssertEq(t, event.Level, "info")
+				h.AssertEq(t, event.Message, "some-message")
+				h.AssertEq(t, event.Phase, "")
+				h.AssertEq(t, event.Timestamp.Format(timeFmt), testTime)
+			})
+
+			it("emits errors to the error writer", func() {
+				logger.Error("some-error")
+
+				var event logging.Event
+				h.AssertNil(t, json.Unmarshal([]byte(fErr()), &event))
+				h.AssertEq(t, event.Level, "error")
+				h.AssertEq(t, event.Message, "some-error")
+			})
+
+			it("attaches phase names to prefixed phase output", func() {
+				for _, phase := range []string{"detector", "builder", "exporter"} {
+					writer := logging.NewPrefixWriter(logging.GetWriterForLevel(logger, logging.InfoLevel), phase)
+					_, err := writer.Write([]byte(phase + " output\n"))
+					h.AssertNil(t, err)
+				}
+
+				var phases []string
+				for _, line := range strings.Split(strings.TrimSpace(fOut()), "\n") {
+					var event logging.Event
+					h.AssertNil(t, json.Unmarshal([]byte(line), &event))
+					phases = append(phases, event.Phase)
+					h.AssertEq(t, event.Message, event.Phase+" output")
+				}
+				h.AssertEq(t, phases, []string{"detector", "builder", "exporter"})
+			})
+
+			it("splits multi-line writes into separate events", func() {
+				writer := logging.GetWriterForLevel(logger, logging.InfoLevel)
+				_, err := writer.Write([]byte("line-1\nline-2\n"))
+				h.AssertNil(t, err)
+
+				lines := strings.Split(strings.TrimSpace(fOut()), "\n")
+				h.AssertEq(t, len(lines), 2)
+			})
+		})
+
+		when("format is unknown", func() {
+			it("errors", func() {
+				h.AssertError(t, logger.WantFormat("xml"), "must be 'text' or 'json'")
+			})
+		})
+
 		it("will convert an empty string to a line feed", func() {
 			logger.Info("")
 			expected := "\n"
@@ -38,9 +38,10 @@ type Project struct {
 }
 
 type Descriptor struct {
-	Project  Project                `toml:"project"`
-	Build    Build                  `toml:"build"`
-	Metadata map[string]interface{} `toml:"metadata"`
+	SchemaVersion string                 `toml:"schema-version"`
+	Project       Project                `toml:"project"`
+	Build         Build                  `toml:"build"`
+	Metadata      map[string]interface{} `toml:"metadata"`
 }
 
 func ReadProjectDescriptor(pathToFile string) (Descriptor, error) {
@@ -53,15 +54,24 @@ func ReadProjectDescriptor(pathToFile string) (Descriptor, error) {
 	}
 
 	var descriptor Descriptor
-	_, err = toml.Decode(string(projectTomlContents), &descriptor)
+	md, err := toml.Decode(string(projectTomlContents), &descriptor)
 	if err != nil {
 		return Descriptor{}, err
 	}
 
+	for _, key := range md.Undecoded() {
+		if len(key) == 1 {
+			return Descriptor{}, errors.Errorf("project.toml: unknown top-level key '%s'", key.String())
+		}
+	}
+
 	return descriptor, descriptor.validate()
 }
 
 func (p Descriptor) validate() error {
+	if p.SchemaVersion != "" && p.SchemaVersion != "0.1" {
+		return errors.Errorf("project.toml: unsupported schema-version '%s'", p.SchemaVersion)
+	}
 	if p.Build.Exclude != nil && p.Build.Include != nil {
 		return errors.New("project.toml: cannot have both include and exclude defined")
 	}
@@ -124,7 +124,7 @@ name = "gallant"
 			expected := 0
 			if len(projectDescriptor.Build.Env) != 0 {
 				t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
-					expected, string(len(projectDescriptor.Build.Env)))
+					expected, len(projectDescriptor.Build.Env))
 			}
 
 			for _, envVar := range projectDescriptor.Build.Env {
@@ -143,6 +143,67 @@ name = "gallant"
 		})
 	})
 
+	it("should accept a supported schema version", func() {
+		projectToml := `
+schema-version = "0.1"
+
+[project]
+name = "gallant"
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "0.1"
+		if projectDescriptor.SchemaVersion != expected {
+			t.Fatalf("Expected\n-----\n%#v\n-----\nbut got\n-----\n%#v\n",
+				expected, projectDescriptor.SchemaVersion)
+		}
+	})
+
+	it("should fail for an unsupported schema version", func() {
+		projectToml := `
+schema-version = "0.2"
+
+[project]
+name = "gallant"
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+		if err == nil {
+			t.Fatal("Expected error for unsupported schema version")
+		}
+	})
+
+	it("should fail for an unknown top-level key", func() {
+		projectToml := `
+[project]
+name = "gallant"
+
+[bulid]
+exclude = [ "*.jar" ]
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+		if err == nil {
+			t.Fatal("Expected error for unknown top-level key")
+		}
+	})
+
 	it("should enforce mutual exclusivity between exclude and include", func() {
 		projectToml := `
 [project]
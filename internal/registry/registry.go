@@ -0,0 +1,176 @@
+// Package registry resolves buildpack references of the form
+// '<namespace>/<name>@<version>' against a buildpack registry index, a git
+// repository mapping each published buildpack to the package image that
+// provides it.
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Buildpack is one published entry in the registry index.
+type Buildpack struct {
+	Namespace string `json:"ns"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Yanked    bool   `json:"yanked"`
+	Address   string `json:"addr"`
+}
+
+// Resolver finds the package image providing a buildpack reference.
+type Resolver interface {
+	Resolve(namespace, name, version string) (Buildpack, error)
+}
+
+// IsRegistryRef reports whether the locator looks like a registry reference,
+// that is '<namespace>/<name>@<version>' where the version is either valid
+// semver or 'latest'.
+func IsRegistryRef(locator string) bool {
+	_, _, version, err := ParseRef(locator)
+	return err == nil && version != ""
+}
+
+// ParseRef splits a '<namespace>/<name>[@<version>]' reference. An omitted
+// version and 'latest' both mean the highest published version.
+func ParseRef(ref string) (namespace, name, version string, err error) {
+	nameAndVersion := ref
+	if i := strings.Index(ref, "@"); i >= 0 {
+		nameAndVersion, version = ref[:i], ref[i+1:]
+	}
+
+	parts := strings.Split(nameAndVersion, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", errors.Errorf("invalid registry reference %s: must be in the form '<namespace>/<name>@<version>'", style.Symbol(ref))
+	}
+
+	if version != "" && version != "latest" {
+		if _, err := semver.NewVersion(version); err != nil {
+			return "", "", "", errors.Errorf("invalid registry reference %s: version %s is not valid semver", style.Symbol(ref), style.Symbol(version))
+		}
+	}
+
+	return parts[0], parts[1], version, nil
+}
+
+// IndexResolver resolves references against a registry index checked out on
+// disk.
+type IndexResolver struct {
+	indexDir string
+}
+
+func NewIndexResolver(indexDir string) *IndexResolver {
+	return &IndexResolver{indexDir: indexDir}
+}
+
+// Resolve returns the entry for the given version, or the highest non-yanked
+// version when the version is empty or 'latest'.
+func (r *IndexResolver) Resolve(namespace, name, version string) (Buildpack, error) {
+	entries, err := r.readEntries(namespace, name)
+	if err != nil {
+		return Buildpack{}, err
+	}
+
+	if version == "" || version == "latest" {
+		return highestVersion(namespace, name, entries)
+	}
+
+	for _, entry := range entries {
+		if entry.Version == version {
+			if entry.Yanked {
+				return Buildpack{}, errors.Errorf("buildpack %s version %s has been yanked", style.Symbol(namespace+"/"+name), style.Symbol(version))
+			}
+			return entry, nil
+		}
+	}
+
+	return Buildpack{}, errors.Errorf("buildpack %s has no version %s in the registry", style.Symbol(namespace+"/"+name), style.Symbol(version))
+}
+
+func (r *IndexResolver) readEntries(namespace, name string) ([]Buildpack, error) {
+	path, err := IndexPath(r.indexDir, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("buildpack %s not found in the registry", style.Symbol(namespace+"/"+name))
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Buildpack
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Buildpack
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, errors.Wrapf(err, "parsing index entry for buildpack %s", style.Symbol(namespace+"/"+name))
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func highestVersion(namespace, name string, entries []Buildpack) (Buildpack, error) {
+	var (
+		best        Buildpack
+		bestVersion *semver.Version
+	)
+	for _, entry := range entries {
+		if entry.Yanked {
+			continue
+		}
+
+		v, err := semver.NewVersion(entry.Version)
+		if err != nil {
+			continue
+		}
+
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			best, bestVersion = entry, v
+		}
+	}
+
+	if bestVersion == nil {
+		return Buildpack{}, errors.Errorf("buildpack %s has no published versions in the registry", style.Symbol(namespace+"/"+name))
+	}
+	return best, nil
+}
+
+// IndexPath locates the index file for a buildpack. Entries are sharded by
+// name length in the style of Cargo's registry: one- and two-character names
+// live under '1/' and '2/', three-character names under '3/<first letter>/',
+// and longer names under '<first two>/<next two>/'.
+func IndexPath(indexDir, namespace, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("buildpack name cannot be empty")
+	}
+
+	file := namespace + "_" + name
+	switch {
+	case len(name) <= 2:
+		return filepath.Join(indexDir, strconv.Itoa(len(name)), file), nil
+	case len(name) == 3:
+		return filepath.Join(indexDir, "3", name[:1], file), nil
+	default:
+		return filepath.Join(indexDir, name[:2], name[2:4], file), nil
+	}
+}
@@ -0,0 +1,169 @@
+package registry_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/registry"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistry(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Registry", testRegistry, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRegistry(t *testing.T, when spec.G, it spec.S) {
+	when("#ParseRef", func() {
+		it("splits a fully qualified reference", func() {
+			namespace, name, version, err := registry.ParseRef("example/java@1.2.3")
+			h.AssertNil(t, err)
+			h.AssertEq(t, namespace, "example")
+			h.AssertEq(t, name, "java")
+			h.AssertEq(t, version, "1.2.3")
+		})
+
+		it("allows the version to be omitted", func() {
+			namespace, name, version, err := registry.ParseRef("example/java")
+			h.AssertNil(t, err)
+			h.AssertEq(t, namespace, "example")
+			h.AssertEq(t, name, "java")
+			h.AssertEq(t, version, "")
+		})
+
+		it("allows 'latest' as the version", func() {
+			_, _, version, err := registry.ParseRef("example/java@latest")
+			h.AssertNil(t, err)
+			h.AssertEq(t, version, "latest")
+		})
+
+		it("rejects references without a namespace", func() {
+			_, _, _, err := registry.ParseRef("java@1.2.3")
+			h.AssertError(t, err, "invalid registry reference 'java@1.2.3'")
+		})
+
+		it("rejects versions that are not semver", func() {
+			_, _, _, err := registry.ParseRef("example/java@not-a-version")
+			h.AssertError(t, err, "version 'not-a-version' is not valid semver")
+		})
+	})
+
+	when("#IsRegistryRef", func() {
+		it("accepts '<namespace>/<name>@<version>' references", func() {
+			h.AssertTrue(t, registry.IsRegistryRef("example/java@1.2.3"))
+			h.AssertTrue(t, registry.IsRegistryRef("example/java@latest"))
+		})
+
+		it("rejects references without a version, so plain image names stay package locators", func() {
+			h.AssertFalse(t, registry.IsRegistryRef("example/java"))
+		})
+
+		it("rejects image references with a digest", func() {
+			h.AssertFalse(t, registry.IsRegistryRef("example/java@sha256:1234"))
+		})
+	})
+
+	when("#IndexPath", func() {
+		it("shards entries by name length", func() {
+			for _, tc := range []struct {
+				name     string
+				expected string
+			}{
+				{name: "a", expected: filepath.Join("1", "example_a")},
+				{name: "go", expected: filepath.Join("2", "example_go")},
+				{name: "php", expected: filepath.Join("3", "p", "example_php")},
+				{name: "java", expected: filepath.Join("ja", "va", "example_java")},
+				{name: "python", expected: filepath.Join("py", "th", "example_python")},
+			} {
+				path, err := registry.IndexPath("index", "example", tc.name)
+				h.AssertNil(t, err)
+				h.AssertEq(t, path, filepath.Join("index", tc.expected))
+			}
+		})
+
+		it("errors for an empty name", func() {
+			_, err := registry.IndexPath("index", "example", "")
+			h.AssertError(t, err, "buildpack name cannot be empty")
+		})
+	})
+
+	when("#Resolve", func() {
+		var (
+			indexDir string
+			resolver *registry.IndexResolver
+		)
+
+		writeEntries := func(namespace, name string, entries string) {
+			path, err := registry.IndexPath(indexDir, namespace, name)
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+			h.AssertNil(t, ioutil.WriteFile(path, []byte(entries), 0644))
+		}
+
+		it.Before(func() {
+			var err error
+			indexDir, err = ioutil.TempDir("", "registry-index")
+			h.AssertNil(t, err)
+
+			resolver = registry.NewIndexResolver(indexDir)
+
+			writeEntries("example", "java", `
+{"ns":"example","name":"java","version":"1.0.0","yanked":false,"addr":"registry.example.com/example/java@1.0.0"}
+{"ns":"example","name":"java","version":"1.1.0","yanked":true,"addr":"registry.example.com/example/java@1.1.0"}
+{"ns":"example","name":"java","version":"0.9.0","yanked":false,"addr":"registry.example.com/example/java@0.9.0"}
+`)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(indexDir))
+		})
+
+		it("returns the entry for an exact version", func() {
+			entry, err := resolver.Resolve("example", "java", "0.9.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, entry.Address, "registry.example.com/example/java@0.9.0")
+		})
+
+		it("returns the highest non-yanked version for 'latest'", func() {
+			entry, err := resolver.Resolve("example", "java", "latest")
+			h.AssertNil(t, err)
+			h.AssertEq(t, entry.Version, "1.0.0")
+			h.AssertEq(t, entry.Address, "registry.example.com/example/java@1.0.0")
+		})
+
+		it("treats an empty version like 'latest'", func() {
+			entry, err := resolver.Resolve("example", "java", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, entry.Version, "1.0.0")
+		})
+
+		it("errors when the requested version has been yanked", func() {
+			_, err := resolver.Resolve("example", "java", "1.1.0")
+			h.AssertError(t, err, "buildpack 'example/java' version '1.1.0' has been yanked")
+		})
+
+		it("errors when the requested version does not exist", func() {
+			_, err := resolver.Resolve("example", "java", "2.0.0")
+			h.AssertError(t, err, "buildpack 'example/java' has no version '2.0.0' in the registry")
+		})
+
+		it("errors when the buildpack is not in the index", func() {
+			_, err := resolver.Resolve("example", "missing", "latest")
+			h.AssertError(t, err, "buildpack 'example/missing' not found in the registry")
+		})
+
+		it("errors when every version has been yanked", func() {
+			writeEntries("example", "ruby", `{"ns":"example","name":"ruby","version":"1.0.0","yanked":true,"addr":"registry.example.com/example/ruby@1.0.0"}`)
+
+			_, err := resolver.Resolve("example", "ruby", "latest")
+			h.AssertError(t, err, "buildpack 'example/ruby' has no published versions in the registry")
+		})
+	})
+}
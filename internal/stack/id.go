@@ -0,0 +1,12 @@
+package stack
+
+import (
+	"github.com/buildpacks/pack/internal/dist"
+)
+
+// IDsMatch reports whether two stack IDs name compatible stacks: the IDs are
+// equal, or one of them is the wildcard dist.AnyStackID, which matches any
+// stack.
+func IDsMatch(a, b string) bool {
+	return a == b || a == dist.AnyStackID || b == dist.AnyStackID
+}
@@ -0,0 +1,36 @@
+package stack_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/stack"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestIDsMatch(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "testIDsMatch", testIDsMatch, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testIDsMatch(t *testing.T, when spec.G, it spec.S) {
+	when("#IDsMatch", func() {
+		it("matches equal stack IDs", func() {
+			h.AssertTrue(t, stack.IDsMatch("some.stack.id", "some.stack.id"))
+		})
+
+		it("does not match different stack IDs", func() {
+			h.AssertFalse(t, stack.IDsMatch("some.stack.id", "other.stack.id"))
+		})
+
+		it("matches the wildcard against any stack ID", func() {
+			h.AssertTrue(t, stack.IDsMatch("*", "some.stack.id"))
+			h.AssertTrue(t, stack.IDsMatch("some.stack.id", "*"))
+			h.AssertTrue(t, stack.IDsMatch("*", "*"))
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package stack
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// BestRunImageMirror picks the run image to use for a build or rebase targeting the given
+// registry. Mirrors from the local config (preferredMirrors) are preferred over mirrors
+// declared by the stack, and within each list an image on the target registry wins. When
+// no image matches the registry, the first preferred mirror is used if one exists,
+// otherwise the stack's declared run image.
+func BestRunImageMirror(registry string, runImage string, mirrors []string, preferredMirrors []string) string {
+	runImageList := append(preferredMirrors, append([]string{runImage}, mirrors...)...)
+	for _, img := range runImageList {
+		ref, err := name.ParseReference(img, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		if ref.Context().RegistryStr() == registry {
+			return img
+		}
+	}
+
+	if len(preferredMirrors) > 0 {
+		return preferredMirrors[0]
+	}
+
+	return runImage
+}
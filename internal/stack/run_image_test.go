@@ -0,0 +1,76 @@
+package stack_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/stack"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBestRunImageMirror(t *testing.T) {
+	spec.Run(t, "testBestRunImageMirror", testBestRunImageMirror, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBestRunImageMirror(t *testing.T, when spec.G, it spec.S) {
+	when("BestRunImageMirror", func() {
+		for _, tc := range []struct {
+			desc             string
+			registry         string
+			runImage         string
+			mirrors          []string
+			preferredMirrors []string
+			expected         string
+		}{
+			{
+				desc:             "preferred mirror on the target registry wins over a stack mirror on the same registry",
+				registry:         "registry.example.com",
+				runImage:         "stack/run",
+				mirrors:          []string{"registry.example.com/stack/run"},
+				preferredMirrors: []string{"registry.example.com/local/run"},
+				expected:         "registry.example.com/local/run",
+			},
+			{
+				desc:     "stack mirror on the target registry wins over the run image",
+				registry: "registry.example.com",
+				runImage: "stack/run",
+				mirrors:  []string{"other.example.com/stack/run", "registry.example.com/stack/run"},
+				expected: "registry.example.com/stack/run",
+			},
+			{
+				desc:     "run image wins when it is on the target registry",
+				registry: "index.docker.io",
+				runImage: "stack/run",
+				mirrors:  []string{"other.example.com/stack/run"},
+				expected: "stack/run",
+			},
+			{
+				desc:             "first preferred mirror is used when nothing matches the registry",
+				registry:         "registry.example.com",
+				runImage:         "stack/run",
+				mirrors:          []string{"other.example.com/stack/run"},
+				preferredMirrors: []string{"first.example.com/local/run", "second.example.com/local/run"},
+				expected:         "first.example.com/local/run",
+			},
+			{
+				desc:     "run image is the fallback when there are no mirrors for the registry",
+				registry: "registry.example.com",
+				runImage: "stack/run",
+				mirrors:  []string{"other.example.com/stack/run"},
+				expected: "stack/run",
+			},
+		} {
+			tc := tc
+
+			when(tc.desc, func() {
+				it(fmt.Sprintf("selects '%s'", tc.expected), func() {
+					selected := stack.BestRunImageMirror(tc.registry, tc.runImage, tc.mirrors, tc.preferredMirrors)
+					h.AssertEq(t, selected, tc.expected)
+				})
+			})
+		}
+	})
+}
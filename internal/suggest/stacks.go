@@ -0,0 +1,68 @@
+package suggest
+
+import "sort"
+
+// Stack is a community-maintained stack, surfaced by 'pack suggest-stacks'.
+type Stack struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Maintainer  string   `json:"maintainer"`
+	BuildImage  string   `json:"build-image"`
+	RunImage    string   `json:"run-image"`
+	OS          string   `json:"os"`
+	Arch        string   `json:"arch"`
+	Mixins      []string `json:"mixins,omitempty"`
+}
+
+var defaultStacks = []Stack{
+	{
+		ID:          "heroku-18",
+		Description: "The official Heroku stack based on Ubuntu 18.04",
+		Maintainer:  "Heroku",
+		BuildImage:  "heroku/pack:18-build",
+		RunImage:    "heroku/pack:18",
+		OS:          "linux",
+		Arch:        "amd64",
+	},
+	{
+		ID:          "io.buildpacks.stacks.bionic",
+		Description: "A minimal Cloud Foundry stack based on Ubuntu 18.04",
+		Maintainer:  "Cloud Foundry",
+		BuildImage:  "cloudfoundry/build:base-cnb",
+		RunImage:    "cloudfoundry/run:base-cnb",
+		OS:          "linux",
+		Arch:        "amd64",
+	},
+	{
+		ID:          "org.cloudfoundry.stacks.cflinuxfs3",
+		Description: "A large Cloud Foundry stack based on Ubuntu 18.04",
+		Maintainer:  "Cloud Foundry",
+		BuildImage:  "cloudfoundry/build:full-cnb",
+		RunImage:    "cloudfoundry/run:full-cnb",
+		OS:          "linux",
+		Arch:        "amd64",
+	},
+	{
+		ID:          "org.cloudfoundry.stacks.tiny",
+		Description: "A tiny Cloud Foundry stack based on Ubuntu 18.04, similar to distroless",
+		Maintainer:  "Cloud Foundry",
+		BuildImage:  "cloudfoundry/build:tiny-cnb",
+		RunImage:    "cloudfoundry/run:tiny-cnb",
+		OS:          "linux",
+		Arch:        "amd64",
+	},
+}
+
+// Stacks returns the community stacks matching the given OS and architecture,
+// sorted by stack ID. Empty filter values match every stack.
+func Stacks(os, arch string) []Stack {
+	stacks := []Stack{}
+	for _, stack := range defaultStacks {
+		if (os == "" || stack.OS == os) && (arch == "" || stack.Arch == arch) {
+			stacks = append(stacks, stack)
+		}
+	}
+
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].ID < stacks[j].ID })
+	return stacks
+}
@@ -0,0 +1,42 @@
+package suggest_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/suggest"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestStacks(t *testing.T) {
+	spec.Run(t, "Stacks", testStacks, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testStacks(t *testing.T, when spec.G, it spec.S) {
+	when("#Stacks", func() {
+		it("returns every community stack sorted by ID when unfiltered", func() {
+			stacks := suggest.Stacks("", "")
+
+			var ids []string
+			for _, stack := range stacks {
+				ids = append(ids, stack.ID)
+			}
+			h.AssertEq(t, ids, []string{
+				"heroku-18",
+				"io.buildpacks.stacks.bionic",
+				"org.cloudfoundry.stacks.cflinuxfs3",
+				"org.cloudfoundry.stacks.tiny",
+			})
+		})
+
+		it("keeps only stacks matching the OS and architecture", func() {
+			stacks := suggest.Stacks("linux", "amd64")
+			h.AssertEq(t, len(stacks), 4)
+
+			h.AssertEq(t, len(suggest.Stacks("windows", "")), 0)
+			h.AssertEq(t, len(suggest.Stacks("linux", "arm64")), 0)
+		})
+	})
+}
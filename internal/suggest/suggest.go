@@ -0,0 +1,84 @@
+// Package suggest holds the curated list of builders pack recommends and
+// merges it with any organization-provided entries from the config.
+package suggest
+
+import (
+	"sort"
+
+	"github.com/buildpacks/pack/internal/config"
+)
+
+// Builder is a recommended builder, surfaced by 'pack suggest-builders' and
+// trusted by default.
+type Builder struct {
+	Name               string
+	Image              string
+	DefaultDescription string
+}
+
+var defaultBuilders = []Builder{
+	{
+		Name:               "Cloud Foundry",
+		Image:              "cloudfoundry/cnb:bionic",
+		DefaultDescription: "Small base image with Java & Node.js",
+	},
+	{
+		Name:               "Cloud Foundry",
+		Image:              "cloudfoundry/cnb:cflinuxfs3",
+		DefaultDescription: "Larger base image with Java, Node.js & Python",
+	},
+	{
+		Name:               "Cloud Foundry",
+		Image:              "cloudfoundry/cnb:tiny",
+		DefaultDescription: "Tiny base image (bionic build image, distroless run image) with buildpacks for Golang",
+	},
+	{
+		Name:               "Heroku",
+		Image:              "heroku/buildpacks:18",
+		DefaultDescription: "heroku-18 base image with buildpacks for Ruby, Java, Node.js, Python, Golang, & PHP",
+	},
+}
+
+// Builders returns the curated defaults merged with the config's suggested
+// builders, sorted by image name. A config entry whose image matches a default
+// overrides its vendor name and description; other entries are appended.
+func Builders(cfg config.Config) []Builder {
+	builders := make([]Builder, len(defaultBuilders))
+	copy(builders, defaultBuilders)
+
+	for _, sb := range cfg.SuggestedBuilders {
+		if sb.Image == "" {
+			continue
+		}
+		merged := false
+		for i := range builders {
+			if builders[i].Image == sb.Image {
+				if sb.Name != "" {
+					builders[i].Name = sb.Name
+				}
+				if sb.Description != "" {
+					builders[i].DefaultDescription = sb.Description
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			builders = append(builders, Builder{Name: sb.Name, Image: sb.Image, DefaultDescription: sb.Description})
+		}
+	}
+
+	sort.Slice(builders, func(i, j int) bool { return builders[i].Image < builders[j].Image })
+	return builders
+}
+
+// IsSuggested reports whether image is a suggested builder, either curated or
+// added through the config.
+func IsSuggested(cfg config.Config, image string) bool {
+	for _, builder := range Builders(cfg) {
+		if builder.Image == image {
+			return true
+		}
+	}
+	return false
+}
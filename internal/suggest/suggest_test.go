@@ -0,0 +1,85 @@
+package suggest_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/suggest"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSuggest(t *testing.T) {
+	spec.Run(t, "Suggest", testSuggest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSuggest(t *testing.T, when spec.G, it spec.S) {
+	when("#Builders", func() {
+		it("returns the curated defaults sorted by image", func() {
+			builders := suggest.Builders(config.Config{})
+
+			var images []string
+			for _, builder := range builders {
+				images = append(images, builder.Image)
+			}
+			h.AssertEq(t, images, []string{
+				"cloudfoundry/cnb:bionic",
+				"cloudfoundry/cnb:cflinuxfs3",
+				"cloudfoundry/cnb:tiny",
+				"heroku/buildpacks:18",
+			})
+		})
+
+		it("appends suggested builders from the config", func() {
+			builders := suggest.Builders(config.Config{
+				SuggestedBuilders: []config.SuggestedBuilder{
+					{Name: "Acme", Image: "acme/builder:latest", Description: "Acme's internal builder"},
+				},
+			})
+
+			h.AssertEq(t, len(builders), 5)
+			h.AssertEq(t, builders[0], suggest.Builder{
+				Name:               "Acme",
+				Image:              "acme/builder:latest",
+				DefaultDescription: "Acme's internal builder",
+			})
+		})
+
+		it("lets the config override a curated entry", func() {
+			builders := suggest.Builders(config.Config{
+				SuggestedBuilders: []config.SuggestedBuilder{
+					{Image: "heroku/buildpacks:18", Description: "Our blessed Heroku builder"},
+				},
+			})
+
+			h.AssertEq(t, len(builders), 4)
+			h.AssertEq(t, builders[3], suggest.Builder{
+				Name:               "Heroku",
+				Image:              "heroku/buildpacks:18",
+				DefaultDescription: "Our blessed Heroku builder",
+			})
+		})
+
+		it("skips config entries without an image", func() {
+			builders := suggest.Builders(config.Config{
+				SuggestedBuilders: []config.SuggestedBuilder{{Name: "Acme"}},
+			})
+
+			h.AssertEq(t, len(builders), 4)
+		})
+	})
+
+	when("#IsSuggested", func() {
+		it("reports curated and config-provided builders", func() {
+			cfg := config.Config{
+				SuggestedBuilders: []config.SuggestedBuilder{{Image: "acme/builder:latest"}},
+			}
+
+			h.AssertTrue(t, suggest.IsSuggested(cfg, "cloudfoundry/cnb:bionic"))
+			h.AssertTrue(t, suggest.IsSuggested(cfg, "acme/builder:latest"))
+			h.AssertFalse(t, suggest.IsSuggested(cfg, "some/builder"))
+		})
+	})
+}
@@ -4,6 +4,8 @@ package logging
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/buildpacks/pack/internal/style"
 )
@@ -41,6 +43,21 @@ type WithSelectableWriter interface {
 	WriterForLevel(level Level) io.Writer
 }
 
+// WithQuiet is an optional interface for loggers that can report whether all
+// informational output is suppressed.
+type WithQuiet interface {
+	IsQuiet() bool
+}
+
+// IsQuiet returns true when the logger suppresses informational output,
+// letting commands emit machine-readable output instead.
+func IsQuiet(logger Logger) bool {
+	if quietable, ok := logger.(WithQuiet); ok {
+		return quietable.IsQuiet()
+	}
+	return false
+}
+
 // GetWriterForLevel retrieves the appropriate Writer for the log level provided.
 //
 // See WithSelectableWriter
@@ -52,10 +69,28 @@ func GetWriterForLevel(logger Logger, level Level) io.Writer {
 	return logger.Writer()
 }
 
+// Event is one structured log record, as emitted by loggers configured for
+// JSON output. Phase is set for output from a lifecycle phase and empty for
+// pack's own messages.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Phase     string    `json:"phase,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// EventWriter is an optional interface for writers that consume structured
+// events instead of raw text. PrefixWriter uses it when available, so phase
+// output keeps its phase name rather than a text prefix.
+type EventWriter interface {
+	WriteEvent(phase string, buf []byte) (int, error)
+}
+
 // PrefixWriter will prefix writes
 type PrefixWriter struct {
 	out    io.Writer
 	prefix string
+	name   string
 }
 
 // NewPrefixWriter writes by w will be prefixed
@@ -63,15 +98,46 @@ func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
 	return &PrefixWriter{
 		out:    w,
 		prefix: fmt.Sprintf("[%s] ", style.Prefix(prefix)),
+		name:   prefix,
 	}
 }
 
 // Writes bytes to the embedded log function
 func (w *PrefixWriter) Write(buf []byte) (int, error) {
+	if ew, ok := w.out.(EventWriter); ok {
+		return ew.WriteEvent(w.name, buf)
+	}
 	_, _ = fmt.Fprint(w.out, w.prefix+string(buf))
 	return len(buf), nil
 }
 
+// TimestampWriter prefixes each written line with an RFC3339 timestamp, for
+// correlating log output with external events. The prefix is plain text, so it
+// works with both colored and uncolored output.
+type TimestampWriter struct {
+	out   io.Writer
+	clock func() time.Time
+}
+
+// NewTimestampWriter writes by w will be prefixed with a timestamp from clock.
+func NewTimestampWriter(w io.Writer, clock func() time.Time) *TimestampWriter {
+	return &TimestampWriter{
+		out:   w,
+		clock: clock,
+	}
+}
+
+// Writes bytes to the embedded writer, stamping each line
+func (w *TimestampWriter) Write(buf []byte) (int, error) {
+	prefix := w.clock().Format(time.RFC3339)
+	for _, line := range strings.Split(strings.TrimRight(string(buf), "\n"), "\n") {
+		if _, err := fmt.Fprintf(w.out, "%s %s\n", prefix, line); err != nil {
+			return 0, err
+		}
+	}
+	return len(buf), nil
+}
+
 // Tip logs a tip.
 func Tip(l Logger, format string, v ...interface{}) {
 	l.Infof(style.Tip("Tip: ")+format, v...)
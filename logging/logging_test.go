@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	spec.Run(t, "TimestampWriter", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			w      bytes.Buffer
+			writer *TimestampWriter
+		)
+
+		it.Before(func() {
+			writer = NewTimestampWriter(&w, func() time.Time {
+				return time.Date(2019, 5, 15, 1, 1, 1, 0, time.UTC)
+			})
+		})
+
+		it.After(func() {
+			w.Reset()
+		})
+
+		it("should prefix lines with an RFC3339 timestamp", func() {
+			_, err := writer.Write([]byte("test\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, w.String(), "2019-05-15T01:01:01Z test\n")
+		})
+
+		it("should prefix every line of a multi-line write", func() {
+			_, err := writer.Write([]byte("one\ntwo\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, w.String(), "2019-05-15T01:01:01Z one\n2019-05-15T01:01:01Z two\n")
+		})
+	})
+}
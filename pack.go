@@ -8,11 +8,12 @@ import (
 
 	"github.com/buildpacks/pack/internal/buildpackage"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/style"
 )
 
-func extractPackagedBuildpacks(ctx context.Context, pkgImageRef string, fetcher ImageFetcher, publish, noPull bool) (mainBP dist.Buildpack, depBPs []dist.Buildpack, err error) {
-	pkgImage, err := fetcher.Fetch(ctx, pkgImageRef, !publish, !noPull)
+func extractPackagedBuildpacks(ctx context.Context, pkgImageRef string, fetcher ImageFetcher, publish bool, pullPolicy image.PullPolicy) (mainBP dist.Buildpack, depBPs []dist.Buildpack, err error) {
+	pkgImage, err := fetcher.Fetch(ctx, pkgImageRef, !publish, pullPolicy)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "fetching image %s", style.Symbol(pkgImageRef))
 	}
@@ -81,6 +82,14 @@ func extractPackagedBuildpacks(ctx context.Context, pkgImageRef string, fetcher
 	return mainBP, depBPs, nil
 }
 
+// pullPolicyForNoPull translates the older NoPull boolean options to a pull policy.
+func pullPolicyForNoPull(noPull bool) image.PullPolicy {
+	if noPull {
+		return image.PullNever
+	}
+	return image.PullAlways
+}
+
 type openerBlob struct {
 	opener func() (io.ReadCloser, error)
 }
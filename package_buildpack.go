@@ -11,14 +11,44 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 )
 
+// Formats a buildpack package can be saved in.
+const (
+	// FormatImage saves the package as an image in a daemon or registry.
+	FormatImage = "image"
+	// FormatOCI writes the package as an OCI image layout directory.
+	FormatOCI = "oci"
+)
+
 type PackageBuildpackOptions struct {
-	Name    string
-	Config  pubbldpkg.Config
-	Publish bool
-	NoPull  bool
+	Name           string
+	Config         pubbldpkg.Config
+	Publish        bool
+	NoPull         bool
+	Format         string   // FormatImage when empty
+	OutputDir      string   // directory receiving the layout, required with FormatOCI
+	AdditionalTags []string // extra tags applied to the package image, pushed when Publish is set
 }
 
 func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOptions) error {
+	switch opts.Format {
+	case "", FormatImage:
+	case FormatOCI:
+		if opts.OutputDir == "" {
+			return errors.New("output directory must be provided for OCI layout")
+		}
+	default:
+		return errors.Errorf("unknown format %s: must be %s or %s", style.Symbol(opts.Format), style.Symbol(FormatImage), style.Symbol(FormatOCI))
+	}
+
+	additionalTags := make([]string, 0, len(opts.AdditionalTags))
+	for _, tag := range opts.AdditionalTags {
+		tagRef, err := c.parseTagReference(tag)
+		if err != nil {
+			return errors.Wrapf(err, "invalid additional tag '%s'", tag)
+		}
+		additionalTags = append(additionalTags, tagRef.Name())
+	}
+
 	packageBuilder := buildpackage.NewBuilder(c.imageFactory)
 
 	bpURI := opts.Config.Buildpack.URI
@@ -31,6 +61,10 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		return errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(bpURI))
 	}
 
+	if err := dist.ValidateRootBlob(blob); err != nil {
+		return errors.Wrapf(err, "invalid buildpack %s", style.Symbol(bpURI))
+	}
+
 	bp, err := dist.BuildpackFromRootBlob(blob)
 	if err != nil {
 		return errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bpURI))
@@ -45,6 +79,10 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 				return errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(dep.URI))
 			}
 
+			if err := dist.ValidateRootBlob(blob); err != nil {
+				return errors.Wrapf(err, "invalid buildpack %s", style.Symbol(dep.URI))
+			}
+
 			depBP, err := dist.BuildpackFromRootBlob(blob)
 			if err != nil {
 				return errors.Wrapf(err, "creating buildpack from %s", style.Symbol(dep.URI))
@@ -52,7 +90,7 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 
 			packageBuilder.AddDependency(depBP)
 		} else if dep.ImageName != "" {
-			mainBP, depBPs, err := extractPackagedBuildpacks(ctx, dep.ImageName, c.imageFetcher, opts.Publish, opts.NoPull)
+			mainBP, depBPs, err := extractPackagedBuildpacks(ctx, dep.ImageName, c.imageFetcher, opts.Publish, pullPolicyForNoPull(opts.NoPull))
 			if err != nil {
 				return err
 			}
@@ -63,7 +101,14 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		}
 	}
 
-	_, err = packageBuilder.Save(opts.Name, opts.Publish)
+	if opts.Format == FormatOCI {
+		if err := packageBuilder.SaveAsOCILayout(opts.OutputDir); err != nil {
+			return errors.Wrapf(err, "saving OCI layout")
+		}
+		return nil
+	}
+
+	_, err = packageBuilder.Save(opts.Name, opts.Publish, additionalTags...)
 	if err != nil {
 		return errors.Wrapf(err, "saving image")
 	}
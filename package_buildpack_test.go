@@ -61,7 +61,7 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 	})
 
 	createBuildpack := func(descriptor dist.BuildpackDescriptor) string {
-		bp, err := ifakes.NewFakeBuildpackBlob(descriptor, 0644)
+		bp, err := ifakes.NewFakeBuildpackBlob(descriptor, 0755)
 		h.AssertNil(t, err)
 		url := fmt.Sprintf("https://example.com/bp.%s.tgz", h.RandString(12))
 		mockDownloader.EXPECT().Download(gomock.Any(), url).Return(bp, nil).AnyTimes()
@@ -88,11 +88,11 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 			}))
 		})
 
-		shouldFetchNestedPackage := func(demon, pull bool) {
+		shouldFetchNestedPackage := func(demon bool, pull image.PullPolicy) {
 			mockImageFetcher.EXPECT().Fetch(gomock.Any(), nestedPackage.Name(), demon, pull).Return(nestedPackage, nil)
 		}
 
-		shouldNotFindNestedPackageWhenCallingImageFetcherWith := func(demon, pull bool) {
+		shouldNotFindNestedPackageWhenCallingImageFetcherWith := func(demon bool, pull image.PullPolicy) {
 			mockImageFetcher.EXPECT().Fetch(gomock.Any(), nestedPackage.Name(), demon, pull).Return(nil, image.ErrNotFound)
 		}
 
@@ -110,7 +110,7 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 
 		when("publish=false and no-pull=false", func() {
 			it("should pull and use local nested package image", func() {
-				shouldFetchNestedPackage(true, true)
+				shouldFetchNestedPackage(true, image.PullAlways)
 				packageImage := shouldCreateLocalPackage()
 
 				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
@@ -136,7 +136,7 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 
 		when("publish=true and no-pull=false", func() {
 			it("should use remote nested package image", func() {
-				shouldFetchNestedPackage(false, true)
+				shouldFetchNestedPackage(false, image.PullAlways)
 				packageImage := shouldCreateRemotePackage()
 
 				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
@@ -162,7 +162,7 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 
 		when("publish=true and no-pull=true", func() {
 			it("should push to registry and not pull nested package image", func() {
-				shouldFetchNestedPackage(false, false)
+				shouldFetchNestedPackage(false, image.PullNever)
 				packageImage := shouldCreateRemotePackage()
 
 				h.AssertNil(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
@@ -188,7 +188,7 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 
 		when("publish=false no-pull=true and there is no local image", func() {
 			it("should fail without trying to retrieve nested image from registry", func() {
-				shouldNotFindNestedPackageWhenCallingImageFetcherWith(true, false)
+				shouldNotFindNestedPackageWhenCallingImageFetcherWith(true, image.PullNever)
 
 				h.AssertError(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
 					Name: "some/package",
@@ -207,10 +207,66 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("additional tags", func() {
+		var simpleConfig pubbldpkg.Config
+
+		it.Before(func() {
+			simpleConfig = pubbldpkg.Config{
+				Buildpack: dist.BuildpackURI{URI: createBuildpack(dist.BuildpackDescriptor{
+					API:    api.MustParse("0.2"),
+					Info:   dist.BuildpackInfo{ID: "bp.1", Version: "1.2.3"},
+					Stacks: []dist.Stack{{ID: "some.stack.id"}},
+				})},
+			}
+		})
+
+		it("must be valid tag references", func() {
+			h.AssertError(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
+				Name:           "some/package",
+				Config:         simpleConfig,
+				AdditionalTags: []string{"not@valid"},
+			}), "invalid additional tag 'not@valid'")
+		})
+
+		it("saves a single image under every tag in daemon mode", func() {
+			packageImage := fakes.NewImage("example.com/some/package:0.1.0", "", nil)
+			mockImageFactory.EXPECT().NewImage(packageImage.Name(), true).Return(packageImage, nil)
+
+			h.AssertNil(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
+				Name:           packageImage.Name(),
+				Config:         simpleConfig,
+				AdditionalTags: []string{"example.com/some/package:latest"},
+			}))
+
+			// one save call covers every name, so all tags share the image's manifest
+			h.AssertSliceContainsOnly(t, packageImage.SavedNames(),
+				"example.com/some/package:0.1.0",
+				"example.com/some/package:latest",
+			)
+		})
+
+		it("saves a single image under every tag when publishing", func() {
+			packageImage := fakes.NewImage("example.com/some/package:0.1.0", "", nil)
+			mockImageFactory.EXPECT().NewImage(packageImage.Name(), false).Return(packageImage, nil)
+
+			h.AssertNil(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
+				Name:           packageImage.Name(),
+				Config:         simpleConfig,
+				Publish:        true,
+				AdditionalTags: []string{"example.com/some/package:latest"},
+			}))
+
+			h.AssertSliceContainsOnly(t, packageImage.SavedNames(),
+				"example.com/some/package:0.1.0",
+				"example.com/some/package:latest",
+			)
+		})
+	})
+
 	when("nested package is not a valid package", func() {
 		it("should error", func() {
 			notPackageImage := fakes.NewImage("not/package", "", nil)
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), notPackageImage.Name(), true, true).Return(notPackageImage, nil)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), notPackageImage.Name(), true, image.PullAlways).Return(notPackageImage, nil)
 
 			h.AssertError(t, subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
 				Name: "",
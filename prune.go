@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+type PruneOptions struct {
+	DryRun bool // report orphaned caches without removing them
+}
+
+// Prune removes cache volumes left behind by app images that no longer exist
+// in the docker daemon, and returns the names of the volumes it removed. When
+// DryRun is set the candidates are returned but nothing is removed.
+func (c *Client) Prune(ctx context.Context, opts PruneOptions) ([]string, error) {
+	volumes, err := c.docker.VolumeList(ctx, filters.Args{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing volumes")
+	}
+
+	images, err := c.docker.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing images")
+	}
+
+	var imageRefs []name.Reference
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			ref, err := name.ParseReference(tag, name.WeakValidation)
+			if err != nil {
+				continue
+			}
+			imageRefs = append(imageRefs, ref)
+		}
+	}
+
+	var volumeNames []string
+	for _, volume := range volumes.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+
+	orphans := cache.OrphanedCacheVolumes(volumeNames, imageRefs)
+	if opts.DryRun {
+		return orphans, nil
+	}
+
+	for _, volume := range orphans {
+		if err := c.docker.VolumeRemove(ctx, volume, false); err != nil {
+			return nil, errors.Wrapf(err, "removing cache volume %s", style.Symbol(volume))
+		}
+	}
+	return orphans, nil
+}
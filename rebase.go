@@ -2,12 +2,15 @@ package pack
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/lifecycle"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/style"
 )
 
@@ -15,26 +18,175 @@ type RebaseOptions struct {
 	RepoName          string
 	Publish           bool
 	SkipPull          bool
-	RunImage          string
+	RunImage          string // may pin a digest ('<image>@sha256:...'), which bypasses mirror selection
 	AdditionalMirrors map[string][]string
+	AdditionalTags    []string // extra tags applied to the rebased image, pushed when Publish is set
+}
+
+// RebasePlan reports what Rebase would do with the same options, without
+// mutating or pushing anything.
+type RebasePlan struct {
+	RunImage           string `json:"run_image"`
+	CurrentBase        string `json:"current_base"`
+	TargetBase         string `json:"target_base"`
+	BaseUpToDate       bool   `json:"base_up_to_date"`
+	AppLayersPreserved bool   `json:"app_layers_preserved"`
+}
+
+// RebaseResult describes the image a successful rebase produced.
+type RebaseResult struct {
+	ImageRef string // canonical, tag-qualified name of the rebased image
+	Digest   string // the image's identifier: a digest reference when published, an image ID otherwise
+}
+
+// IncompatibleStackError reports a rebase that would combine images from
+// different stacks; the resulting image would not run.
+type IncompatibleStackError struct {
+	AppStack      string
+	RunImageStack string
+}
+
+func (e IncompatibleStackError) Error() string {
+	return fmt.Sprintf("incompatible stack: %s is not compatible with %s", style.Symbol(e.RunImageStack), style.Symbol(e.AppStack))
+}
+
+// rebaseTarget holds everything Rebase and PlanRebase need after resolving
+// the app image, its metadata and the new base image.
+type rebaseTarget struct {
+	appImage     imgutil.Image
+	baseImage    imgutil.Image
+	md           lifecycle.LayersMetadataCompat
+	runImageName string
 }
 
 func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
+	_, err := c.rebase(ctx, opts)
+	return err
+}
+
+// RebaseWithResult runs Rebase and reports the rebased image's canonical
+// reference and digest, for callers embedding pack as a library rather than
+// reading the CLI's log output.
+func (c *Client) RebaseWithResult(ctx context.Context, opts RebaseOptions) (*RebaseResult, error) {
+	return c.rebase(ctx, opts)
+}
+
+func (c *Client) rebase(ctx context.Context, opts RebaseOptions) (*RebaseResult, error) {
 	imageRef, err := c.parseTagReference(opts.RepoName)
 	if err != nil {
-		return errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
+	}
+
+	additionalTags := make([]string, 0, len(opts.AdditionalTags))
+	for _, tag := range opts.AdditionalTags {
+		tagRef, err := c.parseTagReference(tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid additional tag '%s'", tag)
+		}
+		additionalTags = append(additionalTags, tagRef.Name())
+	}
+
+	target, err := c.prepareRebase(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, !opts.SkipPull)
+	if err := validateRebaseStacks(target.appImage, target.baseImage); err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(target.appImage.Name()), style.Symbol(target.baseImage.Name()))
+	rebaser := &lifecycle.Rebaser{Logger: c.logger}
+	// the rebased image is saved once with every name, so all tags share its manifest
+	if err := rebaser.Rebase(target.appImage, target.baseImage, additionalTags); err != nil {
+		return nil, err
+	}
+
+	appImageIdentifier, err := target.appImage.Identifier()
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
+	return &RebaseResult{ImageRef: imageRef.Name(), Digest: appImageIdentifier.String()}, nil
+}
+
+// validateRebaseStacks surfaces a stack mismatch as a typed error before the
+// lifecycle's rebaser runs, so API callers can react to it programmatically.
+// Images without a stack label fall through to the rebaser's own checks.
+func validateRebaseStacks(appImage, baseImage imgutil.Image) error {
+	appStackID, err := appImage.Label(lifecycle.StackIDLabel)
 	if err != nil {
 		return err
 	}
+	baseStackID, err := baseImage.Label(lifecycle.StackIDLabel)
+	if err != nil {
+		return err
+	}
+	if appStackID != "" && baseStackID != "" && !stack.IDsMatch(appStackID, baseStackID) {
+		return IncompatibleStackError{AppStack: appStackID, RunImageStack: baseStackID}
+	}
+	return nil
+}
+
+// PlanRebase resolves the run image the same way Rebase would and describes
+// the rebase without performing it.
+func (c *Client) PlanRebase(ctx context.Context, opts RebaseOptions) (RebasePlan, error) {
+	target, err := c.prepareRebase(ctx, opts)
+	if err != nil {
+		return RebasePlan{}, err
+	}
+
+	baseIdentifier, err := target.baseImage.Identifier()
+	if err != nil {
+		return RebasePlan{}, err
+	}
+
+	targetTopLayer, err := target.baseImage.TopLayer()
+	if err != nil {
+		return RebasePlan{}, err
+	}
+
+	appStackID, err := target.appImage.Label(lifecycle.StackIDLabel)
+	if err != nil {
+		return RebasePlan{}, err
+	}
+
+	baseStackID, err := target.baseImage.Label(lifecycle.StackIDLabel)
+	if err != nil {
+		return RebasePlan{}, err
+	}
+
+	return RebasePlan{
+		RunImage:           target.runImageName,
+		CurrentBase:        target.md.RunImage.Reference,
+		TargetBase:         baseIdentifier.String(),
+		BaseUpToDate:       target.md.RunImage.TopLayer == targetTopLayer,
+		AppLayersPreserved: appStackID != "" && appStackID == baseStackID,
+	}, nil
+}
+
+func (c *Client) prepareRebase(ctx context.Context, opts RebaseOptions) (rebaseTarget, error) {
+	imageRef, err := c.parseTagReference(opts.RepoName)
+	if err != nil {
+		return rebaseTarget{}, errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
+	}
+
+	pullPolicy := pullPolicyForNoPull(opts.SkipPull)
+	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, pullPolicy)
+	if err != nil {
+		return rebaseTarget{}, err
+	}
 
 	var md lifecycle.LayersMetadataCompat
 	if ok, err := dist.GetLabel(appImage, lifecycle.LayerMetadataLabel, &md); err != nil {
-		return err
+		return rebaseTarget{}, err
 	} else if !ok {
-		return errors.Errorf("could not find label %s on image", style.Symbol(lifecycle.LayerMetadataLabel))
+		return rebaseTarget{}, errors.Errorf("could not find label %s on image", style.Symbol(lifecycle.LayerMetadataLabel))
+	}
+
+	if candidates := splitRunImageList(opts.RunImage); len(candidates) > 1 {
+		return c.prepareRebaseWithFallbacks(ctx, opts, appImage, md, candidates)
 	}
 
 	runImageName := c.resolveRunImage(
@@ -49,26 +201,48 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 		opts.AdditionalMirrors)
 
 	if runImageName == "" {
-		return errors.New("run image must be specified")
+		return rebaseTarget{}, errors.New("run image must be specified")
 	}
 
-	baseImage, err := c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, !opts.SkipPull)
+	baseImage, err := c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, pullPolicy)
 	if err != nil {
-		return err
+		return rebaseTarget{}, err
 	}
 
-	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
-	rebaser := &lifecycle.Rebaser{Logger: c.logger}
-	err = rebaser.Rebase(appImage, baseImage, nil)
-	if err != nil {
-		return err
-	}
+	return rebaseTarget{
+		appImage:     appImage,
+		baseImage:    baseImage,
+		md:           md,
+		runImageName: runImageName,
+	}, nil
+}
 
-	appImageIdentifier, err := appImage.Identifier()
-	if err != nil {
-		return err
-	}
+// prepareRebaseWithFallbacks tries each run image candidate in order,
+// selecting the first one that is fetchable and on the app image's stack.
+func (c *Client) prepareRebaseWithFallbacks(ctx context.Context, opts RebaseOptions, appImage imgutil.Image, md lifecycle.LayersMetadataCompat, candidates []string) (rebaseTarget, error) {
+	pullPolicy := pullPolicyForNoPull(opts.SkipPull)
 
-	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
-	return nil
+	var lastErr error
+	for _, candidate := range candidates {
+		baseImage, err := c.imageFetcher.Fetch(ctx, candidate, !opts.Publish, pullPolicy)
+		if err != nil {
+			c.logger.Warnf("Run image %s is not usable: %s", style.Symbol(candidate), err)
+			lastErr = err
+			continue
+		}
+		if err := validateRebaseStacks(appImage, baseImage); err != nil {
+			c.logger.Warnf("Run image %s is not usable: %s", style.Symbol(candidate), err)
+			lastErr = err
+			continue
+		}
+
+		c.logger.Infof("Selected run image %s", style.Symbol(candidate))
+		return rebaseTarget{
+			appImage:     appImage,
+			baseImage:    baseImage,
+			md:           md,
+			runImageName: candidate,
+		}, nil
+	}
+	return rebaseTarget{}, lastErr
 }
@@ -88,6 +88,82 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 						h.AssertContains(t, lbl, `"runImage":{"topLayer":"custom-base-top-layer-sha","reference":"custom-base-digest"`)
 					})
 				})
+
+				when("the run image is a comma-separated fallback list", func() {
+					var fakeCustomRunImage *fakes.Image
+
+					it.Before(func() {
+						fakeCustomRunImage = fakes.NewImage("custom/run", "custom-base-top-layer-sha", &fakeIdentifier{name: "custom-base-digest"})
+						h.AssertNil(t, fakeCustomRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic"))
+						fakeImageFetcher.LocalImages["custom/run"] = fakeCustomRunImage
+					})
+
+					it.After(func() {
+						fakeCustomRunImage.Cleanup()
+					})
+
+					it("uses the first candidate that is pullable and on the app's stack", func() {
+						h.AssertNil(t, subject.Rebase(context.TODO(),
+							RebaseOptions{
+								RunImage: "missing/run,custom/run",
+								RepoName: "some/app",
+							}))
+						h.AssertEq(t, fakeAppImage.Base(), "custom/run")
+						h.AssertContains(t, out.String(), "Run image 'missing/run' is not usable")
+						h.AssertContains(t, out.String(), "Selected run image 'custom/run'")
+					})
+
+					it("skips candidates from a different stack", func() {
+						otherStackImage := fakes.NewImage("other-stack/run", "other-top-layer-sha", &fakeIdentifier{name: "other-digest"})
+						defer otherStackImage.Cleanup()
+						h.AssertNil(t, otherStackImage.SetLabel("io.buildpacks.stack.id", "other.stack"))
+						fakeImageFetcher.LocalImages["other-stack/run"] = otherStackImage
+
+						h.AssertNil(t, subject.Rebase(context.TODO(),
+							RebaseOptions{
+								RunImage: "other-stack/run,custom/run",
+								RepoName: "some/app",
+							}))
+						h.AssertEq(t, fakeAppImage.Base(), "custom/run")
+						h.AssertContains(t, out.String(), "Run image 'other-stack/run' is not usable")
+					})
+
+					it("errors when no candidate is usable", func() {
+						err := subject.Rebase(context.TODO(),
+							RebaseOptions{
+								RunImage: "missing/run,also-missing/run",
+								RepoName: "some/app",
+							})
+						h.AssertError(t, err, "does not exist")
+					})
+				})
+
+				when("the run image is pinned to a digest", func() {
+					const digestName = "custom/run@sha256:90e0a26a2d7ca2a2d10c1ae21cc8912fcf4e0e2c7ba68f40a0a0a26ea8b09f2f"
+
+					var fakeDigestRunImage *fakes.Image
+
+					it.Before(func() {
+						fakeDigestRunImage = fakes.NewImage(digestName, "digest-base-top-layer-sha", &fakeIdentifier{name: digestName})
+						h.AssertNil(t, fakeDigestRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic"))
+						fakeImageFetcher.LocalImages[digestName] = fakeDigestRunImage
+					})
+
+					it.After(func() {
+						fakeDigestRunImage.Cleanup()
+					})
+
+					it("records the digest in the stack metadata, skipping mirror selection", func() {
+						h.AssertNil(t, subject.Rebase(context.TODO(),
+							RebaseOptions{
+								RunImage: digestName,
+								RepoName: "some/app",
+							}))
+						h.AssertEq(t, fakeAppImage.Base(), digestName)
+						lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
+						h.AssertContains(t, lbl, `"runImage":{"topLayer":"digest-base-top-layer-sha","reference":"`+digestName+`"`)
+					})
+				})
 			})
 
 			when("run image is NOT provided by the user", func() {
@@ -158,6 +234,99 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("#PlanRebase", func() {
+				it("describes the rebase without mutating the image", func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"old-top-layer-sha","reference":"old-base-digest"},"stack":{"runImage":{"image":"some/run"}}}`))
+
+					plan, err := subject.PlanRebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, plan.RunImage, "some/run")
+					h.AssertEq(t, plan.CurrentBase, "old-base-digest")
+					h.AssertEq(t, plan.TargetBase, "run-image-digest")
+					h.AssertEq(t, plan.BaseUpToDate, false)
+					h.AssertEq(t, plan.AppLayersPreserved, true)
+
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					h.AssertFalse(t, fakeAppImage.IsSaved())
+				})
+
+				it("reports an up-to-date base", func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"run-image-top-layer-sha","reference":"run-image-digest"},"stack":{"runImage":{"image":"some/run"}}}`))
+
+					plan, err := subject.PlanRebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, plan.BaseUpToDate, true)
+				})
+
+				it("reports when app layers would not be preserved", func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.other"))
+
+					plan, err := subject.PlanRebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, plan.AppLayersPreserved, false)
+				})
+			})
+
+			when("the app and run image stacks do not match", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.other"))
+				})
+
+				it("returns an IncompatibleStackError without mutating the image", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertError(t, err, "incompatible stack: 'io.buildpacks.stacks.other' is not compatible with 'io.buildpacks.stacks.bionic'")
+
+					stackErr, ok := err.(IncompatibleStackError)
+					h.AssertEq(t, ok, true)
+					h.AssertEq(t, stackErr.AppStack, "io.buildpacks.stacks.bionic")
+					h.AssertEq(t, stackErr.RunImageStack, "io.buildpacks.stacks.other")
+
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					h.AssertFalse(t, fakeAppImage.IsSaved())
+				})
+			})
+
+			when("#RebaseWithResult", func() {
+				it("returns the rebased image reference and digest", func() {
+					result, err := subject.RebaseWithResult(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+					h.AssertEq(t, result.ImageRef, "index.docker.io/some/app:latest")
+					h.AssertEq(t, result.Digest, "app-image")
+				})
+			})
+
+			when("additional tags are provided", func() {
+				it("saves the rebased image under each tag", func() {
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName:       "some/app",
+						AdditionalTags: []string{"some/app:stable", "example.com/some/app:v1"},
+					}))
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+					h.AssertSliceContains(t, fakeAppImage.SavedNames(), "some/app", "index.docker.io/some/app:stable", "example.com/some/app:v1")
+				})
+
+				it("errors for an invalid tag", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName:       "some/app",
+						AdditionalTags: []string{"some/app::"},
+					})
+					h.AssertError(t, err, "invalid additional tag 'some/app::'")
+				})
+			})
+
 			when("publish", func() {
 				var (
 					fakeRemoteRunImage *fakes.Image
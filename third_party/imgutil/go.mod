@@ -0,0 +1,16 @@
+module github.com/buildpacks/imgutil
+
+require (
+	github.com/docker/distribution v2.7.1+incompatible // indirect
+	github.com/docker/docker v1.4.2-0.20190924003213-a8608b5b67c7
+	github.com/docker/go-connections v0.4.0
+	github.com/gogo/protobuf v1.2.1 // indirect
+	github.com/google/go-cmp v0.2.0
+	github.com/google/go-containerregistry v0.0.0-20191018211754-b77a90c667af
+	github.com/pkg/errors v0.8.1
+	github.com/sclevine/spec v1.0.0
+	golang.org/x/sync v0.0.0-20190423024810-112230192c58
+	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4 // indirect
+)
+
+go 1.13
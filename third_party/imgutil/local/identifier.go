@@ -0,0 +1,9 @@
+package local
+
+type IDIdentifier struct {
+	ImageID string
+}
+
+func (i IDIdentifier) String() string {
+	return i.ImageID
+}
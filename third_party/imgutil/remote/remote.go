@@ -0,0 +1,466 @@
+package remote
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/imgutil"
+)
+
+type Image struct {
+	keychain   authn.Keychain
+	repoName   string
+	image      v1.Image
+	prevLayers []v1.Layer
+	transport  http.RoundTripper
+}
+
+type ImageOption func(*Image) (*Image, error)
+
+// WithTransport overrides the http.RoundTripper registry requests for this
+// image are made over. A nil transport keeps http.DefaultTransport. It must
+// precede options that contact the registry, like FromBaseImage.
+func WithTransport(transport http.RoundTripper) ImageOption {
+	return func(r *Image) (*Image, error) {
+		r.transport = transport
+		return r, nil
+	}
+}
+
+func WithPreviousImage(imageName string) ImageOption {
+	return func(r *Image) (*Image, error) {
+		var err error
+
+		prevImage, err := newV1Image(r.keychain, imageName, r.roundTripper())
+		if err != nil {
+			return nil, err
+		}
+
+		prevLayers, err := prevImage.Layers()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get layers for previous image with repo name '%s'", imageName)
+		}
+
+		r.prevLayers = prevLayers
+		return r, nil
+	}
+}
+
+func FromBaseImage(imageName string) ImageOption {
+	return func(r *Image) (*Image, error) {
+		var err error
+		r.image, err = newV1Image(r.keychain, imageName, r.roundTripper())
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+func NewImage(repoName string, keychain authn.Keychain, ops ...ImageOption) (imgutil.Image, error) {
+	image, err := emptyImage()
+	if err != nil {
+		return nil, err
+	}
+
+	ri := &Image{
+		keychain: keychain,
+		repoName: repoName,
+		image:    image,
+	}
+
+	for _, op := range ops {
+		ri, err = op(ri)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ri, nil
+}
+
+func newV1Image(keychain authn.Keychain, repoName string, roundTripper http.RoundTripper) (v1.Image, error) {
+	ref, auth, err := referenceForRepoName(keychain, repoName)
+	if err != nil {
+		return nil, err
+	}
+	image, err := remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(roundTripper))
+	if err != nil {
+		if transportErr, ok := err.(*transport.Error); ok && len(transportErr.Errors) > 0 {
+			switch transportErr.Errors[0].Code {
+			case transport.UnauthorizedErrorCode, transport.ManifestUnknownErrorCode:
+				return emptyImage()
+			}
+		}
+		return nil, fmt.Errorf("connect to repo store '%s': %s", repoName, err.Error())
+	}
+	return image, nil
+}
+
+func emptyImage() (v1.Image, error) {
+	cfg := &v1.ConfigFile{
+		Architecture: "amd64",
+		OS:           "linux",
+		RootFS: v1.RootFS{
+			Type:    "layers",
+			DiffIDs: []v1.Hash{},
+		},
+	}
+	return mutate.ConfigFile(empty.Image, cfg)
+}
+
+func referenceForRepoName(keychain authn.Keychain, ref string) (name.Reference, authn.Authenticator, error) {
+	var auth authn.Authenticator
+	r, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	auth, err = keychain.Resolve(r.Context().Registry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, auth, nil
+}
+
+func (i *Image) roundTripper() http.RoundTripper {
+	if i.transport != nil {
+		return i.transport
+	}
+	return http.DefaultTransport
+}
+
+func (i *Image) Label(key string) (string, error) {
+	cfg, err := i.image.ConfigFile()
+	if err != nil || cfg == nil {
+		return "", fmt.Errorf("failed to get config file for image '%s'", i.repoName)
+	}
+	labels := cfg.Config.Labels
+	return labels[key], nil
+
+}
+
+func (i *Image) Env(key string) (string, error) {
+	cfg, err := i.image.ConfigFile()
+	if err != nil || cfg == nil {
+		return "", fmt.Errorf("failed to get config file for image '%s'", i.repoName)
+	}
+	for _, envVar := range cfg.Config.Env {
+		parts := strings.Split(envVar, "=")
+		if parts[0] == key {
+			return parts[1], nil
+		}
+	}
+	return "", nil
+}
+
+func (i *Image) Rename(name string) {
+	i.repoName = name
+}
+
+func (i *Image) Name() string {
+	return i.repoName
+}
+
+func (i *Image) Found() bool {
+	ref, auth, err := referenceForRepoName(i.keychain, i.repoName)
+	if err != nil {
+		return false
+	}
+	_, err = remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(i.roundTripper()))
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+func (i *Image) Identifier() (imgutil.Identifier, error) {
+	ref, err := name.ParseReference(i.repoName, name.WeakValidation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reference for image '%s': %s", i.repoName, err)
+	}
+
+	hash, err := i.image.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest for image '%s': %s", i.repoName, err)
+	}
+
+	digestRef, err := name.NewDigest(fmt.Sprintf("%s@%s", ref.Context().Name(), hash.String()), name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating digest reference")
+	}
+
+	return DigestIdentifier{
+		Digest: digestRef,
+	}, nil
+}
+
+func (i *Image) CreatedAt() (time.Time, error) {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get createdAt time for image '%s': %s", i.repoName, err)
+	}
+	return configFile.Created.UTC(), nil
+}
+
+func (i *Image) Rebase(baseTopLayer string, newBase imgutil.Image) error {
+	newBaseRemote, ok := newBase.(*Image)
+	if !ok {
+		return errors.New("expected new base to be a remote image")
+	}
+
+	newImage, err := mutate.Rebase(i.image, &subImage{img: i.image, topDiffID: baseTopLayer}, newBaseRemote.image)
+	if err != nil {
+		return errors.Wrap(err, "rebase")
+	}
+	i.image = newImage
+	return nil
+}
+
+func (i *Image) SetLabel(key, val string) error {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config := *configFile.Config.DeepCopy()
+	if config.Labels == nil {
+		config.Labels = map[string]string{}
+	}
+	config.Labels[key] = val
+	i.image, err = mutate.Config(i.image, config)
+	return err
+}
+
+func (i *Image) SetEnv(key, val string) error {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config := *configFile.Config.DeepCopy()
+	for idx, e := range config.Env {
+		parts := strings.Split(e, "=")
+		if parts[0] == key {
+			config.Env[idx] = fmt.Sprintf("%s=%s", key, val)
+			i.image, err = mutate.Config(i.image, config)
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+	}
+	config.Env = append(config.Env, fmt.Sprintf("%s=%s", key, val))
+	i.image, err = mutate.Config(i.image, config)
+	return err
+}
+
+func (i *Image) SetWorkingDir(dir string) error {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config := *configFile.Config.DeepCopy()
+	config.WorkingDir = dir
+	i.image, err = mutate.Config(i.image, config)
+	return err
+}
+
+func (i *Image) SetEntrypoint(ep ...string) error {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config := *configFile.Config.DeepCopy()
+	config.Entrypoint = ep
+	i.image, err = mutate.Config(i.image, config)
+	return err
+}
+
+func (i *Image) SetCmd(cmd ...string) error {
+	configFile, err := i.image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config := *configFile.Config.DeepCopy()
+	config.Cmd = cmd
+	i.image, err = mutate.Config(i.image, config)
+	return err
+}
+
+func (i *Image) TopLayer() (string, error) {
+	all, err := i.image.Layers()
+	if err != nil {
+		return "", err
+	}
+	if len(all) == 0 {
+		return "", fmt.Errorf("image %s has no layers", i.Name())
+	}
+	topLayer := all[len(all)-1]
+	hex, err := topLayer.DiffID()
+	if err != nil {
+		return "", err
+	}
+	return hex.String(), nil
+}
+
+func (i *Image) GetLayer(sha string) (io.ReadCloser, error) {
+	layers, err := i.image.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := findLayerWithSha(layers, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	return layer.Uncompressed()
+}
+
+func (i *Image) AddLayer(path string) error {
+	layer, err := tarball.LayerFromFile(path, tarball.WithCompressionLevel(gzip.DefaultCompression))
+	if err != nil {
+		return err
+	}
+	i.image, err = mutate.AppendLayers(i.image, layer)
+	if err != nil {
+		return errors.Wrap(err, "add layer")
+	}
+	return nil
+}
+
+func (i *Image) ReuseLayer(sha string) error {
+	layer, err := findLayerWithSha(i.prevLayers, sha)
+	if err != nil {
+		return err
+	}
+	i.image, err = mutate.AppendLayers(i.image, layer)
+	return err
+}
+
+func findLayerWithSha(layers []v1.Layer, diffID string) (v1.Layer, error) {
+	for _, layer := range layers {
+		dID, err := layer.DiffID()
+		if err != nil {
+			return nil, errors.Wrap(err, "get diff ID for previous image layer")
+		}
+		if diffID == dID.String() {
+			return layer, nil
+		}
+	}
+	return nil, fmt.Errorf(`previous image did not have layer with diff id '%s'`, diffID)
+}
+
+func (i *Image) Save(additionalNames ...string) error {
+	var err error
+
+	allNames := append([]string{i.repoName}, additionalNames...)
+
+	i.image, err = mutate.CreatedAt(i.image, v1.Time{Time: imgutil.NormalizedDateTime})
+	if err != nil {
+		return errors.Wrap(err, "set creation time")
+	}
+
+	cfg, err := i.image.ConfigFile()
+	if err != nil {
+		return errors.Wrap(err, "get image config")
+	}
+	cfg = cfg.DeepCopy()
+
+	layers, err := i.image.Layers()
+	if err != nil {
+		return errors.Wrap(err, "get image layers")
+	}
+	cfg.History = make([]v1.History, len(layers))
+	for i, _ := range cfg.History {
+		cfg.History[i] = v1.History{
+			Created: v1.Time{Time: imgutil.NormalizedDateTime},
+		}
+	}
+
+	cfg.DockerVersion = ""
+	cfg.Container = ""
+	i.image, err = mutate.ConfigFile(i.image, cfg)
+	if err != nil {
+		return errors.Wrap(err, "zeroing history")
+	}
+
+	var diagnostics []imgutil.SaveDiagnostic
+	for _, n := range allNames {
+		if err := i.doSave(n); err != nil {
+			diagnostics = append(diagnostics, imgutil.SaveDiagnostic{ImageName: n, Cause: err})
+		}
+	}
+	if len(diagnostics) > 0 {
+		return imgutil.SaveError{Errors: diagnostics}
+	}
+
+	return nil
+}
+
+func (i *Image) doSave(imageName string) error {
+	ref, auth, err := referenceForRepoName(i.keychain, imageName)
+	if err != nil {
+		return err
+	}
+	return remote.Write(ref, i.image, remote.WithAuth(auth), remote.WithTransport(i.roundTripper()))
+}
+
+func (i *Image) Delete() error {
+	id, err := i.Identifier()
+	if err != nil {
+		return err
+	}
+	ref, auth, err := referenceForRepoName(i.keychain, id.String())
+	if err != nil {
+		return err
+	}
+	return remote.Delete(ref, remote.WithAuth(auth), remote.WithTransport(i.roundTripper()))
+}
+
+type subImage struct {
+	img       v1.Image
+	topDiffID string
+}
+
+func (si *subImage) Layers() ([]v1.Layer, error) {
+	all, err := si.img.Layers()
+	if err != nil {
+		return nil, err
+	}
+	for i, l := range all {
+		d, err := l.DiffID()
+		if err != nil {
+			return nil, err
+		}
+		if d.String() == si.topDiffID {
+			return all[0 : i+1], nil
+		}
+	}
+	return nil, errors.New("could not find base layer in image")
+}
+func (si *subImage) BlobSet() (map[v1.Hash]struct{}, error)  { panic("Not Implemented") }
+func (si *subImage) MediaType() (types.MediaType, error)     { panic("Not Implemented") }
+func (si *subImage) ConfigName() (v1.Hash, error)            { panic("Not Implemented") }
+func (si *subImage) ConfigFile() (*v1.ConfigFile, error)     { panic("Not Implemented") }
+func (si *subImage) RawConfigFile() ([]byte, error)          { panic("Not Implemented") }
+func (si *subImage) Digest() (v1.Hash, error)                { panic("Not Implemented") }
+func (si *subImage) Manifest() (*v1.Manifest, error)         { panic("Not Implemented") }
+func (si *subImage) RawManifest() ([]byte, error)            { panic("Not Implemented") }
+func (si *subImage) LayerByDigest(v1.Hash) (v1.Layer, error) { panic("Not Implemented") }
+func (si *subImage) LayerByDiffID(v1.Hash) (v1.Layer, error) { panic("Not Implemented") }
+func (si *subImage) Size() (int64, error)                    { panic("Not Implemented") }